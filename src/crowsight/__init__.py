@@ -6,8 +6,41 @@ import sys
 
 from .services.analyzer import CodebaseAnalyzer
 from .filters.node_filter import NodeFilter, NodeCategory
+from .analyzers.registry import Analyzer, register_analyzer
+from .report.models import Report, Service, Endpoint, Finding, Severity
+from .scanner.scanner import (
+    GoScanner,
+    ScanEvent,
+    ScanOptions,
+    scan,
+    scan_fs,
+    scan_packages,
+    scan_stream,
+)
+from .scanner.fs import FileSystem, MemoryFileSystem
 
-__all__ = ["CodebaseAnalyzer", "NodeFilter", "NodeCategory"]
+__all__ = [
+    "CodebaseAnalyzer",
+    "NodeFilter",
+    "NodeCategory",
+    "Report",
+    "Service",
+    "Endpoint",
+    "Finding",
+    "Severity",
+    "GoScanner",
+    "ScanOptions",
+    "ScanEvent",
+    "FileSystem",
+    "MemoryFileSystem",
+    "scan",
+    "scan_fs",
+    "scan_packages",
+    "scan_stream",
+    "Analyzer",
+    "register_analyzer",
+    "main",
+]
 
 
 def configure_logger(
@@ -16,15 +49,25 @@ def configure_logger(
     fmt: str = "<green>{time:YYYY-MM-DD HH:mm:ss}</green> | "
     "<level>{level: <8}</level> | {message}",
     sink=None,
+    serialize: bool = False,
 ):
     """
     Configure Loguru for CrowSight globally.
+
+    With `serialize` every record is written as one JSON object per line,
+    including the fields bound to it (file, duration, findings, ...).
     """
     logger.remove()
     sink = sink or sys.stdout
-    logger.add(sink, level=level, format=fmt)
+    logger.add(sink, level=level, format=fmt, serialize=serialize)
     env_level = os.getenv("CROWSIGHT_LOG_LEVEL")
     if env_level:
         logger.remove()
-        logger.add(sink, level=env_level, format=fmt)
+        logger.add(sink, level=env_level, format=fmt, serialize=serialize)
         logger.debug(f"Overriding log level from CROWSIGHT_LOG_LEVEL={env_level}")
+
+
+def main():
+    from .cli import main as cli_main
+
+    sys.exit(cli_main())
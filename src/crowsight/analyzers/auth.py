@@ -0,0 +1,80 @@
+# src/crowsight/analyzers/auth.py
+
+from fnmatch import fnmatchcase
+from typing import List, Sequence
+
+from ..report.models import Endpoint, Finding, Report, Severity
+from ..scanner.package import PackageInfo
+from .common import nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {"missing-auth": "An endpoint has no authentication middleware and is not marked public"}
+
+# Case-insensitive globs matched against each middleware's name.
+DEFAULT_AUTH_MIDDLEWARE = ("*auth*", "*jwt*", "*login*")
+# Route globs that are expected to be reachable without credentials.
+DEFAULT_PUBLIC_PATHS = (
+    "/health",
+    "/health/*",
+    "/healthz",
+    "/livez",
+    "/readyz",
+    "/ready",
+    "/ping",
+    "/metrics",
+    "/favicon.ico",
+)
+
+
+def middleware_matches(middleware: str, patterns: Sequence[str]) -> bool:
+    """Whether `middleware` (as written, e.g. `mw.RequireAuth(cfg)`) matches a name glob.
+
+    A pattern matches the whole text, the called function (`mw.RequireAuth`)
+    or its last component (`RequireAuth`), ignoring case.
+    """
+    func = middleware.split("(", 1)[0].strip()
+    candidates = {middleware.lower(), func.lower(), func.rsplit(".", 1)[-1].lower()}
+    return any(fnmatchcase(c, p.lower()) for p in patterns for c in candidates)
+
+
+def is_public(path: str, patterns: Sequence[str]) -> bool:
+    return any(fnmatchcase(path, p) for p in patterns)
+
+
+class MissingAuthAnalyzer(Analyzer):
+    """Flags HTTP endpoints whose middleware chain has no authentication step."""
+
+    def __init__(
+        self,
+        auth_middleware: Sequence[str] = DEFAULT_AUTH_MIDDLEWARE,
+        public_paths: Sequence[str] = DEFAULT_PUBLIC_PATHS,
+    ):
+        self.auth_middleware = list(auth_middleware) or list(DEFAULT_AUTH_MIDDLEWARE)
+        self.public_paths = list(public_paths)
+
+    def unprotected(self, ep: Endpoint) -> bool:
+        if ep.protocol != "http" or is_public(ep.path, self.public_paths):
+            return False
+        return not any(middleware_matches(m, self.auth_middleware) for m in ep.middleware)
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        svc = package_service(pkg, report)
+        if svc is None:
+            return findings
+        suppressed = {f.rel: nolint_lines(f) for f in pkg.files}
+        for ep in svc.endpoints:
+            if not self.unprotected(ep) or ep.line in suppressed.get(ep.file, ()):
+                continue
+            chain = ", ".join(ep.middleware) if ep.middleware else "none"
+            findings.append(
+                Finding(
+                    code="missing-auth",
+                    severity=Severity.WARNING,
+                    message=f"{ep.method} {ep.path} has no authentication middleware "
+                    f"(middleware: {chain}) and is not listed as public",
+                    file=ep.file,
+                    line=ep.line,
+                )
+            )
+        return findings
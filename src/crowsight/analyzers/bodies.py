@@ -0,0 +1,129 @@
+# src/crowsight/analyzers/bodies.py
+
+import re
+from typing import Dict, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Endpoint, Finding, Report, Severity
+from ..scanner.goast import call_args, call_target, walk_body
+from ..scanner.handlers import func_params, iter_handlers
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {"unbounded-body": "A handler reads the request body without a size limit"}
+
+# Calls that read (or wrap for reading) everything in their first argument.
+BODY_READERS = {
+    "json.NewDecoder",
+    "xml.NewDecoder",
+    "yaml.NewDecoder",
+    "gob.NewDecoder",
+    "io.ReadAll",
+    "ioutil.ReadAll",
+}
+DECODE_METHODS = ("Decode",)
+# echo's middleware.BodyLimit, chi-style MaxBytes/RequestSize helpers, ...
+LIMIT_MIDDLEWARE = re.compile(r"body.?limit|max.?bytes|request.?size|size.?limit", re.IGNORECASE)
+
+
+def body_expressions(fn: NodeWrapper) -> Dict[str, str]:
+    """How the handler spells its request body (`r.Body`) -> its ResponseWriter (`w`)."""
+    params = [(n, t) for n, t in func_params(fn) if n and n != "_"]
+    writer = next((n for n, t in params if t == "http.ResponseWriter"), "w")
+    found: Dict[str, str] = {}
+    for name, typ in params:
+        if typ == "*http.Request":
+            found[f"{name}.Body"] = writer
+        elif typ == "*gin.Context":
+            found[f"{name}.Request.Body"] = f"{name}.Writer"
+        elif typ == "echo.Context":
+            found[f"{name}.Request().Body"] = f"{name}.Response()"
+    return found
+
+
+def limited_lines(fn: NodeWrapper, bodies: Dict[str, str]) -> List[int]:
+    """Lines where `r.Body = http.MaxBytesReader(...)` (or io.LimitReader) replaces the body."""
+    lines: List[int] = []
+    for node in walk_body(fn):
+        if node.type != "assignment_statement":
+            continue
+        left, right = node.field("left"), node.field("right")
+        if left is None or right is None or len(left.named_children) != 1:
+            continue
+        value = right.named_children[0] if right.named_children else None
+        if left.named_children[0].text not in bodies or value is None:
+            continue
+        if value.type == "call_expression" and call_target(value)[1] in (
+            "MaxBytesReader",
+            "LimitReader",
+        ):
+            lines.append(node.line)
+    return lines
+
+
+def limited_by_middleware(endpoints: List[Endpoint]) -> bool:
+    return bool(endpoints) and all(
+        any(LIMIT_MIDDLEWARE.search(m) for m in ep.middleware) for ep in endpoints
+    )
+
+
+class UnboundedBodyAnalyzer(Analyzer):
+    """Flags decoding or reading the request body with no http.MaxBytesReader in front.
+
+    The body counts as limited when the handler replaces it
+    (`r.Body = http.MaxBytesReader(w, r.Body, n)`) before reading, reads a
+    wrapped reader instead of `r.Body`, or every route to the handler passes
+    through body-limit middleware. Server.MaxHeaderBytes is not a guard: it
+    caps the request headers only.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        svc = package_service(pkg, report)
+        by_handler: Dict[Tuple[str, int], List[Endpoint]] = {}
+        for ep in svc.endpoints if svc is not None else []:
+            if ep.handler_file:
+                by_handler.setdefault((ep.handler_file, ep.handler_line), []).append(ep)
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            bodies = body_expressions(fn)
+            suppressed = nolint_lines(f)
+            if not bodies or fn.line in suppressed:
+                continue
+            if limited_by_middleware(by_handler.get((f.rel, fn.line), [])):
+                continue
+            limits = limited_lines(fn, bodies)
+            for call in walk_body(fn):
+                if call.type != "call_expression" or call.line in suppressed:
+                    continue
+                fn_text = call.field("function").text
+                args = call_args(call)
+                if fn_text not in BODY_READERS or not args or args[0].text not in bodies:
+                    continue
+                if any(line < call.line for line in limits):
+                    continue
+                site = self._decode_call(call) or call
+                body, writer = args[0].text, bodies[args[0].text]
+                findings.append(
+                    finding(
+                        "unbounded-body",
+                        Severity.WARNING,
+                        f"{fn_text}({body}) reads an unbounded request body; limit it first, "
+                        f"e.g. {body} = http.MaxBytesReader({writer}, {body}, 1<<20)",
+                        f,
+                        site,
+                    )
+                )
+        return findings
+
+    @staticmethod
+    def _decode_call(reader: NodeWrapper) -> Optional[NodeWrapper]:
+        """The `.Decode(...)` call in `json.NewDecoder(r.Body).Decode(&v)`."""
+        sel = reader.parent
+        if sel is None or sel.type != "selector_expression":
+            return None
+        field = sel.field("field")
+        outer = sel.parent
+        if field is None or field.text not in DECODE_METHODS or outer is None:
+            return None
+        return outer if outer.type == "call_expression" else None
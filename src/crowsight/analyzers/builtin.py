@@ -0,0 +1,63 @@
+# src/crowsight/analyzers/builtin.py
+
+from .auth import MissingAuthAnalyzer
+from .bodies import UnboundedBodyAnalyzer
+from .complexity import ComplexityAnalyzer
+from .contenttype import MissingContentTypeAnalyzer
+from .contexts import DetachedContextAnalyzer
+from .deprecated import DeprecatedUsageAnalyzer
+from .duplicates import DuplicateRouteAnalyzer
+from .goroutines import GoroutineLeakAnalyzer
+from .imports import ImportAnalyzer
+from .jsontags import MissingJSONTagAnalyzer
+from .observability import ObservabilityAnalyzer
+from .panics import UnrecoveredPanicAnalyzer
+from .params import UnusedParamAnalyzer
+from .ports import PortConflictAnalyzer
+from .queries import NPlusOneAnalyzer
+from .registry import register_analyzer, register_analyzer_factory
+from .response import MissingResponseAnalyzer
+from .secrets import HardcodedSecretAnalyzer
+from .slashes import TrailingSlashAnalyzer
+from .tls import TLSAnalyzer
+from .unchecked import UncheckedErrorAnalyzer
+from .webhooks import WebhookIdempotencyAnalyzer
+
+
+def register_builtins():
+    """Register the analyzers that ship with crowsight."""
+    register_analyzer("unchecked", UncheckedErrorAnalyzer())
+    register_analyzer_factory(
+        "complexity", lambda options: ComplexityAnalyzer(options.max_complexity)
+    )
+    register_analyzer("duplicates", DuplicateRouteAnalyzer())
+    register_analyzer("params", UnusedParamAnalyzer())
+    register_analyzer("response", MissingResponseAnalyzer())
+    register_analyzer("panics", UnrecoveredPanicAnalyzer())
+    register_analyzer("queries", NPlusOneAnalyzer())
+    register_analyzer("tls", TLSAnalyzer())
+    register_analyzer("context", DetachedContextAnalyzer())
+    register_analyzer("bodylimit", UnboundedBodyAnalyzer())
+    register_analyzer("goroutines", GoroutineLeakAnalyzer())
+    register_analyzer_factory(
+        "secrets", lambda options: HardcodedSecretAnalyzer(options.secret_allowlist)
+    )
+    register_analyzer("deprecated", DeprecatedUsageAnalyzer())
+    register_analyzer("contenttype", MissingContentTypeAnalyzer())
+    register_analyzer_factory(
+        "imports", lambda options: ImportAnalyzer(options.forbid_imports)
+    )
+    register_analyzer("observability", ObservabilityAnalyzer())
+    register_analyzer("jsontags", MissingJSONTagAnalyzer())
+    register_analyzer("ports", PortConflictAnalyzer())
+    register_analyzer_factory(
+        "webhooks", lambda options: WebhookIdempotencyAnalyzer(options.webhook_patterns)
+    )
+    register_analyzer_factory(
+        "slashes", lambda options: TrailingSlashAnalyzer(options.trailing_slash_paths)
+    )
+    register_analyzer_factory(
+        "auth",
+        lambda options: MissingAuthAnalyzer(options.auth_middleware, options.public_paths),
+        enabled=False,
+    )
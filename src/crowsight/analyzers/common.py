@@ -0,0 +1,39 @@
+# src/crowsight/analyzers/common.py
+
+import re
+from typing import Set
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Severity
+from ..scanner.package import GoFile
+
+_NOLINT_RE = re.compile(r"//\s*nolint(?::([\w,-]+))?")
+
+
+def nolint_lines(f: GoFile) -> Set[int]:
+    """Lines carrying `//nolint` or `//nolint:crowsight` (possibly among others)."""
+    lines: Set[int] = set()
+    for node in f.root.descendants():
+        if node.type != "comment":
+            continue
+        m = _NOLINT_RE.search(node.text)
+        if not m:
+            continue
+        linters = m.group(1)
+        if linters is None or "crowsight" in linters.split(","):
+            lines.add(node.line)
+    return lines
+
+
+def finding(
+    code: str, severity: Severity, message: str, f: GoFile, node: NodeWrapper
+) -> Finding:
+    return Finding(
+        code=code,
+        severity=severity,
+        message=message,
+        file=f.rel,
+        line=node.line,
+        column=node.column,
+        expression=node.text,
+    )
@@ -0,0 +1,43 @@
+# src/crowsight/analyzers/complexity.py
+
+from typing import List
+
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import cyclomatic
+from ..scanner.handlers import iter_handlers
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+DEFAULT_MAX_COMPLEXITY = 15
+
+RULES = {"complexity": "Handler cyclomatic complexity exceeds the configured maximum"}
+
+
+class ComplexityAnalyzer(Analyzer):
+    """Flags handlers whose cyclomatic complexity exceeds a threshold."""
+
+    def __init__(self, max_complexity: int = DEFAULT_MAX_COMPLEXITY):
+        self.max_complexity = max_complexity
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        if self.max_complexity <= 0:
+            return findings
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            score = cyclomatic(fn)
+            if score <= self.max_complexity or fn.line in nolint_lines(f):
+                continue
+            name = fn.field("name")
+            label = name.text if name is not None else "inline handler"
+            found = finding(
+                "complexity",
+                Severity.WARNING,
+                f"{label} has cyclomatic complexity {score} (max {self.max_complexity})",
+                f,
+                fn,
+            )
+            # The whole function body is not a useful "expression".
+            found.expression = None
+            findings.append(found)
+        return findings
@@ -0,0 +1,233 @@
+# src/crowsight/analyzers/contenttype.py
+
+from typing import List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import (
+    call_args,
+    call_target,
+    local_value,
+    string_value,
+    unwrap_element,
+    walk_body,
+)
+from ..scanner.handlers import func_params, iter_handlers
+from ..scanner.package import GoFile, PackageInfo
+from ..scanner.resolve import Decl, HandlerResolver, imported_names
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {
+    "missing-content-type": "A handler writes JSON without setting a JSON Content-Type first"
+}
+
+HEADER = "content-type"
+HEADER_METHODS = ("Set", "Add")
+JSON_MARSHAL = ("json.Marshal", "json.MarshalIndent")
+# How many helper calls deep to look for the header being set.
+MAX_DEPTH = 3
+
+
+def is_json_type(value: str) -> bool:
+    """`application/json` or a `+json` type, parameters (`; charset=utf-8`) aside."""
+    media = value.split(";", 1)[0].strip().lower()
+    return media == "application/json" or media.endswith("+json")
+
+
+def is_header(node: Optional[NodeWrapper], writer: str, scope: NodeWrapper) -> bool:
+    """`w.Header()`, or a variable holding it (`h := w.Header()`)."""
+    if node is not None and node.type == "identifier":
+        node = local_value(scope, node.text)
+    if node is None or node.type != "call_expression" or call_args(node):
+        return False
+    return call_target(node) == (writer, "Header")
+
+
+def content_type(node: NodeWrapper, writer: str, scope: NodeWrapper) -> Optional[bool]:
+    """For a Content-Type assignment on `writer`'s headers, whether it is JSON; else None.
+
+    A value that is not a string literal (a constant, a variable) counts as JSON.
+    """
+    if node.type == "call_expression":
+        fn = node.field("function")
+        if fn is None or fn.type != "selector_expression":
+            return None
+        name = fn.field("field")
+        if name is None or name.text not in HEADER_METHODS:
+            return None
+        if not is_header(fn.field("operand"), writer, scope):
+            return None
+        args = call_args(node)
+        if len(args) != 2 or (string_value(args[0]) or "").lower() != HEADER:
+            return None
+        value = string_value(args[1])
+        return value is None or is_json_type(value)
+    if node.type == "assignment_statement":
+        # `w.Header()["Content-Type"] = []string{"application/json"}`
+        left, right = node.field("left"), node.field("right")
+        targets = left.named_children if left is not None else []
+        values = right.named_children if right is not None else []
+        if len(targets) != 1 or len(values) != 1 or targets[0].type != "index_expression":
+            return None
+        index = targets[0].field("index")
+        if (string_value(index) or "").lower() != HEADER:
+            return None
+        if not is_header(targets[0].field("operand"), writer, scope):
+            return None
+        body = values[0].field("body")
+        items = [unwrap_element(e) for e in body.named_children] if body is not None else []
+        value = string_value(items[0]) if items else None
+        return value is None or is_json_type(value)
+    return None
+
+
+def produces_json(node: Optional[NodeWrapper], scope: NodeWrapper) -> bool:
+    """`json.Marshal(v)`, or a variable assigned from it."""
+    if node is not None and node.type == "identifier":
+        node = local_value(scope, node.text)
+    if node is None or node.type != "call_expression":
+        return False
+    fn = node.field("function")
+    return fn is not None and fn.text in JSON_MARSHAL
+
+
+def writes_json(call: NodeWrapper, writer: str, scope: NodeWrapper) -> bool:
+    """`json.NewEncoder(w).Encode(v)` (or via `enc := ...`), or `w.Write` of marshaled JSON."""
+    receiver, name = call_target(call)
+    if receiver == writer and name == "Write":
+        args = call_args(call)
+        return len(args) == 1 and produces_json(args[0], scope)
+    if name != "Encode":
+        return False
+    fn = call.field("function")
+    encoder = fn.field("operand") if fn is not None else None
+    if encoder is not None and encoder.type == "identifier":
+        encoder = local_value(scope, encoder.text)
+    if encoder is None or encoder.type != "call_expression":
+        return False
+    ctor, args = encoder.field("function"), call_args(encoder)
+    return (
+        ctor is not None
+        and ctor.text == "json.NewEncoder"
+        and len(args) == 1
+        and args[0].type == "identifier"
+        and args[0].text == writer
+    )
+
+
+def writes_header(call: NodeWrapper, writer: str) -> bool:
+    """Calls after which header changes no longer reach the client."""
+    receiver, name = call_target(call)
+    return receiver == writer and name in ("WriteHeader", "Write", "WriteString", "Flush")
+
+
+class MissingContentTypeAnalyzer(Analyzer):
+    """Flags JSON written by net/http handlers before any JSON Content-Type is set.
+
+    A handler's writes are matched, in source order, against
+    `w.Header().Set/Add("Content-Type", ...)` (or the header map) on the same
+    writer, including through package helpers the writer is passed to, a
+    few calls deep. A header set after WriteHeader/Write is too late to
+    count. Headers set by middleware cannot be seen; use `//nolint` there.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        resolver = HandlerResolver(pkg)
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            body = fn.field("body")
+            writer = next((n for n, t in func_params(fn) if t == "http.ResponseWriter"), None)
+            suppressed = nolint_lines(f)
+            if body is None or not writer or writer == "_" or fn.line in suppressed:
+                continue
+            # None until set; True for a JSON type.
+            state: Optional[bool] = None
+            late = written = False
+            for node in walk_body(body):
+                value = content_type(node, writer, fn)
+                if value is None and node.type == "call_expression":
+                    value = self._helper_sets(node, writer, f, fn, resolver, 0)
+                if value is not None:
+                    if written:
+                        late = True
+                    else:
+                        state = value
+                    continue
+                if node.type != "call_expression":
+                    continue
+                if writes_json(node, writer, fn) and node.line not in suppressed and not state:
+                    if late and state is None:
+                        why = "Content-Type is only set after the header was written"
+                    elif state is False:
+                        why = "the Content-Type set is not a JSON type"
+                    else:
+                        why = "no Content-Type is set"
+                    findings.append(
+                        finding(
+                            "missing-content-type",
+                            Severity.WARNING,
+                            f"JSON response written but {why}; call "
+                            f'{writer}.Header().Set("Content-Type", "application/json") '
+                            f"before writing",
+                            f,
+                            node,
+                        )
+                    )
+                if writes_header(node, writer) or writes_json(node, writer, fn):
+                    written = True
+        return findings
+
+    def _helper_sets(
+        self,
+        call: NodeWrapper,
+        writer: str,
+        f: GoFile,
+        scope: NodeWrapper,
+        resolver: HandlerResolver,
+        depth: int,
+    ) -> Optional[bool]:
+        """What a package helper `call` passes `writer` to sets Content-Type to, if anything."""
+        args = call_args(call)
+        index = next(
+            (i for i, a in enumerate(args) if a.type == "identifier" and a.text == writer), None
+        )
+        found = self._callee(call, f, scope, resolver) if index is not None else None
+        if found is None or depth >= MAX_DEPTH:
+            return None
+        hf, helper = found
+        params = func_params(helper)
+        body = helper.field("body")
+        if index >= len(params) or not params[index][0] or body is None:
+            return None
+        name = params[index][0]
+        for node in walk_body(body):
+            value = content_type(node, name, helper)
+            if value is None and node.type == "call_expression":
+                value = self._helper_sets(node, name, hf, helper, resolver, depth + 1)
+            if value is not None:
+                return value
+        return None
+
+    @staticmethod
+    def _callee(
+        call: NodeWrapper, f: GoFile, scope: NodeWrapper, resolver: HandlerResolver
+    ) -> Optional[Decl]:
+        """The package function or method `call` calls, when it can be told."""
+        fn = call.field("function")
+        if fn is None:
+            return None
+        if fn.type == "identifier":
+            return resolver.funcs.get(fn.text)
+        if fn.type != "selector_expression":
+            return None
+        operand, name = fn.field("operand"), fn.field("field")
+        if operand is None or name is None:
+            return None
+        typ = resolver.type_of(operand, scope)
+        if typ is not None:
+            return resolver.methods.get((typ, name.text))
+        if operand.type != "identifier" or operand.text in imported_names(f):
+            return None
+        candidates = resolver.methods_by_name.get(name.text, [])
+        return candidates[0] if len(candidates) == 1 else None
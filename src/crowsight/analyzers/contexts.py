@@ -0,0 +1,99 @@
+# src/crowsight/analyzers/contexts.py
+
+from typing import List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import call_args, call_target, local_value, strip_address_of, walk_body
+from ..scanner.handlers import func_params, iter_handlers
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {
+    "detached-context": "A handler passes context.Background() or context.TODO() "
+    "instead of the request's context"
+}
+
+DETACHED = ("Background", "TODO")
+# context.WithX(parent, ...) contexts are only as attached as their parent.
+DERIVED = ("WithCancel", "WithCancelCause", "WithTimeout", "WithDeadline", "WithValue")
+# How many `ctx := ...` hops to follow back to where a context came from.
+MAX_DEPTH = 4
+
+
+def detached(node: Optional[NodeWrapper], scope: NodeWrapper, depth: int = 0) -> Optional[str]:
+    """`context.Background()` or `context.TODO()` if `node` evaluates to (a child of) one."""
+    if node is None or depth > MAX_DEPTH:
+        return None
+    node = strip_address_of(node)
+    if node.type == "identifier":
+        return detached(local_value(scope, node.text), scope, depth + 1)
+    if node.type != "call_expression":
+        return None
+    receiver, name = call_target(node)
+    if receiver != "context":
+        return None
+    if name in DETACHED:
+        return f"context.{name}()"
+    if name in DERIVED:
+        args = call_args(node)
+        return detached(args[0], scope, depth + 1) if args else None
+    return None
+
+
+def request_context(fn: NodeWrapper) -> str:
+    """How the handler reaches its request's context, for the message."""
+    for name, typ in func_params(fn):
+        if typ == "*http.Request":
+            return f"{name or 'r'}.Context()"
+        if typ == "*gin.Context":
+            return f"{name or 'c'}.Request.Context()"
+        if typ == "echo.Context":
+            return f"{name or 'c'}.Request().Context()"
+        if typ == "*fiber.Ctx":
+            return f"{name or 'c'}.UserContext()"
+    return "the request's context"
+
+
+class DetachedContextAnalyzer(Analyzer):
+    """Flags calls in handlers whose context argument is context.Background() or TODO().
+
+    Contexts are traced through local variables and context.WithX wrappers, so
+    `ctx := r.Context()` passed along is fine while
+    `ctx, cancel := context.WithTimeout(context.Background(), d)` is not.
+    Function literals (goroutines started by the handler) are not checked:
+    work meant to outlive the request is a legitimate use of Background.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            suppressed = nolint_lines(f)
+            if fn.line in suppressed:
+                continue
+            for call in walk_body(fn):
+                if call.type != "call_expression" or call.line in suppressed:
+                    continue
+                receiver, name = call_target(call)
+                args = call_args(call)
+                if receiver == "context" or not args:
+                    continue
+                # Go passes the context first by convention.
+                source = detached(args[0], fn)
+                if source is None:
+                    continue
+                target = call.field("function").text
+                if args[0].type == "identifier":
+                    source = f"{args[0].text} (from {source})"
+                findings.append(
+                    finding(
+                        "detached-context",
+                        Severity.WARNING,
+                        f"{target} is called with {source} inside a handler, losing the "
+                        f"request's cancellation and tracing; pass {request_context(fn)}",
+                        f,
+                        call,
+                    )
+                )
+        return findings
@@ -0,0 +1,267 @@
+# src/crowsight/analyzers/deprecated.py
+
+from typing import Dict, List, Optional, Set, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import enclosing_function, unquote
+from ..scanner.handlers import expr_type, receiver_type
+from ..scanner.package import GoFile, PackageInfo
+from ..scanner.structs import base_type
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {"deprecated-call": "Code uses a function, method or type documented as Deprecated"}
+
+DEPRECATED = "Deprecated:"
+# Standard library deprecations, which no scan can read the doc comments of.
+# "*" deprecates the whole package.
+STDLIB: Dict[str, Dict[str, str]] = {
+    "io/ioutil": {
+        "*": "As of Go 1.16, the same functionality is now provided by package io or "
+        "package os, and those implementations should be preferred in new code."
+    },
+    "strings": {
+        "Title": "The rule Title uses for word boundaries does not handle Unicode "
+        "punctuation properly. Use golang.org/x/text/cases instead."
+    },
+    "bytes": {
+        "Title": "The rule Title uses for word boundaries does not handle Unicode "
+        "punctuation properly. Use golang.org/x/text/cases instead."
+    },
+    "math/rand": {
+        "Seed": "As of Go 1.20 there is no reason to call Seed with a random value.",
+        "Read": "For almost all use cases, crypto/rand.Read is more appropriate.",
+    },
+    "reflect": {
+        "SliceHeader": "Use unsafe.Slice or unsafe.SliceData instead.",
+        "StringHeader": "Use unsafe.String or unsafe.StringData instead.",
+    },
+    "crypto/x509": {
+        "ParseCRL": "Use ParseRevocationList instead.",
+        "ParseDERCRL": "Use ParseRevocationList instead.",
+        "IsEncryptedPEMBlock": "Legacy PEM encryption as specified in RFC 1423 is "
+        "insecure by design.",
+        "DecryptPEMBlock": "Legacy PEM encryption as specified in RFC 1423 is "
+        "insecure by design.",
+        "EncryptPEMBlock": "Legacy PEM encryption as specified in RFC 1423 is "
+        "insecure by design.",
+    },
+    "net/http": {
+        "CloseNotifier": "the CloseNotifier interface predates Go's context package. "
+        "New code should use Request.Context instead.",
+    },
+    "os": {
+        "SEEK_SET": "Use io.SeekStart, io.SeekCurrent, and io.SeekEnd.",
+        "SEEK_CUR": "Use io.SeekStart, io.SeekCurrent, and io.SeekEnd.",
+        "SEEK_END": "Use io.SeekStart, io.SeekCurrent, and io.SeekEnd.",
+    },
+}
+
+
+def comment_lines(comment: NodeWrapper) -> List[str]:
+    text = comment.text
+    if text.startswith("//"):
+        return [text[2:].removeprefix(" ")]
+    return [line.strip().removeprefix("*").strip() for line in text[2:-2].splitlines()]
+
+
+def doc_comment(decl: NodeWrapper) -> List[str]:
+    """Lines of the comment block directly above `decl`, without comment markers."""
+    parent = decl.parent
+    if parent is None:
+        return []
+    siblings = parent.children
+    index = siblings.index(decl)
+    lines: List[str] = []
+    line = decl.line
+    for node in reversed(siblings[:index]):
+        if node.type != "comment" or node.end_line != line - 1:
+            break
+        lines = comment_lines(node) + lines
+        line = node.line
+    return lines
+
+
+def deprecation(lines: List[str]) -> Optional[str]:
+    """The message of a `Deprecated:` paragraph, if the doc comment has one."""
+    paragraph: List[str] = []
+    for line in lines + [""]:
+        if line.strip():
+            paragraph.append(line.strip())
+            continue
+        if paragraph and paragraph[0].startswith(DEPRECATED):
+            return " ".join(paragraph)[len(DEPRECATED) :].strip()
+        paragraph = []
+    return None
+
+
+def declarations(pkg: PackageInfo) -> Dict[str, Tuple[str, GoFile, NodeWrapper]]:
+    """Deprecated names of `pkg` (`Func`, `Type`, `Type.Method`) -> (message, file, decl)."""
+    found: Dict[str, Tuple[str, GoFile, NodeWrapper]] = {}
+    for f in pkg.files:
+        for decl in f.root.named_children:
+            if decl.type in ("function_declaration", "method_declaration"):
+                name = decl.field("name")
+                message = deprecation(doc_comment(decl))
+                if name is None or message is None:
+                    continue
+                owner = receiver_type(decl) if decl.type == "method_declaration" else None
+                key = f"{owner}.{name.text}" if owner else name.text
+                found[key] = (message, f, decl)
+            elif decl.type in ("type_declaration", "const_declaration", "var_declaration"):
+                # A group's doc comment covers every spec; a spec's own comment wins.
+                group = deprecation(doc_comment(decl))
+                for spec in decl.named_children:
+                    if spec.type not in ("type_spec", "type_alias", "const_spec", "var_spec"):
+                        continue
+                    message = deprecation(doc_comment(spec)) or group
+                    if message is None:
+                        continue
+                    for name in spec.fields("name"):
+                        found[name.text] = (message, f, spec)
+    return found
+
+
+def inside(node: NodeWrapper, decls: Set[NodeWrapper]) -> bool:
+    p = node.parent
+    while p is not None:
+        if p in decls:
+            return True
+        p = p.parent
+    return False
+
+
+class DeprecatedUsageAnalyzer(Analyzer):
+    """Flags uses of functions, methods, types, consts and vars documented as Deprecated.
+
+    Deprecations are read from the `Deprecated:` paragraph of doc comments,
+    in the package itself and in the packages it imports from the scanned
+    modules (vendored dependencies included), plus a table of well-known
+    standard library deprecations. Methods are matched when the receiver's
+    type can be read from the code (`c := &Client{}`, parameters, receivers).
+    Uses inside deprecated declarations themselves are not reported.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        local = declarations(pkg)
+        decls = {decl for _, _, decl in local.values()}
+        # The methods of a deprecated type go with it.
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                if receiver_type(decl) in local:
+                    decls.add(decl)
+        imported: Dict[str, Dict[str, Tuple[str, GoFile, NodeWrapper]]] = {}
+        for f in pkg.files:
+            suppressed = nolint_lines(f)
+            stdlib = self._stdlib_imports(f)
+            seen: Set[Tuple[int, int]] = set()
+            for node in f.root.descendants():
+                found = self._use(node, pkg, f, local, imported, stdlib)
+                if found is None or node.line in suppressed:
+                    continue
+                name, message = found
+                if (node.line, node.column) in seen or inside(node, decls):
+                    continue
+                seen.add((node.line, node.column))
+                findings.append(
+                    finding(
+                        "deprecated-call",
+                        Severity.WARNING,
+                        f"{name} is deprecated: {message}",
+                        f,
+                        node,
+                    )
+                )
+        return findings
+
+    def _use(
+        self,
+        node: NodeWrapper,
+        pkg: PackageInfo,
+        f: GoFile,
+        local: Dict[str, Tuple[str, GoFile, NodeWrapper]],
+        imported: Dict[str, Dict[str, Tuple[str, GoFile, NodeWrapper]]],
+        stdlib: Dict[str, str],
+    ) -> Optional[Tuple[str, str]]:
+        """(display name, message) if `node` refers to something deprecated."""
+        if node.type in ("selector_expression", "qualified_type"):
+            operand = node.field("operand") or node.field("package")
+            field = node.field("field") or node.field("name")
+            if operand is None or field is None:
+                return None
+            qualifier, name = operand.text, field.text
+            if operand.type in ("identifier", "package_identifier") and qualifier in stdlib:
+                table = STDLIB.get(stdlib[qualifier], {})
+                message = table.get(name) or table.get("*")
+                return (f"{qualifier}.{name}", message) if message else None
+            target = pkg.resolver.imported(qualifier, f) if pkg.resolver else None
+            if target is not None and operand.type in ("identifier", "package_identifier"):
+                if target.dir not in imported:
+                    imported[target.dir] = declarations(target)
+                decl = imported[target.dir].get(name)
+                return (f"{qualifier}.{name}", decl[0]) if decl else None
+            if node.type == "selector_expression":
+                return self._method(operand, name, pkg, f, local, imported)
+            return None
+        if node.type not in ("identifier", "type_identifier") or not is_reference(node):
+            return None
+        decl = local.get(node.text)
+        return (node.text, decl[0]) if decl else None
+
+    def _method(
+        self,
+        operand: NodeWrapper,
+        name: str,
+        pkg: PackageInfo,
+        f: GoFile,
+        local: Dict[str, Tuple[str, GoFile, NodeWrapper]],
+        imported: Dict[str, Dict[str, Tuple[str, GoFile, NodeWrapper]]],
+    ) -> Optional[Tuple[str, str]]:
+        scope = enclosing_function(operand)
+        typ = expr_type(operand, scope) if scope is not None else None
+        if not typ:
+            return None
+        qualifier, _, type_name = base_type(typ).lstrip("*").rpartition(".")
+        decls = local
+        if qualifier:
+            target = pkg.resolver.imported(qualifier, f) if pkg.resolver else None
+            if target is None:
+                return None
+            if target.dir not in imported:
+                imported[target.dir] = declarations(target)
+            decls = imported[target.dir]
+        decl = decls.get(f"{type_name}.{name}")
+        return (f"{base_type(typ).lstrip('*')}.{name}", decl[0]) if decl else None
+
+    @staticmethod
+    def _stdlib_imports(f: GoFile) -> Dict[str, str]:
+        """Qualifier -> import path for the file's imports listed in STDLIB."""
+        found: Dict[str, str] = {}
+        for spec in f.root.descendants():
+            if spec.type != "import_spec":
+                continue
+            alias, path = spec.field("name"), spec.field("path")
+            if path is None or unquote(path.text) not in STDLIB:
+                continue
+            import_path = unquote(path.text)
+            name = alias.text if alias is not None else import_path.rsplit("/", 1)[-1]
+            if name not in ("_", "."):
+                found[name] = import_path
+        return found
+
+
+def is_reference(node: NodeWrapper) -> bool:
+    """Whether a bare identifier uses a name rather than declaring or selecting one."""
+    parent = node.parent
+    if parent is None:
+        return False
+    if parent.type == "selector_expression":
+        return parent.field("operand") == node
+    if parent.type == "qualified_type":
+        return False
+    if parent.type == "keyed_element":
+        # `Field: value` names a struct field.
+        return parent.named_children[0] != node
+    return node not in parent.fields("name")
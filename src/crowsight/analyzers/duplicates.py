@@ -0,0 +1,50 @@
+# src/crowsight/analyzers/duplicates.py
+
+from typing import Dict, List, Tuple
+
+from ..report.models import Endpoint, Finding, Report, Service, Severity
+from ..scanner.routes import route_pattern
+from .registry import Analyzer
+
+RULES = {"duplicate-route": "The same method and path are registered more than once"}
+
+
+def duplicate_routes(services: List[Service]) -> List[Finding]:
+    """One error per (method, path) registered more than once across the scan.
+
+    Parameter syntax is normalized first, so `/users/{id}` and `/users/:id`
+    collide; the same path under different methods does not.
+    """
+    groups: Dict[Tuple[str, str], List[Tuple[Service, Endpoint]]] = {}
+    for svc in services:
+        for ep in svc.endpoints:
+            if ep.protocol != "http":
+                continue
+            groups.setdefault((ep.method, route_pattern(ep.path)), []).append((svc, ep))
+
+    findings: List[Finding] = []
+    for (method, _), sites in sorted(groups.items()):
+        # gorilla's .Methods("GET", "GET") and similar repeats are one registration.
+        unique = {(svc.dir, ep.file, ep.line): (svc, ep) for svc, ep in sites}
+        if len(unique) < 2:
+            continue
+        ordered = sorted(unique.values(), key=lambda s: (s[1].file, s[1].line))
+        where = ", ".join(f"{ep.path} at {ep.file}:{ep.line} ({svc.name})" for svc, ep in ordered)
+        first = ordered[0][1]
+        findings.append(
+            Finding(
+                code="duplicate-route",
+                severity=Severity.ERROR,
+                message=f"{method} route registered {len(ordered)} times: {where}",
+                file=first.file,
+                line=first.line,
+            )
+        )
+    return findings
+
+
+class DuplicateRouteAnalyzer(Analyzer):
+    """Flags (method, path) pairs registered more than once across the whole scan."""
+
+    def finish(self, report: Report) -> List[Finding]:
+        return duplicate_routes(report.services)
@@ -0,0 +1,87 @@
+# src/crowsight/analyzers/goroutines.py
+
+import re
+from typing import List
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import call_target, walk_body
+from ..scanner.handlers import iter_handlers
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .contexts import detached
+from .registry import Analyzer
+
+RULES = {
+    "fire-and-forget-goroutine": "A handler starts a goroutine with no request context "
+    "or wait mechanism"
+}
+
+# `ctx`, `reqCtx`, `ctxTimeout`: names a context is conventionally held in.
+CONTEXT_NAME = re.compile(r"^ctx(?:[A-Z]\w*)?$|Ctx$")
+CONTEXT_METHODS = ("Context", "UserContext")
+# wg.Wait(), g.Wait() on an errgroup, wg.Done() inside the goroutine.
+WAIT_METHODS = ("Wait", "Done")
+
+
+def uses_context(stmt: NodeWrapper, scope: NodeWrapper) -> bool:
+    """Whether the goroutine sees a request-derived context (not context.Background())."""
+    for node in stmt.descendants():
+        if node.type == "call_expression" and call_target(node)[1] in CONTEXT_METHODS:
+            return True
+        if node.type == "identifier" and CONTEXT_NAME.search(node.text):
+            if detached(node, scope) is None:
+                return True
+    return False
+
+
+def waits(nodes: List[NodeWrapper]) -> bool:
+    """A WaitGroup/errgroup call, channel receive or select among `nodes`."""
+    for node in nodes:
+        if node.type == "select_statement":
+            return True
+        if node.type == "unary_expression" and node.children and node.children[0].type == "<-":
+            return True
+        if node.type == "call_expression":
+            receiver, name = call_target(node)
+            if receiver is not None and name in WAIT_METHODS:
+                return True
+    return False
+
+
+class GoroutineLeakAnalyzer(Analyzer):
+    """Flags `go` statements in handlers that nothing cancels or waits for.
+
+    Deliberately conservative: a goroutine is left alone if it references a
+    context that does not trace back to context.Background()/TODO(), or if the
+    handler waits on something (a WaitGroup, errgroup, channel receive or
+    select) or the goroutine itself signals one (`wg.Done()`).
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            suppressed = nolint_lines(f)
+            if fn.line in suppressed:
+                continue
+            body = list(walk_body(fn))
+            stmts = [n for n in body if n.type == "go_statement" and n.line not in suppressed]
+            if not stmts or waits(body):
+                continue
+            name = fn.field("name")
+            label = name.text if name is not None else "inline handler"
+            for stmt in stmts:
+                if uses_context(stmt, fn) or waits(list(stmt.descendants())):
+                    continue
+                findings.append(
+                    finding(
+                        "fire-and-forget-goroutine",
+                        Severity.WARNING,
+                        f"goroutine started in {label} is not tied to the request context "
+                        f"or a WaitGroup/errgroup; it can outlive the request and its "
+                        f"errors are lost",
+                        f,
+                        stmt,
+                    )
+                )
+        return findings
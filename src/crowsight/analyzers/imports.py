@@ -0,0 +1,132 @@
+# src/crowsight/analyzers/imports.py
+
+from collections import deque
+from fnmatch import fnmatchcase
+from typing import Dict, List, Optional, Sequence, Set, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import unquote
+from ..scanner.package import GoFile, PackageInfo
+from ..scanner.types import TypeResolver
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {
+    "forbidden-import": "A package imports a package that a layering rule forbids it to",
+    "import-cycle": "Packages of the scanned modules import each other in a cycle",
+}
+
+
+def parse_rule(rule: str) -> Tuple[str, str]:
+    """`"internal/handlers/*:internal/db/*"` -> (from glob, to glob)."""
+    source, sep, target = rule.partition(":")
+    if not sep or not source.strip() or not target.strip():
+        raise ValueError(f"invalid import rule {rule!r}: expected FROM:TO")
+    return source.strip(), target.strip()
+
+
+def import_specs(f: GoFile) -> List[Tuple[NodeWrapper, str]]:
+    """(spec, import path) for each import of the file."""
+    found: List[Tuple[NodeWrapper, str]] = []
+    for spec in f.root.descendants():
+        if spec.type != "import_spec":
+            continue
+        path = spec.field("path")
+        if path is not None:
+            found.append((spec, unquote(path.text)))
+    return found
+
+
+def matches(pattern: str, names: Sequence[Optional[str]]) -> bool:
+    return any(name is not None and fnmatchcase(name, pattern) for name in names)
+
+
+class ImportAnalyzer(Analyzer):
+    """Flags imports that break layering rules, and import cycles between packages.
+
+    A rule `FROM:TO` forbids packages matching the FROM glob to import
+    packages matching TO. Globs are matched against a package's directory
+    (relative to the scan root) and against its import path, and `*`
+    crosses `/`, so `internal/handlers*` covers the package and everything
+    below it. Cycles are followed through the packages of the scanned
+    modules and reported once, at the import in the cycle's first package.
+    """
+
+    def __init__(self, rules: Sequence[str] = ()):
+        self.rules = [parse_rule(r) for r in rules]
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        types = pkg.resolver
+        if types is None:
+            return []
+        findings: List[Finding] = []
+        source = (pkg.dir, types.modules.import_path(pkg.dir))
+        layered = [r for r in self.rules if matches(r[0], source)]
+        # Packages already checked for a cycle back; other files import them too.
+        checked: Set[str] = set()
+        for f in pkg.files:
+            suppressed = nolint_lines(f)
+            for spec, path in import_specs(f):
+                if spec.line in suppressed:
+                    continue
+                target = types.modules.import_dir(path)
+                for rule in layered:
+                    if matches(rule[1], (path, target)):
+                        findings.append(
+                            finding(
+                                "forbidden-import",
+                                Severity.ERROR,
+                                f"{source[1] or pkg.dir} imports {path}, which the rule "
+                                f"{rule[0]}:{rule[1]} forbids",
+                                f,
+                                spec,
+                            )
+                        )
+                        break
+                if target is None or target in checked:
+                    continue
+                checked.add(target)
+                cycle = self._cycle(types, pkg.dir, target)
+                if cycle is not None:
+                    names = [types.modules.import_path(d) or d for d in cycle]
+                    findings.append(
+                        finding(
+                            "import-cycle",
+                            Severity.ERROR,
+                            f"import cycle: {' -> '.join(names)}",
+                            f,
+                            spec,
+                        )
+                    )
+        return findings
+
+    @staticmethod
+    def _cycle(types: TypeResolver, start: str, first: str) -> Optional[List[str]]:
+        """The shortest import chain start -> first -> ... -> start, if there is one.
+
+        Only chains whose smallest directory is `start` count, so each cycle
+        is reported by one package.
+        """
+        if first == start or first < start:
+            return None
+        parents: Dict[str, str] = {first: start}
+        queue = deque([first])
+        while queue:
+            d = queue.popleft()
+            dep = types.package_at(d)
+            if dep is None:
+                continue
+            for f in dep.files:
+                for _, path in import_specs(f):
+                    target = types.modules.import_dir(path)
+                    if target == start:
+                        chain = [d]
+                        while chain[-1] != first:
+                            chain.append(parents[chain[-1]])
+                        return [start] + chain[::-1] + [start]
+                    if target is None or target < start or target in parents:
+                        continue
+                    parents[target] = d
+                    queue.append(target)
+        return None
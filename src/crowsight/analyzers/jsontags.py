@@ -0,0 +1,44 @@
+# src/crowsight/analyzers/jsontags.py
+
+from typing import List, Set, Tuple
+
+from ..report.models import Finding, Report, Severity
+from .registry import Analyzer
+
+RULES = {"missing-json-tag": "An exported field of a request or response DTO has no json tag"}
+
+
+class MissingJSONTagAnalyzer(Analyzer):
+    """Flags exported DTO fields without a `json` tag.
+
+    DTOs are the structs the scan found handlers decoding into or encoding
+    from, and the structs nested in their fields, in whichever package
+    declares them. Without a tag the wire name is the Go field name, which
+    a rename silently changes. Embedded and unexported fields are skipped.
+    """
+
+    def finish(self, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        # Services share DTOs; each field is reported once.
+        seen: Set[Tuple[str, int, str]] = set()
+        for svc in report.services:
+            for st in svc.structs:
+                for fd in st.fields:
+                    if fd.json is not None or fd.embedded or not fd.exported:
+                        continue
+                    key = (st.file, fd.line, fd.name)
+                    if key in seen:
+                        continue
+                    seen.add(key)
+                    findings.append(
+                        Finding(
+                            code="missing-json-tag",
+                            severity=Severity.WARNING,
+                            message=f"field {fd.name} of DTO {st.package}.{st.name} has no "
+                            f'json tag; add `json:"..."` so renaming the field does not '
+                            "change the API",
+                            file=st.file,
+                            line=fd.line or st.line,
+                        )
+                    )
+        return findings
@@ -0,0 +1,54 @@
+# src/crowsight/analyzers/observability.py
+
+from typing import List
+
+from ..report.models import Finding, Report, Service, Severity
+from .registry import Analyzer
+
+RULES = {
+    "missing-metrics": "A service with HTTP routes exposes no metrics endpoint",
+    "missing-health": "A service with HTTP routes exposes no health or readiness endpoint",
+}
+
+
+def _finding(code: str, message: str, svc: Service) -> Finding:
+    first = min(
+        (ep for ep in svc.endpoints if ep.protocol == "http"), key=lambda e: (e.file, e.line)
+    )
+    return Finding(
+        code=code, severity=Severity.INFO, message=message, file=first.file, line=first.line
+    )
+
+
+class ObservabilityAnalyzer(Analyzer):
+    """Lists the services with HTTP routes but no `/metrics` or health check route.
+
+    Reported at the service's first route. A service whose metrics or probes
+    are served by another package (a shared router, a sidecar) shows up
+    here too; suppress those with a policy.
+    """
+
+    def finish(self, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for svc in report.services:
+            if not any(ep.protocol == "http" for ep in svc.endpoints):
+                continue
+            if not svc.has_metrics:
+                findings.append(
+                    _finding(
+                        "missing-metrics",
+                        f"service {svc.name} exposes no /metrics endpoint "
+                        "(e.g. promhttp.Handler())",
+                        svc,
+                    )
+                )
+            if not svc.has_health:
+                findings.append(
+                    _finding(
+                        "missing-health",
+                        f"service {svc.name} exposes no health or readiness endpoint "
+                        "(/healthz, /readyz)",
+                        svc,
+                    )
+                )
+        return findings
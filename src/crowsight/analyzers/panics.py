@@ -0,0 +1,117 @@
+# src/crowsight/analyzers/panics.py
+
+import re
+from typing import Dict, List, Optional, Set, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Endpoint, Finding, Report, Severity
+from ..scanner.goast import FUNC_TYPES, call_target, walk_body
+from ..scanner.package import GoFile, PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {"unrecovered-panic": "A handler can panic with no recover() or recovery middleware"}
+
+PANIC_CALLS = {"panic", "log.Panic", "log.Panicf", "log.Panicln"}
+# chi's middleware.Recoverer, gin.Recovery(), echo's middleware.Recover(), ...
+RECOVERY_MIDDLEWARE = re.compile(r"recover", re.IGNORECASE)
+
+
+def is_panic(call: NodeWrapper) -> bool:
+    fn = call.field("function")
+    return fn is not None and fn.text in PANIC_CALLS
+
+
+def panics(fn: NodeWrapper) -> List[NodeWrapper]:
+    """panic calls in `fn` itself (not in nested function literals)."""
+    return [n for n in walk_body(fn) if n.type == "call_expression" and is_panic(n)]
+
+
+def recovered(endpoints: List[Endpoint]) -> bool:
+    """Whether every endpoint served by the handler sits behind recovery middleware."""
+    return all(any(RECOVERY_MIDDLEWARE.search(m) for m in ep.middleware) for ep in endpoints)
+
+
+class UnrecoveredPanicAnalyzer(Analyzer):
+    """Flags panics in handlers that neither recover() nor sit behind recovery middleware.
+
+    Panics in package functions the handler calls directly are followed one
+    level deep; each finding points at the panic itself.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        svc = package_service(pkg, report)
+        if svc is None:
+            return findings
+        funcs: Dict[str, Tuple[GoFile, NodeWrapper]] = {}
+        files = {f.rel: f for f in pkg.files}
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                name = decl.field("name")
+                if decl.type == "function_declaration" and name is not None:
+                    funcs[name.text] = (f, decl)
+        by_handler: Dict[Tuple[str, int], List[Endpoint]] = {}
+        for ep in svc.endpoints:
+            if ep.protocol == "http" and ep.handler_file in files:
+                by_handler.setdefault((ep.handler_file, ep.handler_line), []).append(ep)
+        seen: Set[Tuple[str, int, int]] = set()
+        for (rel, line), endpoints in sorted(by_handler.items()):
+            f = files[rel]
+            fn = self._function_at(f, line)
+            if fn is None or recovered(endpoints) or self._recovers(fn, funcs):
+                continue
+            label = endpoints[0].handler or "inline handler"
+            sites = [(f, call, None) for call in panics(fn)]
+            for call in walk_body(fn):
+                if call.type != "call_expression":
+                    continue
+                receiver, name = call_target(call)
+                helper = funcs.get(name) if receiver is None else None
+                if helper is None or helper[1] is fn or self._recovers(helper[1], funcs):
+                    continue
+                sites.extend((helper[0], p, call) for p in panics(helper[1]))
+            for gf, site, via in sites:
+                key = (gf.rel, site.line, site.column)
+                if key in seen or site.line in nolint_lines(gf) or fn.line in nolint_lines(f):
+                    continue
+                seen.add(key)
+                how = f" via the call at {f.rel}:{via.line}" if via is not None else ""
+                findings.append(
+                    finding(
+                        "unrecovered-panic",
+                        Severity.WARNING,
+                        f"panic in {label}{how} is not recovered by the handler "
+                        f"or any recovery middleware",
+                        gf,
+                        site,
+                    )
+                )
+        return findings
+
+    @staticmethod
+    def _function_at(f: GoFile, line: int) -> Optional[NodeWrapper]:
+        # The innermost match, for literals that start on their caller's line.
+        found = None
+        for node in f.root.descendants():
+            if node.type in FUNC_TYPES and node.line == line:
+                found = node
+        return found
+
+    @staticmethod
+    def _recovers(fn: NodeWrapper, funcs: Dict[str, Tuple[GoFile, NodeWrapper]]) -> bool:
+        """`defer func() { recover() ... }()` or `defer recoverer()` in `fn`."""
+        for node in walk_body(fn):
+            if node.type != "defer_statement":
+                continue
+            for call in (n for n in node.descendants() if n.type == "call_expression"):
+                receiver, name = call_target(call)
+                if name == "recover" and receiver is None:
+                    return True
+                deferred = funcs.get(name) if receiver is None else None
+                if deferred is not None and any(
+                    n.type == "call_expression" and call_target(n) == (None, "recover")
+                    for n in walk_body(deferred[1])
+                ):
+                    return True
+        return False
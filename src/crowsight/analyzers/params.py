@@ -0,0 +1,38 @@
+# src/crowsight/analyzers/params.py
+
+from typing import List
+
+from ..report.models import Finding, Report, Severity
+from ..scanner.package import PackageInfo
+from .common import nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {"unused-path-param": "A route declares a path parameter its handler never reads"}
+
+
+class UnusedParamAnalyzer(Analyzer):
+    """Flags route path parameters that the resolved handler is known not to read."""
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        svc = package_service(pkg, report)
+        if svc is None:
+            return findings
+        suppressed = {f.rel: nolint_lines(f) for f in pkg.files}
+        for ep in svc.endpoints:
+            if ep.line in suppressed.get(ep.file, ()):
+                continue
+            for p in ep.path_params:
+                if p.used is not False:
+                    continue
+                findings.append(
+                    Finding(
+                        code="unused-path-param",
+                        severity=Severity.WARNING,
+                        message=f"{ep.method} {ep.path}: path parameter {p.name!r} "
+                        f"is never read by {ep.handler or 'its handler'}",
+                        file=ep.file,
+                        line=ep.line,
+                    )
+                )
+        return findings
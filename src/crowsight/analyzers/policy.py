@@ -0,0 +1,84 @@
+# src/crowsight/analyzers/policy.py
+
+from dataclasses import dataclass, field
+import json
+from pathlib import Path
+from typing import Dict, Iterable, List, Optional, Union
+
+from loguru import logger
+
+from ..report.models import Finding, Severity
+
+IGNORE = "ignore"
+
+Override = Union[Severity, str]
+
+
+def parse_severity(value: str) -> Severity:
+    try:
+        return Severity(value.strip().lower())
+    except ValueError:
+        choices = ", ".join(s.value for s in Severity)
+        raise ValueError(f"unknown severity {value!r} (expected {choices})") from None
+
+
+@dataclass
+class FindingsPolicy:
+    """Per-code severity overrides; a code mapped to "ignore" is dropped."""
+
+    # code -> Severity, or IGNORE
+    overrides: Dict[str, Override] = field(default_factory=dict)
+
+    @classmethod
+    def from_mapping(cls, mapping: Dict[str, str]) -> "FindingsPolicy":
+        overrides: Dict[str, Override] = {}
+        for code, value in mapping.items():
+            if str(value).strip().lower() == IGNORE:
+                overrides[code] = IGNORE
+            else:
+                overrides[code] = parse_severity(str(value))
+        return cls(overrides)
+
+    @classmethod
+    def load(cls, path: Path) -> "FindingsPolicy":
+        """Read a JSON object mapping finding codes to a severity or "ignore"."""
+        data = json.loads(Path(path).read_text())
+        if not isinstance(data, dict):
+            raise ValueError(f"{path}: expected an object mapping finding codes to severities")
+        return cls.from_mapping(data)
+
+    def apply(self, findings: Iterable[Finding]) -> List[Finding]:
+        out: List[Finding] = []
+        for fd in findings:
+            override = self.overrides.get(fd.code)
+            if override == IGNORE:
+                continue
+            if override is not None:
+                fd.severity = override
+            out.append(fd)
+        return out
+
+
+def worst(findings: Iterable[Finding]) -> Optional[Severity]:
+    ranked = [fd.severity for fd in findings]
+    return max(ranked, key=lambda s: s.rank) if ranked else None
+
+
+def fails(findings: Iterable[Finding], threshold: Optional[Severity]) -> bool:
+    """Does any finding reach `threshold`? None never fails."""
+    if threshold is None:
+        return False
+    top = worst(findings)
+    if top is not None and top.rank >= threshold.rank:
+        logger.info(f"Failing: found {top} findings (threshold {threshold})")
+        return True
+    return False
+
+
+def exit_code(
+    findings: List[Finding], threshold: Optional[Severity], codes: Dict[Severity, int]
+) -> int:
+    """0 unless a finding reaches `threshold`; then `codes` of the worst severity found."""
+    if not fails(findings, threshold):
+        return 0
+    return codes[worst(findings)]
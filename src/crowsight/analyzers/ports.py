@@ -0,0 +1,33 @@
+# src/crowsight/analyzers/ports.py
+
+from typing import List
+
+from ..report.models import Finding, Report, Severity
+from ..scanner.listen import port_conflicts
+from .registry import Analyzer
+
+RULES = {"port-conflict": "More than one service listens on the same literal port"}
+
+
+class PortConflictAnalyzer(Analyzer):
+    """Flags literal ports that more than one service binds.
+
+    Only quoted addresses are compared; ports read from flags or the
+    environment may well differ at run time. Reported once per port, at
+    the first service's listen call.
+    """
+
+    def finish(self, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for port, services in port_conflicts(report.services):
+            where = ", ".join(f"{s.name} at {s.listen_file}:{s.listen_line}" for s in services)
+            findings.append(
+                Finding(
+                    code="port-conflict",
+                    severity=Severity.WARNING,
+                    message=f"port {port} is bound by more than one service: {where}",
+                    file=services[0].listen_file or "",
+                    line=services[0].listen_line,
+                )
+            )
+        return findings
@@ -0,0 +1,36 @@
+# src/crowsight/analyzers/queries.py
+
+from typing import Dict, List, Tuple
+
+from ..report.models import Finding, Report, Severity
+from ..scanner.package import PackageInfo
+from .common import nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {"n-plus-one": "A handler issues a database query inside a loop"}
+
+
+class NPlusOneAnalyzer(Analyzer):
+    """Flags database calls an endpoint makes inside a loop, a likely N+1 pattern."""
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        svc = package_service(pkg, report)
+        if svc is None:
+            return []
+        suppressed = {f.rel: nolint_lines(f) for f in pkg.files}
+        sites: Dict[Tuple[str, int], Finding] = {}
+        for ep in svc.endpoints:
+            for q in ep.db_queries:
+                key = (q.file, q.line)
+                if not q.in_loop or key in sites or q.line in suppressed.get(q.file, ()):
+                    continue
+                via = f" (via {' -> '.join(q.via)})" if q.via else ""
+                sites[key] = Finding(
+                    code="n-plus-one",
+                    severity=Severity.WARNING,
+                    message=f"{ep.method} {ep.path}: {q.call} runs inside a loop{via}; "
+                    f"this may issue one query per item (N+1)",
+                    file=q.file,
+                    line=q.line,
+                )
+        return list(sites.values())
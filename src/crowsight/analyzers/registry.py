@@ -0,0 +1,88 @@
+# src/crowsight/analyzers/registry.py
+
+from dataclasses import dataclass
+from typing import Any, Callable, Dict, List, Optional, Tuple
+
+from ..report.models import Finding, Report, Service
+from ..scanner.package import PackageInfo
+
+
+class Analyzer:
+    """Base class for checks the scanner runs over every package.
+
+    `analyze` is called once per package with the directory's partial Report,
+    whose services already include the one for `pkg` (see `package_service`).
+    `finish` is called once after every package is merged, for checks that
+    span services. Both return findings; severities go through the policy.
+    """
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        return []
+
+    def finish(self, report: Report) -> List[Finding]:
+        return []
+
+
+# Builds an analyzer from the scan's ScanOptions, for analyzers with settings.
+AnalyzerFactory = Callable[[Any], Analyzer]
+
+
+@dataclass
+class _Entry:
+    factory: AnalyzerFactory
+    enabled: bool
+
+
+_REGISTRY: Dict[str, _Entry] = {}
+_builtins_loaded = False
+
+
+def register_analyzer(name: str, analyzer: Analyzer, enabled: bool = True):
+    """Add an analyzer that every scan runs (unless `enabled` is False or it is disabled)."""
+    register_analyzer_factory(name, lambda _options: analyzer, enabled)
+
+
+def register_analyzer_factory(name: str, factory: AnalyzerFactory, enabled: bool = True):
+    """Like `register_analyzer`, but build the analyzer per scan from its ScanOptions."""
+    if name in _REGISTRY:
+        raise ValueError(f"analyzer {name!r} is already registered")
+    _REGISTRY[name] = _Entry(factory, enabled)
+
+
+def registered_analyzers() -> Dict[str, bool]:
+    """Analyzer name -> whether it runs by default."""
+    _load_builtins()
+    return {name: e.enabled for name, e in sorted(_REGISTRY.items())}
+
+
+def build_analyzers(options: Any) -> List[Tuple[str, Analyzer]]:
+    """(name, analyzer) for a scan: the defaults, plus `options.enable`, minus `options.disable`."""
+    _load_builtins()
+    enable, disable = list(options.enable), list(options.disable)
+    unknown = sorted(set(enable + disable) - set(_REGISTRY))
+    if unknown:
+        known = ", ".join(sorted(_REGISTRY))
+        raise ValueError(f"unknown analyzer(s) {', '.join(unknown)}; known: {known}")
+    return [
+        (name, e.factory(options))
+        for name, e in sorted(_REGISTRY.items())
+        if (e.enabled or name in enable) and name not in disable
+    ]
+
+
+def package_service(pkg: PackageInfo, report: Report) -> Optional[Service]:
+    """The Service `report` holds for `pkg`."""
+    for svc in report.services:
+        if svc.dir == pkg.dir and svc.package == pkg.name:
+            return svc
+    return None
+
+
+def _load_builtins():
+    # Imported lazily: the built-in analyzers import this module.
+    global _builtins_loaded
+    if not _builtins_loaded:
+        _builtins_loaded = True
+        from .builtin import register_builtins
+
+        register_builtins()
@@ -0,0 +1,152 @@
+# src/crowsight/analyzers/response.py
+
+from typing import List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import call_args, call_target, statements, walk_body
+from ..scanner.handlers import func_params, iter_handlers
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {"missing-response": "A handler path returns without writing a response or status"}
+
+# Calls that end the goroutine or the process; paths through them need no response.
+TERMINATORS = {
+    "panic",
+    "os.Exit",
+    "runtime.Goexit",
+    "log.Fatal",
+    "log.Fatalf",
+    "log.Fatalln",
+    "log.Panic",
+    "log.Panicf",
+    "log.Panicln",
+}
+WRITER_METHODS = ("Write", "WriteHeader", "WriteString", "ReadFrom", "Flush")
+SWITCH_TYPES = ("expression_switch_statement", "type_switch_statement", "select_statement")
+CASE_TYPES = ("expression_case", "type_case", "communication_case", "default_case")
+# Jumps end the statement list; the enclosing case or loop carries on from there.
+JUMP_TYPES = ("break_statement", "continue_statement", "goto_statement", "fallthrough_statement")
+
+
+def _merge(states: List[Optional[bool]]) -> Optional[bool]:
+    """Write state after branches: None if no branch falls through, else all must have written."""
+    reached = [s for s in states if s is not None]
+    return all(reached) if reached else None
+
+
+class _Flow:
+    """Walks a handler body tracking whether the response has been written."""
+
+    def __init__(self, writer: str):
+        self.writer = writer
+        # First `return` reached before anything was written.
+        self.bare_return: Optional[NodeWrapper] = None
+
+    def block(self, stmts: List[NodeWrapper], written: bool) -> Optional[bool]:
+        """Write state on falling off the end of `stmts`; None if every path leaves early."""
+        for stmt in stmts:
+            if stmt.type in JUMP_TYPES:
+                return written
+            state = self.stmt(stmt, written)
+            if state is None:
+                return None
+            written = state
+        return written
+
+    def stmt(self, stmt: NodeWrapper, written: bool) -> Optional[bool]:
+        if stmt.type == "return_statement":
+            if not written and not self.writes(stmt) and self.bare_return is None:
+                self.bare_return = stmt
+            return None
+        if stmt.type == "expression_statement" and self._terminates(stmt):
+            return None
+        if stmt.type == "block":
+            return self.block(statements(stmt), written)
+        if stmt.type == "labeled_statement":
+            inner = [c for c in stmt.named_children if c.type != "label_name"]
+            return self.block(inner, written)
+        if stmt.type == "if_statement":
+            written = written or self.writes(stmt.field("initializer"), stmt.field("condition"))
+            then = self.block(statements(stmt.field("consequence")), written)
+            alt = stmt.field("alternative")
+            other = self.stmt(alt, written) if alt is not None else written
+            return _merge([then, other])
+        if stmt.type in SWITCH_TYPES:
+            written = written or self.writes(stmt.field("initializer"), stmt.field("value"))
+            cases = [c for c in stmt.named_children if c.type in CASE_TYPES]
+            states = [self.block(statements(c), written) for c in cases]
+            # Without a default a switch can match nothing; a select waits for a case.
+            if stmt.type != "select_statement" and not any(c.type == "default_case" for c in cases):
+                states.append(written)
+            return _merge(states)
+        if stmt.type == "for_statement":
+            self.block(statements(stmt.field("body")), written)
+            infinite = not any(c.type != "block" for c in stmt.named_children)
+            if infinite and not any(n.type == "break_statement" for n in walk_body(stmt)):
+                return None
+            # The body may run zero times.
+            return written
+        if stmt.type == "defer_statement":
+            # Deferred literals run before the handler returns, so look inside them.
+            return written or any(self._is_write(n) for n in stmt.descendants())
+        if stmt.type == "go_statement":
+            return written
+        return written or self.writes(stmt)
+
+    def writes(self, *nodes: Optional[NodeWrapper]) -> bool:
+        return any(
+            self._is_write(n) for node in nodes if node is not None for n in walk_body(node)
+        )
+
+    def _is_write(self, node: NodeWrapper) -> bool:
+        if node.type != "call_expression":
+            return False
+        receiver, name = call_target(node)
+        if receiver == self.writer and name in WRITER_METHODS:
+            return True
+        # Passing the writer on (http.Error, json.NewEncoder(w), next.ServeHTTP(w, r),
+        # tmpl.Execute(w, ...)) hands the response to someone who writes it.
+        return any(a.type == "identifier" and a.text == self.writer for a in call_args(node))
+
+    @staticmethod
+    def _terminates(stmt: NodeWrapper) -> bool:
+        calls = [c for c in stmt.named_children if c.type == "call_expression"]
+        if len(calls) != 1:
+            return False
+        fn = calls[0].field("function")
+        return fn is not None and fn.text in TERMINATORS
+
+
+class MissingResponseAnalyzer(Analyzer):
+    """Flags net/http handlers with a path that writes neither a body nor a status."""
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            body = fn.field("body")
+            writer = next((n for n, t in func_params(fn) if t == "http.ResponseWriter"), None)
+            if body is None or not writer or writer == "_" or fn.line in nolint_lines(f):
+                continue
+            flow = _Flow(writer)
+            end = flow.block(statements(body), False)
+            if flow.bare_return is not None:
+                where = f"returns at line {flow.bare_return.line}"
+            elif end is False:
+                where = "reaches the end of the function"
+            else:
+                continue
+            name = fn.field("name")
+            label = name.text if name is not None else "inline handler"
+            found = finding(
+                "missing-response",
+                Severity.WARNING,
+                f"{label} {where} without writing a response or status",
+                f,
+                fn,
+            )
+            found.expression = None
+            findings.append(found)
+        return findings
@@ -0,0 +1,134 @@
+# src/crowsight/analyzers/secrets.py
+
+import re
+from typing import List, Optional, Pattern, Sequence, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import STRING_TYPES, unquote, unwrap_element
+from ..scanner.package import PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {"hardcoded-secret": "A credential or key is hardcoded in a string literal"}
+
+# What a leaked credential looks like, whatever it is assigned to.
+SECRET_PATTERNS: List[Tuple[str, Pattern[str]]] = [
+    ("AWS access key", re.compile(r"\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b")),
+    ("private key", re.compile(r"-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----")),
+    ("GitHub token", re.compile(r"\bgh[pousr]_[A-Za-z0-9]{36,}\b")),
+    ("Slack token", re.compile(r"\bxox[abposr]-[A-Za-z0-9-]{10,}")),
+    ("Stripe secret key", re.compile(r"\b[sr]k_live_[0-9A-Za-z]{20,}\b")),
+    ("Google API key", re.compile(r"\bAIza[0-9A-Za-z_-]{35}\b")),
+]
+# Fields, variables and map keys whose string values are credentials.
+SECRET_NAME = re.compile(
+    r"(?:passw(?:or)?d|secret|token|api_?key|access_?key|private_?key|credentials?)$",
+    re.IGNORECASE,
+)
+# Shorter values assigned to such names are placeholders more often than not.
+MIN_LENGTH = 6
+# `"GITHUB_TOKEN"` (an env var name), `"${TOKEN}"`, `"{{ .Password }}"`, `"<token>"`.
+PLACEHOLDER = re.compile(r"^[A-Z][A-Z0-9_]*$|\$\{|\{\{|^<.*>$|\s")
+
+
+def redact(value: str) -> str:
+    """A prefix of `value`, never more than a third of it: `AKIA...`."""
+    return f"{value[: min(4, len(value) // 3)]}..."
+
+
+def assigned_name(lit: NodeWrapper) -> Optional[str]:
+    """The field, variable or map key a string literal is the value of, if any."""
+    node = lit.parent
+    if node is not None and node.type == "literal_element":
+        lit, node = node, node.parent
+    if node is None:
+        return None
+    if node.type == "keyed_element":
+        parts = [unwrap_element(c) for c in node.named_children if c.type != "comment"]
+        if len(parts) != 2 or parts[1] != unwrap_element(lit):
+            return None
+        key = parts[0]
+        return unquote(key.text) if key.type in STRING_TYPES else key.text
+    if node.type != "expression_list":
+        return None
+    stmt = node.parent
+    if stmt is None or stmt.type not in (
+        "assignment_statement",
+        "short_var_declaration",
+        "var_spec",
+        "const_spec",
+    ):
+        return None
+    if stmt.type in ("var_spec", "const_spec"):
+        names = [n.text for n in stmt.fields("name")]
+        if stmt.field("value") != node:
+            return None
+    else:
+        left = stmt.field("left")
+        if left is None or stmt.field("right") != node:
+            return None
+        names = [n.text for n in left.named_children]
+    values = node.named_children
+    if len(names) != len(values):
+        return None
+    # `cfg.DB.Password = "..."` names the field Password.
+    return names[values.index(lit)].rsplit(".", 1)[-1]
+
+
+class HardcodedSecretAnalyzer(Analyzer):
+    """Flags string literals that look like credentials.
+
+    A literal is reported when it matches one of SECRET_PATTERNS, or when it
+    is assigned to a password/token/key-like field, variable or map key. The
+    literal is redacted to a short prefix in the finding. `allowlist` regexes
+    (e.g. known test fixtures) suppress a finding when they match the file
+    path or the literal's value.
+    """
+
+    def __init__(self, allowlist: Sequence[str] = ()):
+        self.allowlist: List[Pattern[str]] = []
+        for pattern in allowlist:
+            try:
+                self.allowlist.append(re.compile(pattern))
+            except re.error as e:
+                raise ValueError(f"invalid secret allowlist pattern {pattern!r}: {e}")
+
+    def allowed(self, rel: str, value: str) -> bool:
+        return any(p.search(rel) or p.search(value) for p in self.allowlist)
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f in pkg.files:
+            suppressed = nolint_lines(f)
+            for lit in f.root.descendants():
+                if lit.type not in STRING_TYPES or lit.line in suppressed:
+                    continue
+                value = unquote(lit.text)
+                what = self._describe(lit, value)
+                if what is None or self.allowed(f.rel, value):
+                    continue
+                fd = finding(
+                    "hardcoded-secret",
+                    Severity.ERROR,
+                    f"{what} is hardcoded ({redact(value)}); load it from the "
+                    f"environment or a secret store",
+                    f,
+                    lit,
+                )
+                # The report must not leak the secret it found.
+                fd.expression = f'"{redact(value)}"'
+                findings.append(fd)
+        return findings
+
+    @staticmethod
+    def _describe(lit: NodeWrapper, value: str) -> Optional[str]:
+        for kind, pattern in SECRET_PATTERNS:
+            if pattern.search(value):
+                return f"a {kind}" if kind[0] not in "AEIOU" else f"an {kind}"
+        name = assigned_name(lit)
+        if name is None or not SECRET_NAME.search(name):
+            return None
+        if len(value) < MIN_LENGTH or PLACEHOLDER.search(value):
+            return None
+        return f"the value of {name}"
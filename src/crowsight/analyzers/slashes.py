@@ -0,0 +1,63 @@
+# src/crowsight/analyzers/slashes.py
+
+from fnmatch import fnmatchcase
+from typing import Dict, List, Sequence, Tuple
+
+from ..report.models import Endpoint, Finding, Report, Service, Severity
+from ..scanner.routes import without_trailing_slash
+from .registry import Analyzer
+
+RULES = {"trailing-slash": "Routes are registered both with and without a trailing slash"}
+
+
+def slash_pairs(services: List[Service], allowed: Sequence[str] = ()) -> List[Finding]:
+    """One warning per path registered both with and without a trailing slash.
+
+    Registrations are compared across every service, with parameter syntax
+    normalized, whatever their methods. Paths matching an `allowed` glob
+    (in either form) are registered both ways on purpose.
+    """
+    groups: Dict[str, List[Tuple[Service, Endpoint]]] = {}
+    for svc in services:
+        for ep in svc.endpoints:
+            if ep.protocol == "http" and ep.path not in ("", "/"):
+                groups.setdefault(without_trailing_slash(ep.path), []).append((svc, ep))
+
+    findings: List[Finding] = []
+    for _, sites in sorted(groups.items()):
+        if len({ep.path.endswith("/") for _, ep in sites}) < 2:
+            continue
+        if any(fnmatchcase(ep.path, p) for _, ep in sites for p in allowed):
+            continue
+        ordered = sorted(
+            {(svc.dir, ep.file, ep.line): (svc, ep) for svc, ep in sites}.values(),
+            key=lambda s: (s[1].file, s[1].line),
+        )
+        where = ", ".join(
+            f"{ep.method} {ep.path} at {ep.file}:{ep.line} ({svc.name})" for svc, ep in ordered
+        )
+        first = ordered[0][1]
+        findings.append(
+            Finding(
+                code="trailing-slash",
+                severity=Severity.WARNING,
+                message=f"routes differ only by a trailing slash: {where}",
+                file=first.file,
+                line=first.line,
+            )
+        )
+    return findings
+
+
+class TrailingSlashAnalyzer(Analyzer):
+    """Flags paths registered both with and without a trailing slash across the scan.
+
+    Routers redirect or 404 between the two forms, so clients of one service
+    can end up on the other's route.
+    """
+
+    def __init__(self, allowed: Sequence[str] = ()):
+        self.allowed = list(allowed)
+
+    def finish(self, report: Report) -> List[Finding]:
+        return slash_pairs(report.services, self.allowed)
@@ -0,0 +1,217 @@
+# src/crowsight/analyzers/tls.py
+
+import re
+from typing import Dict, List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import (
+    call_args,
+    call_target,
+    enclosing_function,
+    iter_calls,
+    literal_fields,
+    local_value,
+    strip_address_of,
+    string_value,
+    walk_body,
+)
+from ..scanner.callgraph import file_imports
+from ..scanner.handlers import local_type
+from ..scanner.package import GoFile, PackageInfo
+from ..scanner.resolve import HandlerResolver
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+RULES = {
+    "insecure-skip-verify": "A tls.Config disables certificate verification",
+    "weak-tls-version": "A tls.Config allows TLS versions below 1.2",
+    "plaintext-server": "A production entrypoint serves HTTP without TLS",
+}
+
+# tls.VersionXXX constants and their wire values; anything below 1.2 is weak.
+TLS_VERSIONS = {
+    "VersionSSL30": 0x0300,
+    "VersionTLS10": 0x0301,
+    "VersionTLS11": 0x0302,
+    "VersionTLS12": 0x0303,
+    "VersionTLS13": 0x0304,
+}
+MIN_VERSION = 0x0303
+# Calls that mean the package serves TLS somewhere (so a plain listener is likely a redirect).
+TLS_SERVE_CALLS = ("ListenAndServeTLS", "ServeTLS", "NewListener", "RunTLS", "StartTLS")
+PLAIN_SERVE_CALLS = ("ListenAndServe",)
+LOOPBACK = ("localhost", "127.", "[::1]")
+# Directories whose `main` packages are not production entrypoints.
+NON_PRODUCTION = re.compile(
+    r"(^|/)(_?examples?|testdata|tests?|e2e|demos?|dev|mocks?|tools|hack|scripts|sandbox)(/|$)"
+)
+
+
+def tls_version(node: Optional[NodeWrapper]) -> Optional[int]:
+    """Value of `tls.VersionTLS12` or a numeric literal like `0x0303`; None if unknown."""
+    if node is None:
+        return None
+    name = node.text.rsplit(".", 1)[-1]
+    if name in TLS_VERSIONS:
+        return TLS_VERSIONS[name]
+    if node.type == "int_literal":
+        try:
+            return int(node.text.replace("_", ""), 0)
+        except ValueError:
+            return None
+    return None
+
+
+def is_tls_config(lit: NodeWrapper) -> bool:
+    typ = lit.field("type")
+    return typ is not None and typ.text.endswith("tls.Config")
+
+
+def assigned_name(lit: NodeWrapper) -> Optional[str]:
+    """`cfg` in `cfg := &tls.Config{...}` or `var cfg = tls.Config{...}`."""
+    node = lit
+    while node.parent is not None and node.parent.type in ("unary_expression", "expression_list"):
+        node = node.parent
+    stmt = node.parent
+    if stmt is None:
+        return None
+    if stmt.type == "var_spec":
+        names = stmt.fields("name")
+        return names[0].text if len(names) == 1 else None
+    if stmt.type in ("short_var_declaration", "assignment_statement"):
+        left = stmt.field("left")
+        if left is not None and len(left.named_children) == 1:
+            return left.named_children[0].text
+    return None
+
+
+def field_assignment(scope: NodeWrapper, var: str, name: str) -> Optional[NodeWrapper]:
+    """The value of a later `var.Name = value` in `scope`."""
+    for node in walk_body(scope):
+        if node.type != "assignment_statement":
+            continue
+        left, right = node.field("left"), node.field("right")
+        if left is None or right is None or len(left.named_children) != 1:
+            continue
+        if left.named_children[0].text == f"{var}.{name}" and right.named_children:
+            return right.named_children[0]
+    return None
+
+
+class TLSAnalyzer(Analyzer):
+    """Flags weak tls.Config literals and plaintext servers in production `main` packages."""
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f in pkg.files:
+            suppressed = nolint_lines(f)
+            for node in f.root.descendants():
+                if node.type == "composite_literal" and is_tls_config(node):
+                    if node.line not in suppressed:
+                        findings.extend(self._check_config(f, node))
+        findings.extend(self._plaintext(pkg))
+        return findings
+
+    def _check_config(self, f: GoFile, lit: NodeWrapper) -> List[Finding]:
+        fields = literal_fields(lit)
+        scope = enclosing_function(lit) or f.root
+        var = assigned_name(lit)
+        for name in ("InsecureSkipVerify", "MinVersion"):
+            if name not in fields and var is not None:
+                value = field_assignment(scope, var, name)
+                if value is not None:
+                    fields[name] = value
+        found: List[Finding] = []
+        skip = fields.get("InsecureSkipVerify")
+        if skip is not None and skip.text == "true":
+            found.append(
+                finding(
+                    "insecure-skip-verify",
+                    Severity.ERROR,
+                    "tls.Config sets InsecureSkipVerify: true, disabling certificate verification",
+                    f,
+                    lit,
+                )
+            )
+        min_version = fields.get("MinVersion")
+        if min_version is None:
+            message = "tls.Config leaves MinVersion unset; set it to tls.VersionTLS12 or higher"
+        elif (tls_version(min_version) or MIN_VERSION) < MIN_VERSION:
+            message = f"tls.Config allows {min_version.text}; use tls.VersionTLS12 or higher"
+        else:
+            message = None
+        if message is not None:
+            found.append(finding("weak-tls-version", Severity.WARNING, message, f, lit))
+        for item in found:
+            # Point at the literal, not its (multi-line) source.
+            item.expression = None
+        return found
+
+    def _plaintext(self, pkg: PackageInfo) -> List[Finding]:
+        if pkg.name != "main" or NON_PRODUCTION.search(pkg.dir):
+            return []
+        calls = [(f, c) for f in pkg.files for c in iter_calls(f.root)]
+        if any(call_target(c)[1] in TLS_SERVE_CALLS for _, c in calls):
+            return []
+        findings: List[Finding] = []
+        resolver = HandlerResolver(pkg)
+        imports = {f.rel: file_imports(f) for f in pkg.files}
+        for f, call in calls:
+            receiver, name = call_target(call)
+            if name not in PLAIN_SERVE_CALLS or call.line in nolint_lines(f):
+                continue
+            site, addr = self._server(call, imports[f.rel], resolver)
+            if site is None:
+                continue
+            value = string_value(addr, pkg.consts) if addr is not None else None
+            if value is not None and value.split(":", 1)[0].startswith(LOOPBACK):
+                continue
+            package_level = imports[f.rel].get(receiver or "") == "net/http"
+            label = f"{receiver}.{name}" if package_level else name
+            found = finding(
+                "plaintext-server",
+                Severity.INFO,
+                f"{label} serves plain HTTP from a main package; use TLS unless it is "
+                "terminated in front of the service",
+                f,
+                site,
+            )
+            found.expression = None
+            findings.append(found)
+        return findings
+
+    @staticmethod
+    def _server(call: NodeWrapper, imports: Dict[str, str], resolver: HandlerResolver):
+        """(node to report, address expression) for a net/http ListenAndServe call.
+
+        That is `http.ListenAndServe(addr, h)`, or the method on an
+        http.Server; (None, None) for other functions of that name and for
+        receivers whose type cannot be told.
+        """
+        fn = call.field("function")
+        operand = fn.field("operand") if fn is not None else None
+        if fn is None or fn.type != "selector_expression" or operand is None:
+            return None, None
+        scope = enclosing_function(call) or call
+        if operand.type == "identifier" and imports.get(operand.text) == "net/http":
+            if local_type(scope, operand.text) is None and local_value(scope, operand.text) is None:
+                args = call_args(call)
+                return call, (args[0] if args else None)
+        target = strip_address_of(operand)
+        if target.type == "identifier":
+            value = local_value(scope, target.text)
+            target = strip_address_of(value) if value is not None else target
+        if target.type == "composite_literal":
+            typ = target.field("type")
+            if typ is None or not typ.text.endswith("http.Server"):
+                return None, None
+            fields = literal_fields(target)
+            if "TLSConfig" in fields:
+                return None, None
+            return target, fields.get("Addr")
+        # `s.srv.ListenAndServe()`, or a parameter: go by the declared type.
+        qualifier, _, base = (resolver.type_of(operand, scope) or "").rpartition(".")
+        if base == "Server" and imports.get(qualifier) == "net/http":
+            return call, None
+        return None, None
@@ -0,0 +1,140 @@
+# src/crowsight/analyzers/unchecked.py
+
+from typing import List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Finding, Report, Severity
+from ..scanner.goast import call_args, call_target, statements, walk_body
+from ..scanner.handlers import handler_params, is_json_codec, iter_handlers
+from ..scanner.package import GoFile, PackageInfo
+from .common import finding, nolint_lines
+from .registry import Analyzer
+
+# Finding code -> one-line description, used for SARIF rules.
+RULES = {
+    "unchecked-error": "Error from a response write or body operation is ignored",
+    "superfluous-writeheader": "WriteHeader called after the response was already written",
+}
+
+WRITE_HELPERS = ("fmt.Fprint", "fmt.Fprintf", "fmt.Fprintln", "io.WriteString", "io.Copy")
+
+
+def _last_call(node: NodeWrapper) -> Optional[NodeWrapper]:
+    exprs = [c for c in node.named_children if c.type != "comment"]
+    return exprs[0] if len(exprs) == 1 and exprs[0].type == "call_expression" else None
+
+
+class UncheckedErrorAnalyzer(Analyzer):
+    """Flags ignored errors from response writes and body handling in handlers."""
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        for f, fn in iter_handlers(pkg, include_literals=True):
+            body = fn.field("body")
+            if body is None:
+                continue
+            writer, request = handler_params(fn)
+            suppressed = nolint_lines(f)
+            for node in walk_body(body):
+                if node.line in suppressed:
+                    continue
+                found = self._check(node, fn, f, writer, request)
+                if found:
+                    findings.append(found)
+            for node in walk_body(body):
+                if node.type in ("block", "expression_case", "default_case", "type_case"):
+                    findings.extend(self._writeheader_order(node, f, writer, suppressed))
+        return findings
+
+    def _check(self, node, fn, f: GoFile, writer, request) -> Optional[Finding]:
+        if node.type == "expression_statement":
+            call = _last_call(node)
+            if call is not None and self._returns_error(call, writer, request):
+                return finding(
+                    "unchecked-error",
+                    Severity.WARNING,
+                    f"error returned by {call.field('function').text} is ignored",
+                    f,
+                    call,
+                )
+        elif node.type in ("short_var_declaration", "assignment_statement"):
+            right = node.field("right")
+            left = node.field("left")
+            call = _last_call(right) if right is not None else None
+            if call is None or left is None or not self._returns_error(call, writer, request):
+                return None
+            err = left.named_children[-1] if left.named_children else None
+            if err is None or err.type != "identifier" or err.text == "_":
+                return None
+            if not self._used_after(fn, err.text, node):
+                return finding(
+                    "unchecked-error",
+                    Severity.WARNING,
+                    f"error from {call.field('function').text} is assigned to "
+                    f"{err.text} but never checked",
+                    f,
+                    call,
+                )
+        return None
+
+    def _returns_error(self, call: NodeWrapper, writer, request) -> bool:
+        receiver, name = call_target(call)
+        fn_text = call.field("function").text
+        if name == "Encode" and is_json_codec(call, "NewEncoder"):
+            return True
+        if writer and receiver == writer and name == "Write":
+            return True
+        if request and receiver == f"{request}.Body" and name == "Close":
+            return True
+        args = call_args(call)
+        if fn_text in ("io.WriteString", "io.Copy") and args and args[0].text == writer:
+            return True
+        return False
+
+    def _used_after(self, fn: NodeWrapper, name: str, stmt: NodeWrapper) -> bool:
+        end = stmt._node.end_byte
+        for node in fn.descendants():
+            if node.type == "identifier" and node.text == name and node._node.start_byte >= end:
+                return True
+        return False
+
+    def _is_write(self, call: NodeWrapper, writer) -> bool:
+        receiver, name = call_target(call)
+        fn_text = call.field("function").text
+        args = call_args(call)
+        if writer and receiver == writer and name == "Write":
+            return True
+        if name == "Encode" and is_json_codec(call, "NewEncoder"):
+            return True
+        helper = fn_text in WRITE_HELPERS or fn_text == "http.Error"
+        if helper and args and args[0].text == writer:
+            return True
+        return False
+
+    def _writeheader_order(self, block, f: GoFile, writer, suppressed) -> List[Finding]:
+        """WriteHeader after the body (or a second time) is ignored by net/http."""
+        if not writer:
+            return []
+        findings: List[Finding] = []
+        written = header = False
+        for stmt in statements(block):
+            call = _last_call(stmt) if stmt.type == "expression_statement" else None
+            if call is None:
+                continue
+            receiver, name = call_target(call)
+            if receiver == writer and name == "WriteHeader":
+                if (written or header) and call.line not in suppressed:
+                    reason = "after the response body" if written else "twice"
+                    findings.append(
+                        finding(
+                            "superfluous-writeheader",
+                            Severity.WARNING,
+                            f"WriteHeader called {reason}; the status has already been sent",
+                            f,
+                            call,
+                        )
+                    )
+                header = True
+            elif self._is_write(call, writer):
+                written = True
+        return findings
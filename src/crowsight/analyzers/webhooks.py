@@ -0,0 +1,120 @@
+# src/crowsight/analyzers/webhooks.py
+
+from fnmatch import fnmatchcase
+import re
+from typing import Dict, List, Optional, Sequence, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Endpoint, Finding, Report, Severity
+from ..scanner.goast import FUNC_TYPES, STRING_TYPES, call_target, unquote, walk_body
+from ..scanner.package import GoFile, PackageInfo
+from .common import nolint_lines
+from .registry import Analyzer, package_service
+
+RULES = {
+    "webhook-idempotency": "A webhook handler shows no idempotency, dedup or retry handling"
+}
+
+# Case-insensitive globs matched against an endpoint's path and its handler's name.
+DEFAULT_WEBHOOK_PATTERNS = ("*webhook*", "*notify*", "*callback*")
+# Identifiers that suggest a dedup check or retry construct: `store.AlreadyProcessed(id)`,
+# `rdb.SetNX(...)`, `backoff.Retry(...)`.
+IDEMPOTENT_NAMES = re.compile(
+    r"idempot|dedup|duplicate|already|processed|seen|setnx|retry|retries|backoff", re.I
+)
+# String literals that do: idempotency or delivery-id headers, and SQL upserts.
+IDEMPOTENT_STRINGS = re.compile(
+    r"idempot|delivery|request-id|event-id|on\s+conflict|insert\s+ignore|on\s+duplicate", re.I
+)
+
+
+def is_webhook(ep: Endpoint, patterns: Sequence[str]) -> bool:
+    name = (ep.handler or "").split("(", 1)[0].rsplit(".", 1)[-1]
+    candidates = [c.lower() for c in (ep.path, name) if c]
+    return any(fnmatchcase(c, p.lower()) for p in patterns for c in candidates)
+
+
+def handles_idempotency(fn: NodeWrapper) -> bool:
+    """Whether `fn`'s code (not its comments) names a key, dedup check, or retry."""
+    for node in fn.descendants():
+        if node.type in ("identifier", "field_identifier", "type_identifier"):
+            if IDEMPOTENT_NAMES.search(node.text):
+                return True
+        elif node.type in STRING_TYPES and IDEMPOTENT_STRINGS.search(unquote(node.text)):
+            return True
+    return False
+
+
+class WebhookIdempotencyAnalyzer(Analyzer):
+    """Suggests idempotency for webhook handlers that show no sign of it.
+
+    An endpoint is a webhook when its path or handler name matches one of
+    the patterns. Senders retry deliveries, so the handler, or a package
+    function it calls, should read an idempotency key or delivery id,
+    check for duplicates, or retry with backoff itself; this looks for
+    names and strings that suggest any of those.
+    """
+
+    def __init__(self, patterns: Sequence[str] = DEFAULT_WEBHOOK_PATTERNS):
+        self.patterns = list(patterns) or list(DEFAULT_WEBHOOK_PATTERNS)
+
+    def analyze(self, pkg: PackageInfo, report: Report) -> List[Finding]:
+        findings: List[Finding] = []
+        svc = package_service(pkg, report)
+        if svc is None:
+            return findings
+        files = {f.rel: f for f in pkg.files}
+        funcs: Dict[str, NodeWrapper] = {}
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                name = decl.field("name")
+                if decl.type == "function_declaration" and name is not None:
+                    funcs[name.text] = decl
+        by_handler: Dict[Tuple[str, int], List[Endpoint]] = {}
+        for ep in svc.endpoints:
+            if ep.protocol != "http" or ep.handler_file not in files:
+                continue
+            if is_webhook(ep, self.patterns):
+                by_handler.setdefault((ep.handler_file, ep.handler_line), []).append(ep)
+        for (rel, line), endpoints in sorted(by_handler.items()):
+            f = files[rel]
+            fn = self._function_at(f, line)
+            if fn is None or fn.line in nolint_lines(f) or self._idempotent(fn, funcs):
+                continue
+            label = endpoints[0].handler or "inline handler"
+            routes = ", ".join(f"{ep.method} {ep.path}" for ep in endpoints)
+            findings.append(
+                Finding(
+                    code="webhook-idempotency",
+                    severity=Severity.INFO,
+                    message=f"webhook handler {label} ({routes}) shows no idempotency "
+                    "handling; senders retry deliveries, so check an idempotency key or "
+                    "delivery id before acting on one",
+                    file=f.rel,
+                    line=fn.line,
+                )
+            )
+        return findings
+
+    @staticmethod
+    def _idempotent(fn: NodeWrapper, funcs: Dict[str, NodeWrapper]) -> bool:
+        """In the handler, or a package function it calls directly."""
+        if handles_idempotency(fn):
+            return True
+        for call in walk_body(fn):
+            if call.type != "call_expression":
+                continue
+            receiver, name = call_target(call)
+            helper = funcs.get(name) if receiver is None else None
+            if helper is not None and helper != fn and handles_idempotency(helper):
+                return True
+        return False
+
+    @staticmethod
+    def _function_at(f: GoFile, line: int) -> Optional[NodeWrapper]:
+        # The innermost match, for literals that start on their caller's line.
+        found = None
+        for node in f.root.descendants():
+            if node.type in FUNC_TYPES and node.line == line:
+                found = node
+        return found
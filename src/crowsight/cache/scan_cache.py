@@ -0,0 +1,90 @@
+import json
+import hashlib
+from pathlib import Path
+from typing import Any, Dict, List, Optional
+
+from loguru import logger
+
+from ..scanner.fs import OS_FS, FileSystem
+
+CACHE_FILE = "cache.json"
+
+
+class ScanCache:
+    """Per-directory scan results keyed by the content hashes of their Go files.
+
+    Route constants, DTOs and handlers resolve across a package, so a change to
+    any file in a directory invalidates the whole directory's entry; so does a
+    change to a file in another package its DTOs were resolved from. Without a
+    `cache_dir` the cache lives in memory only. Sources are read through `fs`;
+    the cache file itself is always on disk.
+    """
+
+    def __init__(self, cache_dir: Optional[Path], version: str, fs: FileSystem = OS_FS):
+        self.path = cache_dir / CACHE_FILE if cache_dir is not None else None
+        self.version = version
+        self.fs = fs
+        self.data: Dict[str, Any] = {"version": version, "dirs": {}}
+        self.hits = 0
+
+    def load_if_exists(self):
+        if self.path is None or not self.path.exists():
+            return
+        try:
+            data = json.loads(self.path.read_text())
+        except Exception as e:
+            logger.error(f"Failed to load scan cache: {e}")
+            return
+        if data.get("version") != self.version:
+            logger.info(
+                f"Discarding scan cache from analysis version {data.get('version')}"
+            )
+            return
+        self.data = data
+        logger.success(f"Loaded scan cache {self.path}")
+
+    def save(self):
+        if self.path is None:
+            return
+        try:
+            self.path.parent.mkdir(parents=True, exist_ok=True)
+            self.path.write_text(json.dumps(self.data, indent=2))
+            logger.success(f"Saved scan cache to {self.path}")
+        except Exception as e:
+            logger.error(f"Failed to write scan cache: {e}")
+
+    @staticmethod
+    def checksum(path: Path, fs: FileSystem = OS_FS) -> str:
+        return hashlib.sha256(fs.read_bytes(path)).hexdigest()
+
+    def get(
+        self, directory: str, hashes: Dict[str, str], root: Optional[Path] = None
+    ) -> Optional[Dict[str, Any]]:
+        entry = self.data["dirs"].get(directory)
+        if entry and entry.get("files") == hashes and self._deps_current(entry, root):
+            self.hits += 1
+            return entry
+        return None
+
+    def _deps_current(self, entry: Dict[str, Any], root: Optional[Path]) -> bool:
+        """Whether files in other directories the entry's DTOs came from are unchanged."""
+        deps = entry.get("deps") or {}
+        if deps and root is None:
+            return False
+        for rel, digest in deps.items():
+            try:
+                if self.checksum(root / rel, self.fs) != digest:
+                    return False
+            except OSError:
+                return False
+        return True
+
+    def update(self, directory: str, hashes: Dict[str, str], results: Dict[str, Any]):
+        self.data["dirs"][directory] = {"files": hashes, **results}
+
+    def retain(self, directories: List[str]):
+        """Forget directories that no longer contain any Go files."""
+        keep = set(directories)
+        for d in list(self.data["dirs"]):
+            if d not in keep:
+                del self.data["dirs"][d]
@@ -0,0 +1,717 @@
+# src/crowsight/cli.py
+
+import argparse
+from pathlib import Path
+import sys
+import time
+from typing import Any, Callable, Dict, List, Optional
+
+from .analyzers.auth import DEFAULT_AUTH_MIDDLEWARE, DEFAULT_PUBLIC_PATHS
+from .analyzers.complexity import DEFAULT_MAX_COMPLEXITY
+from .analyzers.policy import FindingsPolicy, exit_code
+from .analyzers.registry import registered_analyzers
+from .analyzers.webhooks import DEFAULT_WEBHOOK_PATTERNS
+from .config import CONFIG_FILES, find_config, load_config
+from .report.contract import load_spec, validate
+from .report.diff import ReportDiff, diff
+from .report.models import Report, Severity
+from .render.callgraph import render_callgraph_dot, render_callgraph_json
+from .render.dot import render_dot
+from .render.env import render_env
+from .render.jsonschema import render_jsonschema
+from .render.markdown import render_service_docs
+from .render.mermaid import render_mermaid
+from .render.openapi import render_openapi
+from .render.sarif import render_sarif
+from .render.template import Template, TemplateError
+from .render.todos import render_todos
+from .scanner.build import host_goarch, host_goos
+from .scanner.changes import GitError
+from .scanner.callgraph import DEFAULT_CALLGRAPH_DEPTH
+from .scanner.module import WILDCARD
+from .scanner.profile import format_metrics
+from .scanner.remote import TOKEN_ENV, checkout
+from .scanner.scanner import GoScanner, ScanOptions
+from .scanner.todos import DEFAULT_MARKERS
+from .scanner.watch import DEFAULT_DEBOUNCE, Watcher
+from .server import DEFAULT_ADDR, parse_addr, serve
+from .tui import browse
+
+FORMATS: Dict[str, Callable[[Report], str]] = {
+    "dot": render_dot,
+    "env": render_env,
+    "json": lambda r: r.to_json() + "\n",
+    "jsonschema": render_jsonschema,
+    "mermaid": render_mermaid,
+    "openapi": render_openapi,
+    "sarif": render_sarif,
+    "todos": render_todos,
+    "yaml": lambda r: r.to_yaml(),
+}
+CALLGRAPH_FORMATS: Dict[str, Callable[[Report], str]] = {
+    "dot": render_callgraph_dot,
+    "json": render_callgraph_json,
+}
+# Rendered with the user's --template-file or --template instead of a FORMATS entry.
+TEMPLATE_FORMAT = "template"
+# Formats a saved report can be read back from.
+INPUT_FORMATS = ("auto", "json", "yaml")
+FAIL_ON = (*(s.value for s in Severity), "never")
+GROUP_BY = ("module",)
+# Exit code of a scan that fails --fail-on, unless --warning/--error-exit-code say otherwise.
+DEFAULT_EXIT_CODE = 1
+DEFAULT_DOCS_DIR = "docs/services"
+# Config keys for rendering a report, which commands with their own output skip.
+RENDER_KEYS = ("format", "output", "template_file", "template")
+
+
+def add_paths_argument(p: argparse.ArgumentParser):
+    p.add_argument(
+        "paths",
+        nargs="*",
+        default=["."],
+        metavar="PATH",
+        help="directory, .go file, or go-style package patterns such as ./services/...",
+    )
+
+
+def add_target_arguments(p: argparse.ArgumentParser):
+    add_paths_argument(p)
+    p.add_argument("--format", choices=sorted([*FORMATS, TEMPLATE_FORMAT]), default="json")
+    p.add_argument("-o", "--output", help="write to a file instead of stdout")
+    template = p.add_mutually_exclusive_group()
+    template.add_argument(
+        "--template-file",
+        metavar="FILE",
+        help="Go text/template to render with --format template",
+    )
+    template.add_argument(
+        "--template", metavar="TEXT", help="inline template, instead of --template-file"
+    )
+
+
+def add_scan_arguments(p: argparse.ArgumentParser):
+    """Scan options shared by every command that runs a scan."""
+    p.add_argument(
+        "--config",
+        metavar="FILE",
+        help=f"options file (default: {' or '.join(CONFIG_FILES)} in the scanned directory "
+        "or its module root); command-line flags take precedence",
+    )
+    p.add_argument(
+        "--todo-markers",
+        default=",".join(DEFAULT_MARKERS),
+        help="comma-separated comment markers to collect (default: %(default)s)",
+    )
+    p.add_argument(
+        "-j", "--concurrency", type=int, default=0, help="parser threads (0 = CPUs)"
+    )
+    p.add_argument(
+        "--cache-dir", help="reuse results for unchanged files (e.g. .crowsight)"
+    )
+    p.add_argument(
+        "--max-complexity",
+        type=int,
+        default=DEFAULT_MAX_COMPLEXITY,
+        metavar="N",
+        help="flag handlers above this cyclomatic complexity (default: %(default)s; 0 = off)",
+    )
+    p.add_argument(
+        "--tags",
+        default="",
+        help="comma-separated build tags to satisfy, like `go build -tags`",
+    )
+    p.add_argument("--goos", default=host_goos(), help="target GOOS (default: %(default)s)")
+    p.add_argument(
+        "--goarch", default=host_goarch(), help="target GOARCH (default: %(default)s)"
+    )
+    p.add_argument("--tests", action="store_true", help="include _test.go files")
+    p.add_argument(
+        "--policy",
+        metavar="FILE",
+        help='JSON file mapping finding codes to info/warning/error or "ignore"',
+    )
+    # Set from the config file's `severities:`; a --policy file overrides it per code.
+    p.set_defaults(severities={})
+    p.add_argument(
+        "--enable",
+        action="append",
+        default=[],
+        metavar="NAMES",
+        help="comma-separated analyzers to run besides the defaults; may be repeated "
+        f"(opt-in: {', '.join(n for n, on in registered_analyzers().items() if not on)})",
+    )
+    p.add_argument(
+        "--disable",
+        action="append",
+        default=[],
+        metavar="NAMES",
+        help=f"comma-separated analyzers to skip (built in: {', '.join(registered_analyzers())})",
+    )
+    p.add_argument(
+        "--since",
+        metavar="REF",
+        help="only report findings in files changed since this git ref (e.g. origin/main)",
+    )
+    p.add_argument(
+        "--profile",
+        action="store_true",
+        help="time each scan phase and analyzer; adds `metrics` to the report",
+    )
+    p.add_argument(
+        "--auth-middleware",
+        action="append",
+        default=[],
+        metavar="NAMES",
+        help="comma-separated globs naming auth middleware, for --enable auth "
+        f"(default: {','.join(DEFAULT_AUTH_MIDDLEWARE)})",
+    )
+    p.add_argument(
+        "--public-paths",
+        action="append",
+        default=[],
+        metavar="GLOBS",
+        help="comma-separated route globs that need no auth "
+        f"(default: {','.join(DEFAULT_PUBLIC_PATHS)})",
+    )
+    p.add_argument(
+        "--secret-allowlist",
+        action="append",
+        default=[],
+        metavar="REGEX",
+        help="don't report secrets whose file path or value matches this; may be repeated",
+    )
+    p.add_argument(
+        "--webhook-patterns",
+        action="append",
+        default=[],
+        metavar="GLOBS",
+        help="comma-separated globs naming webhook routes or handlers "
+        f"(default: {','.join(DEFAULT_WEBHOOK_PATTERNS)})",
+    )
+    p.add_argument(
+        "--allow-trailing-slash",
+        dest="trailing_slash_paths",
+        action="append",
+        default=[],
+        metavar="GLOBS",
+        help="comma-separated route globs that may be registered with and without a "
+        "trailing slash",
+    )
+    p.add_argument(
+        "--forbid-import",
+        dest="forbid_imports",
+        action="append",
+        default=[],
+        metavar="FROM:TO",
+        help="packages matching glob FROM may not import packages matching TO; may be repeated",
+    )
+    p.add_argument(
+        "--exclude",
+        action="append",
+        default=[],
+        metavar="PATTERN",
+        help="gitignore-style pattern to skip; may be repeated",
+    )
+
+
+def add_debounce_argument(p: argparse.ArgumentParser):
+    p.add_argument(
+        "--debounce",
+        type=int,
+        default=int(DEFAULT_DEBOUNCE * 1000),
+        metavar="MS",
+        help="quiet period before rescanning (default: %(default)sms)",
+    )
+
+
+def build_parser(defaults: Optional[Dict[str, Any]] = None) -> argparse.ArgumentParser:
+    """The CLI; `defaults` (from a config file) replace the built-in defaults of scan options."""
+    defaults = defaults or {}
+    parser = argparse.ArgumentParser(prog="crowsight")
+    parser.add_argument("--log-level", default="WARNING")
+    parser.add_argument(
+        "--log-format",
+        choices=("text", "json"),
+        default="text",
+        help="text, or one JSON object per log line for tooling (default: %(default)s)",
+    )
+    sub = parser.add_subparsers(dest="command", required=True)
+
+    p_scan = sub.add_parser("scan", help="scan Go services and print a report")
+    add_target_arguments(p_scan)
+    add_scan_arguments(p_scan)
+    p_scan.add_argument(
+        "--fail-on",
+        choices=FAIL_ON,
+        default=Severity.ERROR.value,
+        help="fail if any finding is at least this severe (default: %(default)s)",
+    )
+    p_scan.add_argument(
+        "--warning-exit-code",
+        type=int,
+        default=DEFAULT_EXIT_CODE,
+        metavar="N",
+        help="exit code when the worst failing finding is a warning or info "
+        "(default: %(default)s)",
+    )
+    p_scan.add_argument(
+        "--error-exit-code",
+        type=int,
+        default=DEFAULT_EXIT_CODE,
+        metavar="N",
+        help="exit code when a failing finding is an error (default: %(default)s)",
+    )
+    p_scan.add_argument(
+        "--group-by",
+        choices=GROUP_BY,
+        help="also list services under their go.mod module (`modules` in the report)",
+    )
+    p_scan.add_argument(
+        "--repo",
+        metavar="URL",
+        help="shallow-clone this Git repository into a temporary directory and scan it; "
+        "PATHs are then inside the clone (private HTTPS repos: credential helpers "
+        f"or ${TOKEN_ENV})",
+    )
+    p_scan.add_argument(
+        "--ref", help="branch, tag or commit of --repo to scan (default: its HEAD)"
+    )
+    p_scan.add_argument(
+        "--callgraph",
+        choices=sorted(CALLGRAPH_FORMATS),
+        help="print the functions each endpoint's handler reaches, instead of the report",
+    )
+    p_scan.add_argument(
+        "--callgraph-depth",
+        type=int,
+        default=DEFAULT_CALLGRAPH_DEPTH,
+        metavar="N",
+        help="how many calls deep --callgraph follows (default: %(default)s)",
+    )
+    p_scan.set_defaults(func=cmd_scan, **defaults)
+
+    p_watch = sub.add_parser(
+        "watch", help="re-scan on file changes and print what changed"
+    )
+    add_target_arguments(p_watch)
+    add_scan_arguments(p_watch)
+    add_debounce_argument(p_watch)
+    p_watch.set_defaults(func=cmd_watch, **defaults)
+
+    p_serve = sub.add_parser(
+        "serve", help="serve the live report over HTTP, rescanning on changes"
+    )
+    p_serve.add_argument("--root", default=".", help="directory to scan (default: .)")
+    p_serve.add_argument(
+        "--addr", default=DEFAULT_ADDR, help="host:port to listen on (default: %(default)s)"
+    )
+    add_scan_arguments(p_serve)
+    add_debounce_argument(p_serve)
+    p_serve.set_defaults(func=cmd_serve, **defaults)
+
+    p_docgen = sub.add_parser(
+        "docgen", help="write a starter Markdown doc per service into a directory"
+    )
+    add_paths_argument(p_docgen)
+    p_docgen.add_argument(
+        "-o",
+        "--output-dir",
+        default=DEFAULT_DOCS_DIR,
+        metavar="DIR",
+        help="directory to write <service>.md files to (default: %(default)s)",
+    )
+    p_docgen.add_argument(
+        "--force",
+        action="store_true",
+        help="overwrite existing files; otherwise they are kept and the exit code is 1",
+    )
+    add_scan_arguments(p_docgen)
+    p_docgen.set_defaults(func=cmd_docgen, **defaults)
+
+    p_diff = sub.add_parser(
+        "diff", help="compare two saved reports; exits 1 if endpoints were removed"
+    )
+    p_diff.add_argument("old")
+    p_diff.add_argument("new")
+    p_diff.add_argument("--format", choices=("text", "json"), default="text")
+    p_diff.add_argument(
+        "--input-format",
+        choices=INPUT_FORMATS,
+        default="auto",
+        help="format of the saved reports; auto goes by extension, then content",
+    )
+    p_diff.add_argument("-o", "--output", help="write to a file instead of stdout")
+    p_diff.set_defaults(func=cmd_diff)
+
+    p_validate = sub.add_parser(
+        "validate", help="check routes against an OpenAPI spec; exits 1 on any drift"
+    )
+    p_validate.add_argument(
+        "--spec", metavar="FILE", help="OpenAPI document to check against (JSON or YAML)"
+    )
+    p_validate.add_argument("--root", default=".", help="directory to scan (default: .)")
+    p_validate.add_argument("--format", choices=("text", "json"), default="text")
+    p_validate.add_argument("-o", "--output", help="write to a file instead of stdout")
+    add_scan_arguments(p_validate)
+    p_validate.set_defaults(
+        func=cmd_validate, **{k: v for k, v in defaults.items() if k not in RENDER_KEYS}
+    )
+
+    p_hash = sub.add_parser(
+        "hash", help="print the report's content hash, which only changes with the code"
+    )
+    p_hash.add_argument("--root", default=".", help="directory to scan (default: .)")
+    add_scan_arguments(p_hash)
+    p_hash.set_defaults(
+        func=cmd_hash, **{k: v for k, v in defaults.items() if k not in RENDER_KEYS}
+    )
+
+    p_tui = sub.add_parser(
+        "tui", help="browse services, endpoints and findings in the terminal"
+    )
+    p_tui.add_argument("--root", default=".", help="directory to scan (default: .)")
+    p_tui.add_argument(
+        "--report",
+        metavar="FILE",
+        help="browse a saved JSON or YAML report instead of scanning --root",
+    )
+    add_scan_arguments(p_tui)
+    p_tui.set_defaults(
+        func=cmd_tui, **{k: v for k, v in defaults.items() if k not in RENDER_KEYS}
+    )
+    return parser
+
+
+def emit(text: str, output: Optional[str]):
+    if output:
+        with open(output, "w") as fh:
+            fh.write(text)
+    else:
+        sys.stdout.write(text)
+
+
+def split_names(values: List[str]) -> List[str]:
+    return [n.strip() for v in values for n in v.split(",") if n.strip()]
+
+
+def config_defaults(args) -> Dict[str, Any]:
+    """Option defaults from --config or the scan target's crowsight.yaml, if any."""
+    if not hasattr(args, "config"):
+        return {}
+    # Only a config file found in a clone is untrusted; --config is the user's own.
+    checkout = None
+    if args.config:
+        path: Optional[Path] = Path(args.config)
+    else:
+        paths = getattr(args, "paths", None) or [getattr(args, "root", ".")]
+        single = len(paths) == 1 and WILDCARD not in paths[0]
+        checkout = getattr(args, "checkout", None)
+        path = find_config((checkout or Path(".")) / (paths[0] if single else "."))
+    if path is None:
+        return {}
+    try:
+        defaults = load_config(path, checkout)
+        if defaults.get("format", "json") not in (*FORMATS, TEMPLATE_FORMAT):
+            raise ValueError(f"format: unknown format {defaults['format']!r}")
+        if defaults.get("fail_on", "error") not in FAIL_ON:
+            raise ValueError(f"fail_on: expected one of {', '.join(FAIL_ON)}")
+        if defaults.get("group_by", GROUP_BY[0]) not in GROUP_BY:
+            raise ValueError(f"group_by: expected one of {', '.join(GROUP_BY)}")
+    except (OSError, ValueError) as e:
+        print(f"crowsight: invalid config {path}: {e}", file=sys.stderr)
+        raise SystemExit(2)
+    return defaults
+
+
+def scan_options(args) -> ScanOptions:
+    markers = [m.strip() for m in args.todo_markers.split(",") if m.strip()]
+    severities = dict(args.severities)
+    if args.policy:
+        try:
+            severities.update(FindingsPolicy.load(args.policy).overrides)
+        except (OSError, ValueError) as e:
+            print(f"crowsight: invalid policy {args.policy}: {e}", file=sys.stderr)
+            raise SystemExit(2)
+    return ScanOptions(
+        todo_markers=markers,
+        concurrency=args.concurrency,
+        exclude=args.exclude,
+        cache_dir=args.cache_dir,
+        max_complexity=args.max_complexity,
+        build_tags=[t.strip() for t in args.tags.split(",") if t.strip()],
+        goos=args.goos,
+        goarch=args.goarch,
+        include_tests=args.tests,
+        severities=severities,
+        enable=split_names(args.enable),
+        disable=split_names(args.disable),
+        since=args.since,
+        auth_middleware=split_names(args.auth_middleware) or list(DEFAULT_AUTH_MIDDLEWARE),
+        public_paths=split_names(args.public_paths) or list(DEFAULT_PUBLIC_PATHS),
+        secret_allowlist=args.secret_allowlist,
+        webhook_patterns=split_names(args.webhook_patterns) or list(DEFAULT_WEBHOOK_PATTERNS),
+        trailing_slash_paths=split_names(args.trailing_slash_paths),
+        forbid_imports=args.forbid_imports,
+        callgraph_depth=args.callgraph_depth if getattr(args, "callgraph", None) else 0,
+        profile=args.profile,
+    )
+
+
+def make_renderer(args) -> Callable[[Report], str]:
+    """The --format renderer; a template is parsed up front so errors show before the scan."""
+    has_template = args.template is not None or args.template_file is not None
+    if args.format != TEMPLATE_FORMAT:
+        if has_template:
+            print(
+                "crowsight: --template and --template-file need --format template",
+                file=sys.stderr,
+            )
+            raise SystemExit(2)
+        return FORMATS[args.format]
+    if not has_template:
+        print("crowsight: --format template needs --template-file or --template", file=sys.stderr)
+        raise SystemExit(2)
+    try:
+        if args.template_file is not None:
+            template = Template(Path(args.template_file).read_text(), args.template_file)
+        else:
+            template = Template(args.template, "--template")
+    except (OSError, TemplateError) as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        raise SystemExit(2)
+
+    def render(report: Report) -> str:
+        try:
+            return template.execute(report)
+        except TemplateError as e:
+            print(f"crowsight: {e}", file=sys.stderr)
+            raise SystemExit(2)
+
+    return render
+
+
+def make_scanner(args) -> GoScanner:
+    options = scan_options(args)
+    # With --repo, paths are relative to the clone.
+    base = getattr(args, "checkout", None) or Path(".")
+    try:
+        if len(args.paths) == 1 and WILDCARD not in args.paths[0]:
+            return GoScanner(str(base / args.paths[0]), options)
+        return GoScanner(str(base), options, args.paths)
+    except ValueError as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        raise SystemExit(2)
+
+
+def cmd_scan(args) -> int:
+    render = CALLGRAPH_FORMATS[args.callgraph] if args.callgraph else make_renderer(args)
+    report = make_scanner(args).scan()
+    if args.group_by == "module":
+        report.group_by_module()
+    started = time.perf_counter()
+    text = render(report)
+    if report.metrics is not None:
+        report.metrics.phases["render"] = round(time.perf_counter() - started, 4)
+        # Rendering is timed after the fact, so only this breakdown includes it.
+        print(format_metrics(report.metrics), end="", file=sys.stderr)
+    emit(text, args.output)
+    print(f"crowsight: {report.summary.line()}", file=sys.stderr)
+    threshold = None if args.fail_on == "never" else Severity(args.fail_on)
+    codes = {
+        Severity.INFO: args.warning_exit_code,
+        Severity.WARNING: args.warning_exit_code,
+        Severity.ERROR: args.error_exit_code,
+    }
+    return exit_code(report.findings, threshold, codes)
+
+
+def cmd_watch(args) -> int:
+    render = make_renderer(args)
+
+    def on_report(report: Report, changes: Optional[ReportDiff]):
+        # With -o the full report is rewritten on every refresh.
+        if args.output:
+            emit(render(report), args.output)
+        stamp = time.strftime("%H:%M:%S")
+        if changes is None:
+            print(
+                f"[{stamp}] watching {report.root}: {len(report.services)} services, "
+                f"{len(report.endpoints)} endpoints, {len(report.todos)} TODOs",
+                flush=True,
+            )
+        elif not changes.empty:
+            for line in changes.to_text().splitlines():
+                print(f"[{stamp}] {line}", flush=True)
+
+    watcher = Watcher(
+        make_scanner(args), debounce=args.debounce / 1000, reload=reloaded(args, make_scanner)
+    )
+    try:
+        watcher.run(on_report)
+    except KeyboardInterrupt:
+        pass
+    return 0
+
+
+def cmd_serve(args) -> int:
+    try:
+        host, port = parse_addr(args.addr)
+        scanner = GoScanner(args.root, scan_options(args))
+    except ValueError as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        return 2
+    print(f"Serving {args.root} on http://{host or 'localhost'}:{port}/", file=sys.stderr)
+    try:
+        reload = reloaded(args, lambda a: GoScanner(a.root, scan_options(a)))
+        serve(scanner, args.addr, args.debounce / 1000, reload)
+    except KeyboardInterrupt:
+        pass
+    return 0
+
+
+def cmd_docgen(args) -> int:
+    report = make_scanner(args).scan()
+    out = Path(args.output_dir)
+    try:
+        out.mkdir(parents=True, exist_ok=True)
+    except OSError as e:
+        print(f"crowsight: cannot create {out}: {e}", file=sys.stderr)
+        return 2
+    kept = 0
+    for name, text in render_service_docs(report).items():
+        path = out / name
+        if path.exists() and not args.force:
+            # Stubs are meant to be edited by hand; never clobber them silently.
+            print(f"crowsight: {path} exists, not overwriting (use --force)", file=sys.stderr)
+            kept += 1
+            continue
+        path.write_text(text)
+        print(f"wrote {path}", file=sys.stderr)
+    return 1 if kept else 0
+
+
+def load_report(path: str, input_format: str = "auto") -> Report:
+    """Read a report saved as JSON or YAML (`--format json` / `--format yaml`)."""
+    with open(path) as fh:
+        text = fh.read()
+    if input_format == "auto":
+        if path.endswith((".yaml", ".yml")):
+            input_format = "yaml"
+        elif path.endswith(".json") or text.lstrip().startswith("{"):
+            input_format = "json"
+        else:
+            input_format = "yaml"
+    if input_format == "yaml":
+        return Report.from_yaml(text)
+    return Report.from_json(text)
+
+
+def cmd_diff(args) -> int:
+    try:
+        old = load_report(args.old, args.input_format)
+        new = load_report(args.new, args.input_format)
+    except (OSError, ValueError) as e:
+        print(f"crowsight: cannot load report: {e}", file=sys.stderr)
+        return 2
+    result = diff(old, new)
+    emit(result.to_json() + "\n" if args.format == "json" else result.to_text(), args.output)
+    return 1 if result.removed else 0
+
+
+def cmd_validate(args) -> int:
+    if not args.spec:
+        print("crowsight: validate needs --spec (or `spec` in the config file)", file=sys.stderr)
+        return 2
+    try:
+        spec = load_spec(args.spec)
+    except (OSError, ValueError) as e:
+        print(f"crowsight: cannot load spec: {e}", file=sys.stderr)
+        return 2
+    try:
+        scanner = GoScanner(args.root, scan_options(args))
+    except ValueError as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        return 2
+    drift = validate(scanner.scan(), spec)
+    emit(drift.to_json() + "\n" if args.format == "json" else drift.to_text(), args.output)
+    return 0 if drift.empty else 1
+
+
+def cmd_hash(args) -> int:
+    try:
+        scanner = GoScanner(args.root, scan_options(args))
+    except ValueError as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        return 2
+    print(scanner.scan().hash)
+    return 0
+
+
+def cmd_tui(args) -> int:
+    if not (sys.stdin.isatty() and sys.stdout.isatty()):
+        print("crowsight: tui needs a terminal", file=sys.stderr)
+        return 2
+    try:
+        if args.report:
+            report = load_report(args.report)
+        else:
+            report = GoScanner(args.root, scan_options(args)).scan()
+    except (OSError, ValueError) as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        return 2
+    try:
+        browse(report)
+    except KeyboardInterrupt:
+        pass
+    return 0
+
+
+def main(argv: Optional[List[str]] = None) -> int:
+    from . import configure_logger
+
+    args = build_parser().parse_args(argv)
+    # Logs go to stderr so the report on stdout stays machine-readable.
+    configure_logger(
+        level=args.log_level, sink=sys.stderr, serialize=args.log_format == "json"
+    )
+    if getattr(args, "ref", None) and not args.repo:
+        print("crowsight: --ref needs --repo", file=sys.stderr)
+        return 2
+    if not getattr(args, "repo", None):
+        return run(args, argv)
+    try:
+        with checkout(args.repo, args.ref) as clone:
+            return run(args, argv, clone)
+    except GitError as e:
+        print(f"crowsight: {e}", file=sys.stderr)
+        return 2
+
+
+def run(args, argv: Optional[List[str]], clone: Optional[Path] = None) -> int:
+    """Run the command, with config-file defaults found in the scanned tree (or `clone`)."""
+    args.checkout, args.argv = clone, argv
+    # Precedence: command-line flags, then the config file, then built-in defaults.
+    defaults = config_defaults(args)
+    if defaults:
+        args = build_parser(defaults).parse_args(argv)
+        args.checkout, args.argv = clone, argv
+    return args.func(args)
+
+
+def reloaded(args, make: Callable[[Any], GoScanner]) -> Callable[[], Optional[GoScanner]]:
+    """For watchers: a scanner built from the config file as it is now, or None."""
+
+    def reload() -> Optional[GoScanner]:
+        try:
+            fresh = build_parser(config_defaults(args)).parse_args(args.argv)
+            fresh.checkout, fresh.argv = args.checkout, args.argv
+            return make(fresh)
+        except ValueError as e:
+            print(f"crowsight: {e}", file=sys.stderr)
+        except SystemExit:
+            # The reason was printed already.
+            pass
+        return None
+
+    return reload
@@ -0,0 +1,177 @@
+# src/crowsight/config.py
+
+from pathlib import Path
+from typing import Any, Callable, Dict, List, Optional
+
+from loguru import logger
+import yaml
+
+from .scanner.module import find_module
+
+CONFIG_FILES = ("crowsight.yaml", ".crowsight.yaml")
+
+
+def _text(key: str, value: Any) -> str:
+    if isinstance(value, (dict, list)) or value is None:
+        raise ValueError(f"{key}: expected a string")
+    return str(value)
+
+
+def _integer(key: str, value: Any) -> int:
+    if isinstance(value, bool) or not isinstance(value, int):
+        raise ValueError(f"{key}: expected an integer")
+    return value
+
+
+def _flag(key: str, value: Any) -> bool:
+    if not isinstance(value, bool):
+        raise ValueError(f"{key}: expected true or false")
+    return value
+
+
+def _names(key: str, value: Any) -> List[str]:
+    """A list, or a comma-separated string, of names."""
+    if isinstance(value, str):
+        value = value.split(",")
+    if not isinstance(value, list) or any(isinstance(v, (dict, list)) for v in value):
+        raise ValueError(f"{key}: expected a list of strings")
+    return [str(v).strip() for v in value if v is not None and str(v).strip()]
+
+
+def _patterns(key: str, value: Any) -> List[str]:
+    """A list of regexes, or a single one; unlike names, never split on commas."""
+    if isinstance(value, str):
+        value = [value]
+    if not isinstance(value, list) or any(isinstance(v, (dict, list)) for v in value):
+        raise ValueError(f"{key}: expected a regex or a list of regexes")
+    return [str(v) for v in value if v is not None and str(v)]
+
+
+def _joined(key: str, value: Any) -> str:
+    return ",".join(_names(key, value))
+
+
+def _import_rules(key: str, value: Any) -> List[str]:
+    """A list of "FROM:TO" strings or {from, to} mappings, as "FROM:TO" strings."""
+    if isinstance(value, (str, dict)):
+        value = [value]
+    if not isinstance(value, list):
+        raise ValueError(f"{key}: expected a list of import rules")
+    rules = []
+    for v in value:
+        if isinstance(v, dict):
+            if set(v) != {"from", "to"} or any(isinstance(x, (dict, list)) for x in v.values()):
+                raise ValueError(f"{key}: expected rules with `from` and `to` globs")
+            v = f"{v['from']}:{v['to']}"
+        elif not isinstance(v, str):
+            raise ValueError(f"{key}: expected a list of import rules")
+        rules.append(v)
+    return rules
+
+
+def _levels(key: str, value: Any) -> Dict[str, str]:
+    if not isinstance(value, dict):
+        raise ValueError(f"{key}: expected a mapping of finding codes to levels")
+    return {str(k): _text(f"{key}.{k}", v).lower() for k, v in value.items()}
+
+
+# Config key (the argparse destination of the matching flag) -> converter. The
+# file's values become the command's defaults, so flags given on the command
+# line still win; repeatable flags (exclude, enable, ...) add to the file's
+# list instead of replacing it.
+KEYS: Dict[str, Callable[[str, Any], Any]] = {
+    "format": _text,
+    "output": _text,
+    "template_file": _text,
+    "template": _text,
+    "exclude": _names,
+    "enable": _names,
+    "disable": _names,
+    "max_complexity": _integer,
+    "todo_markers": _joined,
+    "tags": _joined,
+    "goos": _text,
+    "goarch": _text,
+    "tests": _flag,
+    "concurrency": _integer,
+    "cache_dir": _text,
+    "policy": _text,
+    "severities": _levels,
+    "since": _text,
+    "profile": _flag,
+    "auth_middleware": _names,
+    "public_paths": _names,
+    "secret_allowlist": _patterns,
+    "webhook_patterns": _names,
+    "trailing_slash_paths": _names,
+    "forbid_imports": _import_rules,
+    "fail_on": _text,
+    "warning_exit_code": _integer,
+    "error_exit_code": _integer,
+    "group_by": _text,
+    "callgraph_depth": _integer,
+    "debounce": _integer,
+    "addr": _text,
+    "spec": _text,
+}
+# Paths in the file are relative to the file, not to where crowsight runs.
+PATH_KEYS = ("output", "template_file", "cache_dir", "policy", "spec")
+# Not taken from a cloned repository's config (`--repo`): they choose what is
+# written where, or which local files are read, rather than how code is analyzed.
+CHECKOUT_IGNORED = ("output", "template_file", "template", "cache_dir", "addr")
+
+
+def _inside(path: Path, root: Path) -> bool:
+    resolved, base = path.resolve(), root.resolve()
+    return resolved == base or base in resolved.parents
+
+
+def find_config(target: Path) -> Optional[Path]:
+    """crowsight.yaml or .crowsight.yaml in the scan target's directory, else its module root."""
+    start = target.parent if target.is_file() else target
+    dirs = [start]
+    module = find_module(start) if start.exists() else None
+    if module is not None and module.root.resolve() != start.resolve():
+        dirs.append(module.root)
+    for d in dirs:
+        for name in CONFIG_FILES:
+            if (d / name).is_file():
+                return d / name
+    return None
+
+
+def load_config(path: Path, checkout: Optional[Path] = None) -> Dict[str, Any]:
+    """Read a config file into argparse defaults (see KEYS).
+
+    Keys may be written with dashes or underscores; relative paths are
+    resolved against the file's directory. Unknown keys are logged
+    and skipped so that configs written for newer versions keep working;
+    values of the wrong type raise ValueError.
+
+    A config found in a `checkout` of someone else's repository is not
+    trusted: CHECKOUT_IGNORED keys are skipped, and paths must stay inside it.
+    """
+    try:
+        data = yaml.safe_load(path.read_text())
+    except yaml.YAMLError as e:
+        raise ValueError(f"invalid YAML: {e}")
+    if data is None:
+        return {}
+    if not isinstance(data, dict):
+        raise ValueError("expected a mapping of option names to values")
+    defaults: Dict[str, Any] = {}
+    for raw, value in data.items():
+        key = str(raw).replace("-", "_")
+        if key not in KEYS:
+            logger.warning(f"{path}: ignoring unknown option {raw!r}")
+            continue
+        if checkout is not None and key in CHECKOUT_IGNORED:
+            logger.warning(f"{path}: ignoring {raw!r} in a cloned repository's config")
+            continue
+        defaults[key] = KEYS[key](str(raw), value)
+        if key in PATH_KEYS:
+            defaults[key] = str(path.parent / defaults[key])
+            if checkout is not None and not _inside(Path(defaults[key]), checkout):
+                raise ValueError(f"{raw}: {value!r} is outside the cloned repository")
+    logger.info(f"Loaded {len(defaults)} options from {path}")
+    return defaults
@@ -10,6 +10,12 @@ class NodeWrapper:
         self.source = source
         logger.trace(f"Wrapped node {node.type}")
 
+    def __eq__(self, other):
+        return isinstance(other, NodeWrapper) and self._node == other._node
+
+    def __hash__(self):
+        return hash(self._node)
+
     @property
     def type(self) -> str:
         return self._node.type
@@ -18,10 +24,37 @@ class NodeWrapper:
     def text(self) -> str:
         return self.source[self._node.start_byte : self._node.end_byte].decode("utf8")
 
+    @property
+    def has_error(self) -> bool:
+        return self._node.has_error
+
+    @property
+    def line(self) -> int:
+        """1-based line the node starts on."""
+        return self._node.start_point[0] + 1
+
+    @property
+    def column(self) -> int:
+        """1-based column the node starts on."""
+        return self._node.start_point[1] + 1
+
+    @property
+    def end_line(self) -> int:
+        return self._node.end_point[0] + 1
+
+    @property
+    def parent(self):
+        p = self._node.parent
+        return NodeWrapper(p, self.source) if p else None
+
     @property
     def children(self):
         return [NodeWrapper(c, self.source) for c in self._node.children]
 
+    @property
+    def named_children(self):
+        return [NodeWrapper(c, self.source) for c in self._node.named_children]
+
     def descendants(self):
         yield self
         for child in self.children:
@@ -30,3 +63,8 @@ class NodeWrapper:
     def field(self, name: str):
         c = self._node.child_by_field_name(name)
         return NodeWrapper(c, self.source) if c else None
+
+    def fields(self, name: str):
+        return [
+            NodeWrapper(c, self.source) for c in self._node.children_by_field_name(name)
+        ]
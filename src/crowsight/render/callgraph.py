@@ -0,0 +1,64 @@
+# src/crowsight/render/callgraph.py
+
+import json
+from typing import Any, Dict, List
+
+from ..report.models import Report
+from .dot import node_id, quote
+
+STDLIB_STYLE = 'shape=plaintext, fontcolor="#666666"'
+
+
+def render_callgraph_json(report: Report) -> str:
+    """One entry per endpoint with a recorded call graph: its handler and the calls it reaches."""
+    graphs: List[Dict[str, Any]] = []
+    for svc in report.services:
+        for ep in svc.endpoints:
+            if not ep.call_graph:
+                continue
+            graphs.append(
+                {
+                    "service": svc.name,
+                    "method": ep.method,
+                    "path": ep.path,
+                    "handler": ep.call_graph[0].caller,
+                    "calls": [
+                        {
+                            "caller": e.caller,
+                            "callee": e.callee,
+                            "file": e.file,
+                            "line": e.line,
+                            "stdlib": e.stdlib,
+                        }
+                        for e in ep.call_graph
+                    ],
+                }
+            )
+    return json.dumps(graphs, indent=2) + "\n"
+
+
+def render_callgraph_dot(report: Report) -> str:
+    """Render each endpoint's call graph as a cluster; standard library calls are leaves."""
+    lines: List[str] = ["digraph callgraph {", "    rankdir=LR;", "    node [fontsize=10];"]
+    for si, svc in enumerate(report.services):
+        for ei, ep in enumerate(svc.endpoints):
+            if not ep.call_graph:
+                continue
+            prefix = f"svc{si}_ep{ei}"
+            root = ep.call_graph[0].caller
+            lines.append(f"    subgraph {quote('cluster_' + prefix)} {{")
+            lines.append(f"        label={quote(f'{svc.name}: {ep.method} {ep.path}')};")
+            # Labels can differ only in punctuation, so nodes are numbered instead.
+            ids = {root: node_id(prefix, "0")}
+            lines.append(f"        {ids[root]} [label={quote(root)}, shape=box, style=bold];")
+            for e in ep.call_graph:
+                if e.callee in ids:
+                    continue
+                ids[e.callee] = node_id(prefix, str(len(ids)))
+                style = STDLIB_STYLE if e.stdlib else "shape=box"
+                lines.append(f"        {ids[e.callee]} [label={quote(e.callee)}, {style}];")
+            for e in ep.call_graph:
+                lines.append(f"        {ids[e.caller]} -> {ids[e.callee]};")
+            lines.append("    }")
+    lines.append("}")
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,64 @@
+# src/crowsight/render/dot.py
+
+import re
+from typing import Dict, List, Optional, Tuple
+
+from ..report.models import Endpoint, OutboundCall, Report, Service
+
+# Node and edge attributes per endpoint protocol.
+NODE_STYLES = {"http": "shape=box", "grpc": 'shape=box, style="rounded,dashed"'}
+EDGE_STYLES = {"http": "style=solid", "grpc": 'style=dashed, color="#1f77b4"'}
+
+
+def quote(text: str) -> str:
+    """A DOT double-quoted string; safe for slashes, braces and quotes."""
+    escaped = text.replace("\\", "\\\\").replace('"', '\\"').replace("\n", "\\n")
+    return f'"{escaped}"'
+
+
+def node_id(*parts: str) -> str:
+    return quote("_".join(re.sub(r"\W", "_", p) for p in parts))
+
+
+def _target(
+    services: Dict[str, Tuple[int, Service]], call: OutboundCall
+) -> Optional[Tuple[int, int, Endpoint]]:
+    """(service index, endpoint index, endpoint) an outbound call was linked to."""
+    if call.service_dir not in services or not call.endpoint:
+        return None
+    si, svc = services[call.service_dir]
+    for ei, ep in enumerate(svc.endpoints):
+        if ep.path == call.endpoint and (not call.method or ep.method in ("ANY", call.method)):
+            return si, ei, ep
+    return None
+
+
+def render_dot(report: Report) -> str:
+    """Render services as clusters, endpoints as nodes and linked calls as edges."""
+    lines: List[str] = ["digraph crowsight {", "    rankdir=LR;", "    node [fontsize=10];"]
+    for si, svc in enumerate(report.services):
+        sid = f"svc{si}"
+        lines.append(f"    subgraph {quote('cluster_' + sid)} {{")
+        lines.append(f"        label={quote(svc.name)};")
+        # Edges start at the service node, so services without endpoints still connect.
+        lines.append(f"        {node_id(sid)} [label={quote(svc.name)}, shape=component];")
+        for ei, ep in enumerate(svc.endpoints):
+            style = NODE_STYLES.get(ep.protocol, NODE_STYLES["http"])
+            label = quote(f"{ep.method} {ep.path}")
+            lines.append(f"        {node_id(sid, 'ep', str(ei))} [label={label}, {style}];")
+        lines.append("    }")
+
+    by_dir = {svc.dir: (si, svc) for si, svc in enumerate(report.services)}
+    edges: Dict[Tuple[int, int, int], Endpoint] = {}
+    for si, svc in enumerate(report.services):
+        for call in svc.outbound_calls:
+            found = _target(by_dir, call)
+            if found is not None:
+                ti, ei, ep = found
+                edges.setdefault((si, ti, ei), ep)
+    for (si, ti, ei), ep in sorted(edges.items()):
+        style = EDGE_STYLES.get(ep.protocol, EDGE_STYLES["http"])
+        target = node_id(f"svc{ti}", "ep", str(ei))
+        lines.append(f"    {node_id(f'svc{si}')} -> {target} [{style}];")
+    lines.append("}")
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,23 @@
+# src/crowsight/render/env.py
+
+from typing import List
+
+from ..report.models import Report
+
+
+def render_env(report: Report) -> str:
+    """A `.env.example` skeleton with one empty assignment per discovered key."""
+    out: List[str] = []
+    for svc in report.services:
+        if not svc.env_vars:
+            continue
+        out.append(f"# {svc.name} ({svc.dir})")
+        for var in svc.env_vars:
+            where = ", ".join(f"{u.file}:{u.line}" for u in var.usages)
+            if not var.resolved:
+                out.append(f"# unresolved {var.name} at {where}")
+                continue
+            out.append(f"# {where}")
+            out.append(f"{var.name}=")
+        out.append("")
+    return "\n".join(out)
@@ -0,0 +1,141 @@
+# src/crowsight/render/goschema.py
+
+from typing import Any, Dict, List, Optional, Tuple
+
+from ..report.models import Report, Struct
+
+Schema = Dict[str, Any]
+
+PRIMITIVES: Dict[str, Schema] = {
+    "string": {"type": "string"},
+    "bool": {"type": "boolean"},
+    "int": {"type": "integer"},
+    "int8": {"type": "integer"},
+    "int16": {"type": "integer"},
+    "int32": {"type": "integer", "format": "int32"},
+    "int64": {"type": "integer", "format": "int64"},
+    "uint": {"type": "integer", "minimum": 0},
+    "uint8": {"type": "integer", "minimum": 0},
+    "uint16": {"type": "integer", "minimum": 0},
+    "uint32": {"type": "integer", "minimum": 0},
+    "uint64": {"type": "integer", "minimum": 0},
+    "byte": {"type": "integer", "minimum": 0},
+    "rune": {"type": "integer", "format": "int32"},
+    "float32": {"type": "number", "format": "float"},
+    "float64": {"type": "number", "format": "double"},
+    "time.Time": {"type": "string", "format": "date-time"},
+    "time.Duration": {"type": "integer", "format": "int64"},
+    "json.RawMessage": {},
+    "interface{}": {},
+    "any": {},
+}
+
+# Used whenever a type cannot be resolved to something more specific.
+GENERIC_OBJECT: Schema = {"type": "object"}
+
+
+def nullable(schema: Schema) -> Schema:
+    """Allow `null` in addition to `schema` (JSON Schema 2020-12 style)."""
+    if not schema:
+        return schema
+    typ = schema.get("type")
+    if isinstance(typ, str):
+        return {**schema, "type": [typ, "null"]}
+    return {"anyOf": [schema, {"type": "null"}]}
+
+
+class SchemaBuilder:
+    """Maps Go types from a Report's DTOs to JSON schemas, `$ref`-ing structs.
+
+    In `strict` mode struct schemas list fields without `omitempty` as
+    `required` and pointers accept `null`, matching what encoding/json emits.
+    """
+
+    def __init__(self, report: Report, ref_prefix: str, strict: bool = False):
+        self.report = report
+        self.ref_prefix = ref_prefix
+        self.strict = strict
+        self.defs: Dict[str, Schema] = {}
+        self._names: Dict[Tuple[str, str, str], str] = {}
+
+    def for_type(self, type_text: str, package: str = "") -> Schema:
+        t = type_text.strip()
+        if t.startswith("*"):
+            inner = self.for_type(t[1:], package)
+            return nullable(inner) if self.strict else inner
+        if t == "[]byte":
+            return {"type": "string", "format": "byte"}
+        if t.startswith("[]"):
+            return {"type": "array", "items": self.for_type(t[2:], package)}
+        if t.startswith("[") and "]" in t:
+            return {"type": "array", "items": self.for_type(t[t.index("]") + 1 :], package)}
+        if t.startswith("map["):
+            value = self._map_value(t)
+            return {"type": "object", "additionalProperties": self.for_type(value, package)}
+        if t in PRIMITIVES:
+            return dict(PRIMITIVES[t])
+        st = self.report.find_struct(t, package)
+        if st is None:
+            return dict(GENERIC_OBJECT)
+        return {"$ref": self.ref_prefix + self.ref(st)}
+
+    def ref(self, st: Struct) -> str:
+        """Register `st` under its def name (once) and return that name."""
+        key = (st.package, st.file, st.name)
+        name = self._names.get(key)
+        if name is None:
+            name = st.name
+            if name in self.defs:
+                # Same type name in another package: qualify it.
+                name = f"{st.package}.{st.name}"
+            self._names[key] = name
+            self.defs[name] = {}  # placeholder breaks recursive references
+            self.defs[name] = self.struct_schema(st)
+        return name
+
+    def struct_schema(self, st: Struct) -> Schema:
+        props: Dict[str, Schema] = {}
+        required: List[str] = []
+        self._add_fields(st, props, required, seen=set())
+        schema: Schema = {"type": "object", "properties": props}
+        if self.strict and required:
+            schema["required"] = required
+        return schema
+
+    def _add_fields(self, st: Struct, props: Dict[str, Schema], required: List[str], seen):
+        seen.add(st.name)
+        for fld in st.fields:
+            if fld.json == "-":
+                continue
+            if fld.embedded:
+                # encoding/json promotes the fields of untagged embedded structs, even
+                # unexported ones; a tagged embedded struct is a field under the tag.
+                inner = self.report.find_struct(fld.type.lstrip("*"), st.package)
+                if inner and not fld.json and inner.name not in seen:
+                    self._add_fields(inner, props, required, seen)
+                    continue
+                if not fld.exported and not (inner and fld.json):
+                    continue
+            elif not fld.exported:
+                continue
+            name = fld.json or fld.name
+            props[name] = self.for_type(fld.type, st.package)
+            if not fld.omitempty and name not in required:
+                required.append(name)
+
+    @staticmethod
+    def _map_value(t: str) -> str:
+        depth = 0
+        for i, ch in enumerate(t):
+            if ch == "[":
+                depth += 1
+            elif ch == "]":
+                depth -= 1
+                if depth == 0:
+                    return t[i + 1 :]
+        return ""
+
+    def body_schema(self, type_text: Optional[str], package: str = "") -> Schema:
+        if not type_text:
+            return dict(GENERIC_OBJECT)
+        return self.for_type(type_text, package)
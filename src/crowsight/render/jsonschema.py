@@ -0,0 +1,29 @@
+# src/crowsight/render/jsonschema.py
+
+import json
+from pathlib import Path
+from typing import Any, Dict
+
+from ..report.models import Report
+from .goschema import SchemaBuilder
+
+DRAFT = "https://json-schema.org/draft/2020-12/schema"
+
+
+def build_jsonschema(report: Report) -> Dict[str, Any]:
+    """One schema per request/response struct under `$defs`, sharing nested types."""
+    schemas = SchemaBuilder(report, "#/$defs/", strict=True)
+    for svc in report.services:
+        for item in (*svc.endpoints, *svc.handlers):
+            for type_text in (item.request, item.response):
+                if type_text:
+                    schemas.for_type(type_text, svc.package)
+    return {
+        "$schema": DRAFT,
+        "title": f"{Path(report.root).resolve().name} DTOs",
+        "$defs": {k: schemas.defs[k] for k in sorted(schemas.defs)},
+    }
+
+
+def render_jsonschema(report: Report) -> str:
+    return json.dumps(build_jsonschema(report), indent=2) + "\n"
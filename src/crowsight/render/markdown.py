@@ -0,0 +1,148 @@
+# src/crowsight/render/markdown.py
+
+import re
+from typing import Dict, List, Optional, Set
+
+from ..report.models import Endpoint, Report, Service, Struct
+from ..scanner.types import element_type
+
+# Shown in place of a type that the scanner could not determine.
+UNKNOWN = "_unknown_"
+
+
+def cell(text: str) -> str:
+    """Text safe to put inside a Markdown table cell."""
+    return text.replace("\\", "\\\\").replace("|", "\\|").replace("\n", " ")
+
+
+def code(text: str) -> str:
+    return f"`{cell(text)}`" if text else ""
+
+
+def doc_filenames(services: List[Service]) -> List[str]:
+    """`<name>.md` per service, named after its directory when service names collide."""
+    counts: Dict[str, int] = {}
+    for svc in services:
+        counts[svc.name] = counts.get(svc.name, 0) + 1
+    names: List[str] = []
+    for svc in services:
+        name = svc.name
+        if counts[name] > 1:
+            name = re.sub(r"[^\w.-]+", "-", svc.dir.strip("/")) or name
+        names.append(f"{name}.md")
+    return names
+
+
+class ServiceDoc:
+    """Builds the Markdown stub for one service; DTO tables use json tag names."""
+
+    def __init__(self, report: Report, svc: Service):
+        self.report = report
+        self.svc = svc
+
+    def render(self) -> str:
+        svc = self.svc
+        out: List[str] = [f"# {svc.name}", ""]
+        out.append(f"Package `{svc.package}` in `{svc.dir or '.'}`.")
+        out.append("")
+        out.append(f"- Listen address: {code(svc.listen_addr) if svc.listen_addr else UNKNOWN}")
+        out.append(f"- Endpoints: {len(svc.endpoints)}")
+        out.append("")
+        out.extend(self._endpoints())
+        out.extend(self._env())
+        return "\n".join(out).rstrip("\n") + "\n"
+
+    def _endpoints(self) -> List[str]:
+        out: List[str] = ["## Endpoints", ""]
+        if not self.svc.endpoints:
+            return out + ["No endpoints detected.", ""]
+        out += ["| Method | Path | Handler |", "| --- | --- | --- |"]
+        for ep in self.svc.endpoints:
+            out.append(f"| {ep.method} | {code(ep.path)} | {code(ep.handler or '')} |")
+        out.append("")
+        for ep in self.svc.endpoints:
+            out.extend(self._endpoint(ep))
+        return out
+
+    def _endpoint(self, ep: Endpoint) -> List[str]:
+        out = [f"### {ep.method} {ep.path}", ""]
+        where = f"{ep.handler_file or ep.file}:{ep.handler_line or ep.line}"
+        out.append(f"Handler {code(ep.handler or 'inline function')} at `{where}`.")
+        if ep.protocol != "http":
+            out.append(f"Protocol: {ep.protocol}.")
+        if ep.middleware:
+            out.append(f"Middleware: {', '.join(code(m) for m in ep.middleware)}.")
+        if ep.status_codes:
+            out.append(f"Responds with: {', '.join(code(str(c)) for c in ep.status_codes)}.")
+        out.append("")
+        if ep.path_params:
+            out += ["Path parameters:", "", "| Name | Type |", "| --- | --- |"]
+            for p in ep.path_params:
+                out.append(f"| {code(p.name)} | {code(p.type)} |")
+            out.append("")
+        for title, type_text in (("Request", ep.request), ("Response", ep.response)):
+            out.extend(self._body(title, type_text))
+        return out
+
+    def _body(self, title: str, type_text: Optional[str]) -> List[str]:
+        if not type_text:
+            return [f"{title}: {UNKNOWN}", ""]
+        out = [f"{title}: {code(type_text)}", ""]
+        pending = [element_type(type_text)]
+        seen: Set[str] = set()
+        while pending:
+            st = self.report.find_struct(pending.pop(0), self.svc.package)
+            if st is None or self._key(st) in seen:
+                continue
+            seen.add(self._key(st))
+            table, nested = self._struct(st)
+            out += [f"`{st.package}.{st.name}`:", "", *table, ""]
+            pending.extend(nested)
+        return out
+
+    def _struct(self, st: Struct):
+        """The field table of `st` and the struct types its fields refer to."""
+        rows: List[str] = ["| Field | Type | Required |", "| --- | --- | --- |"]
+        nested: List[str] = []
+        self._rows(st, rows, nested, {self._key(st)})
+        if len(rows) == 2:
+            rows = ["No JSON fields."]
+        return rows, nested
+
+    def _rows(self, st: Struct, rows: List[str], nested: List[str], seen: Set[str]):
+        for fld in st.fields:
+            if fld.json == "-" or not fld.exported:
+                continue
+            if fld.embedded and not fld.json:
+                # encoding/json promotes the fields of untagged embedded structs.
+                inner = self.report.find_struct(fld.type.lstrip("*"), st.package)
+                if inner is not None and self._key(inner) not in seen:
+                    self._rows(inner, rows, nested, seen | {self._key(inner)})
+                    continue
+            required = "no" if fld.omitempty else "yes"
+            rows.append(f"| {code(fld.json or fld.name)} | {code(fld.type)} | {required} |")
+            inner = self.report.find_struct(element_type(fld.type), st.package)
+            if inner is not None:
+                nested.append(f"{inner.package}.{inner.name}")
+
+    @staticmethod
+    def _key(st: Struct) -> str:
+        return f"{st.package}.{st.name}:{st.file}"
+
+    def _env(self) -> List[str]:
+        out: List[str] = ["## Environment", ""]
+        if not self.svc.env_vars:
+            return out + ["No environment variables detected.", ""]
+        out += ["| Variable | Read at |", "| --- | --- |"]
+        for var in self.svc.env_vars:
+            name = code(var.name) if var.resolved else f"{code(var.name)} (unresolved)"
+            where = ", ".join(f"`{u.file}:{u.line}`" for u in var.usages)
+            out.append(f"| {name} | {where} |")
+        out.append("")
+        return out
+
+
+def render_service_docs(report: Report) -> Dict[str, str]:
+    """One Markdown document per service, keyed by file name."""
+    names = doc_filenames(report.services)
+    return {name: ServiceDoc(report, svc).render() for name, svc in zip(names, report.services)}
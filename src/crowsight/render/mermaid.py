@@ -0,0 +1,56 @@
+# src/crowsight/render/mermaid.py
+
+import re
+from typing import Dict, List, Tuple
+
+from ..report.models import Report
+
+# Characters the Mermaid parser treats as syntax even inside quoted labels.
+_ENTITIES = {
+    '"': "#quot;",
+    "{": "#123;",
+    "}": "#125;",
+    ":": "#58;",
+    "[": "#91;",
+    "]": "#93;",
+    "(": "#40;",
+    ")": "#41;",
+    "<": "#lt;",
+    ">": "#gt;",
+    "|": "#124;",
+}
+
+
+def escape_label(text: str) -> str:
+    return "".join(_ENTITIES.get(ch, ch) for ch in text)
+
+
+def node_id(*parts: str) -> str:
+    return "_".join(re.sub(r"\W", "_", p) for p in parts)
+
+
+def render_mermaid(report: Report) -> str:
+    """Render services as subgraphs and their endpoints as nodes (`graph LR`)."""
+    lines: List[str] = ["graph LR"]
+    # By directory: two services can share a package name.
+    ids = {svc.dir: node_id("svc", str(i)) for i, svc in enumerate(report.services)}
+    for svc in report.services:
+        sid = ids[svc.dir]
+        lines.append(f'    subgraph {sid}["{escape_label(svc.name)}"]')
+        for ei, ep in enumerate(svc.endpoints):
+            label = escape_label(f"{ep.method} {ep.path}")
+            lines.append(f'        {node_id(sid, "ep", str(ei))}["{label}"]')
+        lines.append("    end")
+
+    # One edge per calling/called service pair, labelled with the target paths.
+    edges: Dict[Tuple[str, str], List[str]] = {}
+    for svc in report.services:
+        for call in svc.outbound_calls:
+            if call.service_dir in ids and call.endpoint:
+                paths = edges.setdefault((svc.dir, call.service_dir), [])
+                if call.endpoint not in paths:
+                    paths.append(call.endpoint)
+    for (src, dst), paths in sorted(edges.items()):
+        label = escape_label(", ".join(paths))
+        lines.append(f'    {ids[src]} -->|"{label}"| {ids[dst]}')
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,108 @@
+# src/crowsight/render/openapi.py
+
+from http import HTTPStatus
+import json
+import re
+from pathlib import Path
+from typing import Any, Dict, List, Optional
+
+from ..report.models import Endpoint, Report
+from .goschema import SchemaBuilder
+
+_COLON_PARAM = re.compile(r":(\w+)")
+_BRACE_PARAM = re.compile(r"\{(\w+)(?:[:.][^}]*)?\}")
+
+
+def openapi_path(path: str) -> str:
+    """Rewrite router syntax (`:id`, `{id:[0-9]+}`, `*`) into OpenAPI templates."""
+    # ServeMux's `{$}` only anchors an exact match.
+    path = path.replace("{$}", "")
+    path = _BRACE_PARAM.sub(r"{\1}", path)
+    path = _COLON_PARAM.sub(r"{\1}", path)
+    path = re.sub(r"\*(\w*)$", lambda m: "{" + (m.group(1) or "path") + "}", path)
+    return path
+
+
+def path_parameters(ep: Endpoint) -> List[Dict[str, Any]]:
+    """OpenAPI `in: path` parameters, named as `openapi_path` names them."""
+    out: List[Dict[str, Any]] = []
+    for p in ep.path_params:
+        schema: Dict[str, Any] = {"type": p.type}
+        if p.pattern and p.type == "string":
+            schema["pattern"] = f"^{p.pattern}$"
+        name = "path" if p.name == "*" else p.name
+        out.append({"name": name, "in": "path", "required": True, "schema": schema})
+    return out
+
+
+def status_text(code: int) -> str:
+    try:
+        return HTTPStatus(code).phrase
+    except ValueError:
+        return f"Status {code}"
+
+
+def responses(codes: List[int], content: Optional[Dict[str, Any]]) -> Dict[str, Any]:
+    """One response per status the handler uses; the body schema goes on the first 2xx."""
+    codes = codes or [200]
+    success = next((c for c in codes if 200 <= c < 300), None)
+    if success is None and content is not None:
+        codes, success = sorted([200, *codes]), 200
+    out: Dict[str, Any] = {}
+    for code in codes:
+        resp: Dict[str, Any] = {"description": status_text(code)}
+        if code == success and content is not None:
+            resp["content"] = content
+        out[str(code)] = resp
+    return out
+
+
+def build_openapi(report: Report) -> Dict[str, Any]:
+    schemas = SchemaBuilder(report, "#/components/schemas/")
+    paths: Dict[str, Dict[str, Any]] = {}
+
+    for svc in report.services:
+        for ep in svc.endpoints:
+            if ep.protocol != "http":
+                continue
+            # OpenAPI has no "any method" operation; pick the likeliest verb.
+            method = ep.method.lower()
+            if method == "any":
+                method = "post" if ep.request else "get"
+            op: Dict[str, Any] = {"tags": [svc.name]}
+            if ep.handler:
+                op["operationId"] = ep.handler
+            params = path_parameters(ep)
+            if params:
+                op["parameters"] = params
+            if ep.request:
+                op["requestBody"] = {
+                    "required": True,
+                    "content": {
+                        "application/json": {
+                            "schema": schemas.body_schema(ep.request, svc.package)
+                        }
+                    },
+                }
+            content = None
+            if ep.response:
+                content = {
+                    "application/json": {
+                        "schema": schemas.body_schema(ep.response, svc.package)
+                    }
+                }
+            op["responses"] = responses(ep.status_codes, content)
+            paths.setdefault(openapi_path(ep.path), {})[method] = op
+
+    doc: Dict[str, Any] = {
+        "openapi": "3.0.3",
+        "info": {"title": Path(report.root).resolve().name, "version": "0.0.0"},
+        "paths": {p: paths[p] for p in sorted(paths)},
+    }
+    if schemas.defs:
+        doc["components"] = {"schemas": {k: schemas.defs[k] for k in sorted(schemas.defs)}}
+    return doc
+
+
+def render_openapi(report: Report) -> str:
+    return json.dumps(build_openapi(report), indent=2) + "\n"
@@ -0,0 +1,136 @@
+# src/crowsight/render/sarif.py
+
+from importlib import metadata
+import json
+from pathlib import Path
+from typing import Any, Dict, List
+
+from ..analyzers import (
+    auth,
+    bodies,
+    complexity,
+    contenttype,
+    contexts,
+    deprecated,
+    duplicates,
+    goroutines,
+    imports,
+    jsontags,
+    observability,
+    panics,
+    params,
+    ports,
+    queries,
+    response,
+    secrets,
+    slashes,
+    tls,
+    unchecked,
+    webhooks,
+)
+from ..report.models import Finding, Report, Severity
+
+SARIF_SCHEMA = "https://json.schemastore.org/sarif-2.1.0.json"
+SARIF_VERSION = "2.1.0"
+TOOL_URI = "https://github.com/tavallaie/crowsight"
+SRCROOT = "SRCROOT"
+
+RULE_DESCRIPTIONS: Dict[str, str] = {
+    **unchecked.RULES,
+    **complexity.RULES,
+    **duplicates.RULES,
+    **params.RULES,
+    **response.RULES,
+    **panics.RULES,
+    **queries.RULES,
+    **auth.RULES,
+    **tls.RULES,
+    **contexts.RULES,
+    **bodies.RULES,
+    **goroutines.RULES,
+    **secrets.RULES,
+    **deprecated.RULES,
+    **contenttype.RULES,
+    **imports.RULES,
+    **slashes.RULES,
+    **observability.RULES,
+    **webhooks.RULES,
+    **jsontags.RULES,
+    **ports.RULES,
+}
+
+LEVELS = {Severity.INFO: "note", Severity.WARNING: "warning", Severity.ERROR: "error"}
+
+
+def _version() -> str:
+    try:
+        return metadata.version("crowsight")
+    except metadata.PackageNotFoundError:
+        return "0.0.0"
+
+
+def _rule_name(code: str) -> str:
+    """`unchecked-error` → `UncheckedError` (SARIF names are PascalCase by convention)."""
+    return "".join(part.capitalize() for part in code.replace("_", "-").split("-"))
+
+
+def _result(fd: Finding, index: int) -> Dict[str, Any]:
+    location: Dict[str, Any] = {
+        "artifactLocation": {"uri": Path(fd.file).as_posix(), "uriBaseId": SRCROOT}
+    }
+    if fd.line > 0:
+        region: Dict[str, Any] = {"startLine": fd.line}
+        if fd.column > 0:
+            region["startColumn"] = fd.column
+        if fd.expression:
+            region["snippet"] = {"text": fd.expression}
+        location["region"] = region
+    return {
+        "ruleId": fd.code,
+        "ruleIndex": index,
+        "level": LEVELS[fd.severity],
+        "message": {"text": fd.message},
+        "locations": [{"physicalLocation": location}],
+    }
+
+
+def build_sarif(report: Report) -> Dict[str, Any]:
+    """SARIF 2.1.0 log with one rule per finding code; paths are relative to SRCROOT."""
+    codes = sorted({fd.code for fd in report.findings})
+    index = {code: i for i, code in enumerate(codes)}
+    rules: List[Dict[str, Any]] = []
+    for code in codes:
+        # The default level is the most common severity the report used for the code.
+        levels = [fd.severity for fd in report.findings if fd.code == code]
+        default = max(sorted(set(levels), key=lambda s: s.rank), key=levels.count)
+        rules.append(
+            {
+                "id": code,
+                "name": _rule_name(code),
+                "shortDescription": {"text": RULE_DESCRIPTIONS.get(code, code)},
+                "defaultConfiguration": {"level": LEVELS[default]},
+            }
+        )
+    root_uri = Path(report.root).resolve().as_uri().rstrip("/") + "/"
+    return {
+        "$schema": SARIF_SCHEMA,
+        "version": SARIF_VERSION,
+        "runs": [
+            {
+                "tool": {
+                    "driver": {
+                        "name": "crowsight",
+                        "informationUri": TOOL_URI,
+                        "version": _version(),
+                        "rules": rules,
+                    }
+                },
+                "originalUriBaseIds": {SRCROOT: {"uri": root_uri}},
+                "results": [_result(fd, index[fd.code]) for fd in report.findings],
+            }
+        ],
+    }
+
+
+def render_sarif(report: Report) -> str:
+    return json.dumps(build_sarif(report), indent=2) + "\n"
@@ -0,0 +1,803 @@
+# src/crowsight/render/template.py
+
+"""`--format template`: render a report with a Go text/template.
+
+The engine implements the text/template language, so crowsight needs no
+template dependency: `{{.Field.Chain}}`, `{{$var}}`, pipelines
+(`{{.Path | lower}}`), parenthesized arguments, `{{if}}`/`{{else if}}`,
+`{{range}}` (with `$i, $v :=`, `{{break}}` and `{{continue}}`), `{{with}}`,
+`$x := ...` / `$x = ...`, `{{/* comments */}}` and the `{{-`/`-}}` trim
+markers. `{{define}}`, `{{template}}` and `{{block}}` are not supported.
+
+The template runs against the Report. Fields use their Go-style names
+(`.HandlerFile`) or the names in the JSON report (`.handler_file`):
+
+    Report    .Root .SchemaVersion .Services .Endpoints (of every service)
+              .Findings .Todos .Errors .Summary .Modules .Metrics
+    Service   .Name .Package .Dir .Module .ListenAddr .Files .Endpoints
+              .Handlers .Structs .OutboundCalls .EnvVars
+    Endpoint  .Method .Path .Handler .File .Line .HandlerFile .HandlerLine
+              .Request .Response .Protocol .Middleware .PathParams .Todos
+              .Complexity .DBAccess .DBQueries
+    Finding   .Code .Severity .Message .File .Line .Column .Expression .Analyzer
+    Todo      .File .Line .Marker .Text
+    Summary   .Services .Endpoints .Findings .BySeverity .ByAnalyzer
+
+Only report data is reachable: the fields of the report's types, and these
+lookups, which take their arguments like functions do
+(`{{with .FindStruct "User"}}`):
+
+    Report    .FindStruct TYPE [PACKAGE]  .EndpointsByMethod METHOD
+              .ServiceByName NAME
+    Service   .Struct NAME
+
+Mappings are indexed by key (`.Summary.BySeverity.error`), and nil values
+print as nothing. Besides Go's builtins (and, or, not, len, index, eq, ne,
+lt, le, gt, ge, print, printf, println) these functions are available:
+
+    join SEP LIST        lower S, upper S, title S, trim S
+    replace OLD NEW S    contains SUB S, hasPrefix P S, hasSuffix P S
+    default D V          V, or D when V is empty
+    toJson V             V as compact JSON
+    groupBy FIELD LIST   [{.Key .Items}] grouped on an item field, in order
+    byService LIST       [{.Key .Service .Items}] findings/todos/... by service
+    findingsFor SERVICE  the findings in a service's package directory
+
+The list and the other argument of join and groupBy may come in either
+order, so `{{.Middleware | join ", "}}` and `{{join .Middleware ", "}}` both
+work.
+"""
+
+from dataclasses import asdict, dataclass, field, fields, is_dataclass
+from enum import Enum
+from functools import partial
+import json
+import posixpath
+import re
+from typing import Any, Callable, Dict, List, Optional, Set, Tuple
+
+from ..report.models import Finding, Report, Service
+from ..scanner.goast import unquote
+
+LEFT, RIGHT = "{{", "}}"
+SPACE = " \t\r\n"
+
+_TOKEN = re.compile(
+    r"""
+    (?P<string>"(?:[^"\\\n]|\\.)*"|`[^`]*`)
+    |(?P<char>'(?:[^'\\\n]|\\.)+')
+    |(?P<declare>:=)
+    |(?P<assign>=)
+    |(?P<pipe>\|)
+    |(?P<lparen>\()
+    |(?P<rparen>\))
+    |(?P<comma>,)
+    |(?P<number>[-+]?(?:0[xX][0-9a-fA-F_]+|\d[\d_]*(?:\.\d*)?(?:[eE][-+]?\d+)?))
+    |(?P<var>\$\w*(?:\.\w+)*)
+    |(?P<field>(?:\.\w+)+)
+    |(?P<dot>\.)
+    |(?P<ident>[A-Za-z_]\w*)
+    """,
+    re.VERBOSE,
+)
+_VERB = re.compile(r"%([-+# 0]*)(\d+)?(?:\.(\d+))?([a-zA-Z%])")
+_MISSING = object()
+# What a template may use besides dataclass fields: the documented lookups.
+HELPERS: Dict[type, Tuple[str, ...]] = {
+    Report: ("endpoints", "find_struct", "endpoints_by_method", "service_by_name"),
+    Service: ("struct",),
+}
+
+
+class TemplateError(ValueError):
+    """A template that does not parse or fails to run; the message says where."""
+
+
+@dataclass
+class Group:
+    """An element of groupBy / byService results."""
+
+    key: str
+    items: List[Any] = field(default_factory=list)
+    service: Optional[Service] = None
+
+
+@dataclass
+class _Token:
+    kind: str
+    text: str
+    pos: int
+    end: int
+
+
+@dataclass
+class _Operand:
+    # field | var | dot | func | literal | pipe
+    kind: str
+    pos: int
+    text: str
+    name: str = ""
+    chain: List[str] = field(default_factory=list)
+    value: Any = None
+
+
+@dataclass
+class _Command:
+    args: List[_Operand]
+
+
+@dataclass
+class _Pipeline:
+    pos: int
+    commands: List[_Command]
+    decl: List[str] = field(default_factory=list)
+    # `$x = ...` assigns an existing variable; `$x := ...` declares one.
+    assign: bool = False
+
+
+@dataclass
+class _Text:
+    text: str
+
+
+@dataclass
+class _Action:
+    pipe: _Pipeline
+
+
+@dataclass
+class _Control:
+    # if | range | with
+    keyword: str
+    pipe: _Pipeline
+    body: List[Any]
+    orelse: List[Any]
+
+
+@dataclass
+class _Loop:
+    # break | continue
+    keyword: str
+    pos: int
+
+
+class _Break(Exception):
+    pass
+
+
+class _Continue(Exception):
+    pass
+
+
+def _snake(name: str) -> str:
+    """`HandlerFile` -> `handler_file`, `DBAccess` -> `db_access`."""
+    name = re.sub(r"([A-Z]+)([A-Z][a-z])", r"\1_\2", name)
+    return re.sub(r"([a-z0-9])([A-Z])", r"\1_\2", name).lower()
+
+
+def _exposed(obj: Any) -> Set[str]:
+    """The attributes of `obj` a template can read: its fields and documented helpers."""
+    if not is_dataclass(obj) or isinstance(obj, type):
+        return set()
+    names = {f.name for f in fields(obj) if not f.name.startswith("_")}
+    for cls, helpers in HELPERS.items():
+        if isinstance(obj, cls):
+            names.update(helpers)
+    return names
+
+
+def _lookup(obj: Any, name: str) -> Any:
+    """`obj.name` the way a template sees it; AttributeError if there is no such field."""
+    if isinstance(obj, dict):
+        for key in (name, _snake(name)):
+            if key in obj:
+                return obj[key]
+        return None
+    exposed = _exposed(obj)
+    for attr in (name, _snake(name)):
+        if attr in exposed:
+            return getattr(obj, attr)
+    raise AttributeError(f"can't evaluate field {name} in type {type(obj).__name__}")
+
+
+def _text(value: Any) -> str:
+    """How `{{value}}` prints, following Go's fmt where it matters."""
+    if value is None:
+        return ""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, Enum):
+        return str(value.value)
+    if isinstance(value, str):
+        return value
+    if isinstance(value, (list, tuple)):
+        return "[" + " ".join(_text(v) for v in value) + "]"
+    if isinstance(value, dict):
+        pairs = sorted(value.items(), key=lambda kv: str(kv[0]))
+        return "map[" + " ".join(f"{_text(k)}:{_text(v)}" for k, v in pairs) + "]"
+    if is_dataclass(value) and not isinstance(value, type):
+        return "{" + " ".join(_text(getattr(value, f.name)) for f in fields(value)) + "}"
+    return str(value)
+
+
+def _truth(value: Any) -> bool:
+    if value is None:
+        return False
+    if isinstance(value, (bool, int, float, str, list, tuple, dict)):
+        return bool(value)
+    return True
+
+
+def _items(value: Any) -> List[Tuple[Any, Any]]:
+    """What `{{range}}` iterates: (index or key, element) pairs."""
+    if value is None:
+        return []
+    if isinstance(value, dict):
+        return sorted(value.items(), key=lambda kv: str(kv[0]))
+    if isinstance(value, bool):
+        raise TypeError("range can't iterate over a bool")
+    if isinstance(value, int):
+        return [(i, i) for i in range(value)]
+    if isinstance(value, str):
+        raise TypeError("range can't iterate over a string")
+    return list(enumerate(value))
+
+
+def _sprint(*args: Any) -> str:
+    # Like fmt.Sprint: a space between operands when neither is a string.
+    out = ""
+    for i, arg in enumerate(args):
+        if i and not isinstance(arg, str) and not isinstance(args[i - 1], str):
+            out += " "
+        out += _text(arg)
+    return out
+
+
+def _sprintf(format: str, *args: Any) -> str:
+    """fmt.Sprintf for the common verbs: %v %s %q %d %f %e %g %x %o %t %c %%."""
+    out: List[str] = []
+    pos, used = 0, 0
+    for m in _VERB.finditer(format):
+        out.append(format[pos : m.start()])
+        pos = m.end()
+        flags, width, precision, verb = m.groups()
+        if verb == "%":
+            out.append("%")
+            continue
+        if used >= len(args):
+            out.append(f"%!{verb}(MISSING)")
+            continue
+        arg = args[used]
+        used += 1
+        spec = f"%{flags}{width or ''}{'.' + precision if precision else ''}"
+        if verb in "vs":
+            out.append((spec + "s") % _text(arg))
+        elif verb == "q":
+            out.append((spec + "s") % json.dumps(_text(arg)))
+        elif verb == "t":
+            out.append((spec + "s") % _text(bool(arg)))
+        elif verb == "c":
+            out.append((spec + "s") % chr(arg))
+        elif verb in "dxXo":
+            out.append((spec + verb) % int(arg))
+        elif verb in "feEgG":
+            out.append((spec + verb) % float(arg))
+        else:
+            out.append(f"%!{verb}({_text(arg)})")
+    out.append(format[pos:])
+    if used < len(args):
+        out.append("%!(EXTRA " + ", ".join(_text(a) for a in args[used:]) + ")")
+    return "".join(out)
+
+
+def _and(*args: Any) -> Any:
+    for arg in args:
+        if not _truth(arg):
+            return arg
+    return args[-1]
+
+
+def _or(*args: Any) -> Any:
+    for arg in args:
+        if _truth(arg):
+            return arg
+    return args[-1]
+
+
+def _index(value: Any, *keys: Any) -> Any:
+    for key in keys:
+        if isinstance(value, dict):
+            value = value.get(key)
+        else:
+            value = value[key]
+    return value
+
+
+def _compare(op: Callable[[Any, Any], bool]) -> Callable[[Any, Any], bool]:
+    def compare(a: Any, b: Any) -> bool:
+        try:
+            return op(a, b)
+        except TypeError:
+            raise TypeError("incompatible types for comparison")
+
+    return compare
+
+
+def _ordered(a: Any, b: Any) -> Tuple[Any, Any]:
+    """(list, other) for helpers that take their list argument in either position."""
+    if isinstance(a, (list, tuple)) and not isinstance(b, (list, tuple)):
+        return a, b
+    return b, a
+
+
+def _join(a: Any, b: Any) -> str:
+    items, sep = _ordered(a, b)
+    return str(sep).join(_text(v) for v in items or [])
+
+
+def _group_by(a: Any, b: Any) -> List[Group]:
+    items, key = _ordered(a, b)
+    groups: Dict[str, Group] = {}
+    for item in items or []:
+        value = item
+        for name in str(key).split("."):
+            value = _lookup(value, name)
+        text = _text(value)
+        groups.setdefault(text, Group(key=text)).items.append(item)
+    return list(groups.values())
+
+
+def _to_json(value: Any) -> str:
+    def plain(obj: Any) -> Any:
+        if is_dataclass(obj) and not isinstance(obj, type):
+            return asdict(obj)
+        raise TypeError(f"{type(obj).__name__} is not JSON serializable")
+
+    return json.dumps(value, default=plain)
+
+
+def _by_service(report: Report, items: Any) -> List[Group]:
+    """Group anything with a `.file` (findings, todos, ...) under its package's service."""
+    owners = {}
+    for svc in report.services:
+        owners.setdefault(svc.dir, svc)
+    groups: Dict[str, Group] = {}
+    for item in items or []:
+        svc = owners.get(posixpath.dirname(_lookup(item, "file") or ""))
+        key = svc.name if svc is not None else ""
+        groups.setdefault(key, Group(key=key, service=svc)).items.append(item)
+    # Service order, then whatever belongs to no service.
+    ranks = {svc.name: i for i, svc in reversed(list(enumerate(report.services)))}
+    return sorted(groups.values(), key=lambda g: ranks.get(g.key, len(ranks)))
+
+
+def _findings_for(report: Report, svc: Service) -> List[Finding]:
+    return [fd for fd in report.findings if posixpath.dirname(fd.file) == svc.dir]
+
+
+FUNCS: Dict[str, Callable[..., Any]] = {
+    "and": _and,
+    "or": _or,
+    "not": lambda v: not _truth(v),
+    "len": len,
+    "index": _index,
+    "eq": lambda a, *bs: any(a == b for b in bs),
+    "ne": lambda a, b: a != b,
+    "lt": _compare(lambda a, b: a < b),
+    "le": _compare(lambda a, b: a <= b),
+    "gt": _compare(lambda a, b: a > b),
+    "ge": _compare(lambda a, b: a >= b),
+    "print": _sprint,
+    "printf": _sprintf,
+    "println": lambda *args: " ".join(_text(a) for a in args) + "\n",
+    "join": _join,
+    "lower": lambda s: _text(s).lower(),
+    "upper": lambda s: _text(s).upper(),
+    "title": lambda s: _text(s).title(),
+    "trim": lambda s: _text(s).strip(),
+    "replace": lambda old, new, s: _text(s).replace(old, new),
+    "contains": lambda sub, s: sub in _text(s),
+    "hasPrefix": lambda prefix, s: _text(s).startswith(prefix),
+    "hasSuffix": lambda suffix, s: _text(s).endswith(suffix),
+    "default": lambda d, v=None: v if _truth(v) else d,
+    "toJson": _to_json,
+    "groupBy": _group_by,
+}
+# Helpers that also get the Report the template runs against.
+REPORT_FUNCS: Dict[str, Callable[..., Any]] = {
+    "byService": _by_service,
+    "findingsFor": _findings_for,
+}
+
+
+class Template:
+    """A parsed template; `execute` runs it against a report (or any data).
+
+    `funcs` adds or replaces template functions, like Go's Template.Funcs.
+    Parse errors raise TemplateError as `template: NAME:LINE:COL: message`.
+    """
+
+    def __init__(
+        self,
+        source: str,
+        name: str = "template",
+        funcs: Optional[Dict[str, Callable[..., Any]]] = None,
+    ):
+        self.source = source
+        self.name = name
+        self.funcs = {**FUNCS, **(funcs or {})}
+        self._items = self._lex()
+        self._next = 0
+        self.nodes, _ = self._list(())
+
+    def execute(self, data: Any) -> str:
+        funcs = dict(self.funcs)
+        if isinstance(data, Report):
+            funcs.update({n: partial(fn, data) for n, fn in REPORT_FUNCS.items()})
+        return _Exec(self, data, funcs).run(self.nodes)
+
+    def error(self, pos: int, message: str) -> TemplateError:
+        line = self.source.count("\n", 0, pos) + 1
+        column = pos - (self.source.rfind("\n", 0, pos) + 1) + 1
+        return TemplateError(f"template: {self.name}:{line}:{column}: {message}")
+
+    # Lexing: the source becomes text items and token lists, one per action.
+
+    def _lex(self) -> List[Tuple[str, Any, int]]:
+        src = self.source
+        items: List[Tuple[str, Any, int]] = []
+        pos, trim = 0, False
+        while True:
+            start = src.find(LEFT, pos)
+            text = src[pos:] if start < 0 else src[pos:start]
+            if trim:
+                text = text.lstrip(SPACE)
+            if start < 0:
+                if text:
+                    items.append(("text", text, pos))
+                return items
+            inner = start + len(LEFT)
+            if src.startswith("-", inner) and src[inner + 1 : inner + 2] in tuple(SPACE):
+                text = text.rstrip(SPACE)
+                inner += 2
+            if text:
+                items.append(("text", text, pos))
+            tokens, pos, trim = self._lex_action(start, inner)
+            if tokens is not None:
+                items.append(("action", tokens, start))
+
+    def _lex_action(self, start: int, pos: int) -> Tuple[Optional[List[_Token]], int, bool]:
+        src = self.source
+        tokens: List[_Token] = []
+        spaced = pos > start + len(LEFT)
+        while True:
+            skipped = pos
+            while pos < len(src) and src[pos] in SPACE:
+                pos += 1
+            spaced = spaced or pos > skipped
+            if src.startswith("/*", pos) and not tokens:
+                close = src.find("*/", pos + 2)
+                if close < 0:
+                    raise self.error(pos, "unclosed comment")
+                end = self._close(close + 2, start)
+                if end is None:
+                    raise self.error(close, "comment ends before closing delimiter")
+                return None, end[0], end[1]
+            if src.startswith(RIGHT, pos):
+                return tokens, pos + len(RIGHT), False
+            if src.startswith("-" + RIGHT, pos) and spaced:
+                return tokens, pos + 1 + len(RIGHT), True
+            if pos >= len(src):
+                raise self.error(start, "unclosed action")
+            m = _TOKEN.match(src, pos)
+            if m is None:
+                raise self.error(pos, f"unexpected {src[pos]!r} in action")
+            tokens.append(_Token(m.lastgroup, m.group(), pos, m.end()))
+            pos, spaced = m.end(), False
+
+    def _close(self, pos: int, start: int) -> Optional[Tuple[int, bool]]:
+        """(end, trim) if the action closes right at `pos`, as comments must."""
+        src = self.source
+        if src.startswith(RIGHT, pos):
+            return pos + len(RIGHT), False
+        if src[pos : pos + 1] in tuple(SPACE) and src.startswith("-" + RIGHT, pos + 1):
+            return pos + 1 + 1 + len(RIGHT), True
+        return None
+
+    # Parsing: items become a tree of text, actions and control structures.
+
+    def _list(self, stops: Tuple[str, ...], in_range: bool = False):
+        nodes: List[Any] = []
+        while self._next < len(self._items):
+            kind, value, pos = self._items[self._next]
+            self._next += 1
+            if kind == "text":
+                nodes.append(_Text(value))
+                continue
+            if not value:
+                raise self.error(pos, "missing value for command")
+            head = value[0]
+            keyword = head.text if head.kind == "ident" else None
+            if keyword in ("else", "end"):
+                if keyword not in stops:
+                    raise self.error(pos, f"unexpected {{{{{keyword}}}}}")
+                return nodes, (keyword, value[1:], pos)
+            if keyword in ("if", "range", "with"):
+                nodes.append(self._control(keyword, value[1:], pos, in_range))
+            elif keyword in ("break", "continue"):
+                if not in_range:
+                    raise self.error(pos, f"{{{{{keyword}}}}} outside {{{{range}}}}")
+                if len(value) > 1:
+                    raise self.error(value[1].pos, f"unexpected {value[1].text!r} in {keyword}")
+                nodes.append(_Loop(keyword, pos))
+            elif keyword in ("define", "template", "block"):
+                raise self.error(pos, f"{{{{{keyword}}}}} is not supported")
+            else:
+                nodes.append(_Action(self._pipeline(value, pos, decl=True)))
+        return nodes, None
+
+    def _control(self, keyword: str, tokens: List[_Token], pos: int, in_range: bool) -> _Control:
+        if not tokens:
+            raise self.error(pos, f"missing value for {keyword}")
+        pipe = self._pipeline(tokens, pos, decl=True, pair=keyword == "range")
+        body, end = self._list(("else", "end"), in_range or keyword == "range")
+        if end is None:
+            raise self.error(pos, f"unexpected EOF: {{{{{keyword}}}}} has no {{{{end}}}}")
+        orelse: List[Any] = []
+        word, rest, end_pos = end
+        if word == "else":
+            if rest and rest[0].kind == "ident" and rest[0].text in ("if", "with"):
+                if rest[0].text != keyword or keyword == "range":
+                    raise self.error(end_pos, f"unexpected {{{{else {rest[0].text}}}}}")
+                # `{{else if x}}` is `{{else}}{{if x}}...{{end}}{{end}}` with one shared end.
+                orelse = [self._control(keyword, rest[1:], end_pos, in_range)]
+                return _Control(keyword, pipe, body, orelse)
+            if rest:
+                raise self.error(rest[0].pos, f"unexpected {rest[0].text!r} in else")
+            orelse, end = self._list(("end",), in_range)
+            if end is None:
+                raise self.error(pos, f"unexpected EOF: {{{{{keyword}}}}} has no {{{{end}}}}")
+            rest = end[1]
+        if rest:
+            raise self.error(rest[0].pos, f"unexpected {rest[0].text!r} in end")
+        return _Control(keyword, pipe, body, orelse)
+
+    def _pipeline(
+        self, tokens: List[_Token], pos: int, decl: bool = False, pair: bool = False
+    ) -> _Pipeline:
+        names: List[str] = []
+        assign = False
+        i = 0
+        if decl and tokens and tokens[0].kind == "var":
+            k = 1
+            if pair and len(tokens) > 2 and tokens[1].kind == "comma" and tokens[2].kind == "var":
+                k = 3
+            if len(tokens) > k and tokens[k].kind in ("declare", "assign"):
+                named = [tokens[0]] + ([tokens[2]] if k == 3 else [])
+                for t in named:
+                    if "." in t.text or t.text == "$":
+                        raise self.error(t.pos, f"can't declare {t.text}")
+                names = [t.text for t in named]
+                assign = tokens[k].kind == "assign"
+                i = k + 1
+        commands, i = self._commands(tokens, i, pos)
+        if i < len(tokens):
+            raise self.error(tokens[i].pos, f"unexpected {tokens[i].text!r}")
+        return _Pipeline(pos, commands, names, assign)
+
+    def _commands(self, tokens: List[_Token], i: int, pos: int, nested: bool = False):
+        commands: List[_Command] = []
+        args: List[_Operand] = []
+        while i < len(tokens):
+            t = tokens[i]
+            if t.kind == "rparen":
+                if nested:
+                    break
+                raise self.error(t.pos, "unexpected right paren")
+            if t.kind == "pipe":
+                if not args:
+                    raise self.error(t.pos, "missing value for command")
+                commands.append(_Command(args))
+                args, i = [], i + 1
+                continue
+            op, i = self._operand(tokens, i)
+            args.append(op)
+        if not args:
+            raise self.error(tokens[i - 1].pos if tokens else pos, "missing value for command")
+        commands.append(_Command(args))
+        return commands, i
+
+    def _operand(self, tokens: List[_Token], i: int) -> Tuple[_Operand, int]:
+        t = tokens[i]
+        i += 1
+        if t.kind == "lparen":
+            commands, i = self._commands(tokens, i, t.pos, nested=True)
+            if i >= len(tokens):
+                raise self.error(t.pos, "unclosed left paren")
+            close = tokens[i]
+            i += 1
+            op = _Operand("pipe", t.pos, self.source[t.pos : close.end])
+            op.value = _Pipeline(t.pos, commands)
+            if i < len(tokens) and tokens[i].kind == "field" and tokens[i].pos == close.end:
+                op.chain = tokens[i].text.split(".")[1:]
+                op.text += tokens[i].text
+                i += 1
+            return op, i
+        if t.kind == "field":
+            return _Operand("field", t.pos, t.text, chain=t.text.split(".")[1:]), i
+        if t.kind == "dot":
+            return _Operand("dot", t.pos, t.text), i
+        if t.kind == "var":
+            name, *chain = t.text.split(".")
+            return _Operand("var", t.pos, t.text, name=name, chain=chain), i
+        if t.kind == "ident":
+            if t.text in ("true", "false", "nil"):
+                value = {"true": True, "false": False, "nil": None}[t.text]
+                return _Operand("literal", t.pos, t.text, value=value), i
+            if t.text not in self.funcs and t.text not in REPORT_FUNCS:
+                raise self.error(t.pos, f'function "{t.text}" not defined')
+            return _Operand("func", t.pos, t.text, name=t.text), i
+        if t.kind == "string":
+            return _Operand("literal", t.pos, t.text, value=unquote(t.text)), i
+        if t.kind == "char":
+            return _Operand("literal", t.pos, t.text, value=ord(unquote(t.text))), i
+        if t.kind == "number":
+            text = t.text.replace("_", "")
+            try:
+                value: Any = int(text, 0)
+            except ValueError:
+                value = float(text)
+            return _Operand("literal", t.pos, t.text, value=value), i
+        raise self.error(t.pos, f"unexpected {t.text!r} in operand")
+
+
+class _Exec:
+    def __init__(self, template: Template, data: Any, funcs: Dict[str, Callable[..., Any]]):
+        self.template = template
+        self.funcs = funcs
+        self.dot = data
+        self.vars: List[Tuple[str, Any]] = [("$", data)]
+        self.out: List[str] = []
+
+    def run(self, nodes: List[Any]) -> str:
+        self.block(nodes, self.dot)
+        return "".join(self.out)
+
+    def fail(self, op: _Operand, message: str) -> TemplateError:
+        return self.template.error(op.pos, f"executing at <{op.text}>: {message}")
+
+    def block(self, nodes: List[Any], dot: Any):
+        mark = len(self.vars)
+        try:
+            for node in nodes:
+                if isinstance(node, _Text):
+                    self.out.append(node.text)
+                elif isinstance(node, _Action):
+                    value = self.pipeline(node.pipe, dot)
+                    if not node.pipe.decl:
+                        self.out.append(_text(value))
+                elif isinstance(node, _Loop):
+                    raise _Break() if node.keyword == "break" else _Continue()
+                elif node.keyword == "range":
+                    self.range(node, dot)
+                else:
+                    self.control(node, dot)
+        finally:
+            del self.vars[mark:]
+
+    def control(self, node: _Control, dot: Any):
+        mark = len(self.vars)
+        value = self.pipeline(node.pipe, dot)
+        if _truth(value):
+            self.block(node.body, value if node.keyword == "with" else dot)
+        else:
+            self.block(node.orelse, dot)
+        del self.vars[mark:]
+
+    def range(self, node: _Control, dot: Any):
+        value = self.pipeline(node.pipe, dot, bind=False)
+        try:
+            items = _items(value)
+        except TypeError as e:
+            raise self.fail(node.pipe.commands[0].args[0], str(e))
+        if not items:
+            self.block(node.orelse, dot)
+            return
+        mark = len(self.vars)
+        for key, elem in items:
+            del self.vars[mark:]
+            if len(node.pipe.decl) == 2:
+                self.vars += [(node.pipe.decl[0], key), (node.pipe.decl[1], elem)]
+            elif node.pipe.decl:
+                self.vars.append((node.pipe.decl[0], elem))
+            try:
+                self.block(node.body, elem)
+            except _Break:
+                break
+            except _Continue:
+                continue
+        del self.vars[mark:]
+
+    def pipeline(self, pipe: _Pipeline, dot: Any, bind: bool = True) -> Any:
+        value: Any = _MISSING
+        for command in pipe.commands:
+            value = self.command(command, dot, value)
+        if pipe.decl and bind:
+            if pipe.assign:
+                self.set(pipe, value)
+            else:
+                self.vars.append((pipe.decl[0], value))
+        return value
+
+    def set(self, pipe: _Pipeline, value: Any):
+        name = pipe.decl[0]
+        for i in range(len(self.vars) - 1, -1, -1):
+            if self.vars[i][0] == name:
+                self.vars[i] = (name, value)
+                return
+        raise self.template.error(pipe.pos, f"undefined variable: {name}")
+
+    def command(self, command: _Command, dot: Any, final: Any) -> Any:
+        first, rest = command.args[0], command.args[1:]
+        piped = [] if final is _MISSING else [final]
+        if first.kind == "func":
+            args = [self.arg(a, dot) for a in rest] + piped
+            return self.call(first, first.name, self.funcs[first.name], args)
+        if not rest and not piped:
+            return self.arg(first, dot)
+        # `.FindStruct "User"`: a helper called with arguments.
+        if first.kind in ("field", "var") and first.chain:
+            receiver = self.chain(self.base(first, dot), first.chain[:-1], first)
+            try:
+                method = _lookup(receiver, first.chain[-1])
+            except AttributeError as e:
+                raise self.fail(first, str(e))
+            if callable(method):
+                args = [self.arg(a, dot) for a in rest] + piped
+                return self.call(first, first.chain[-1], method, args)
+        raise self.fail(first, f"can't give argument to non-function {first.text}")
+
+    def call(self, op: _Operand, name: str, fn: Callable[..., Any], args: List[Any]) -> Any:
+        try:
+            return fn(*args)
+        except TemplateError:
+            raise
+        except Exception as e:
+            raise self.fail(op, f"error calling {name}: {e}")
+
+    def base(self, op: _Operand, dot: Any) -> Any:
+        if op.kind != "var":
+            return dot
+        for name, value in reversed(self.vars):
+            if name == op.name:
+                return value
+        raise self.fail(op, f"undefined variable: {op.name}")
+
+    def arg(self, op: _Operand, dot: Any) -> Any:
+        if op.kind == "literal":
+            return op.value
+        if op.kind == "dot":
+            return dot
+        if op.kind == "func":
+            return self.call(op, op.name, self.funcs[op.name], [])
+        if op.kind == "pipe":
+            return self.chain(self.pipeline(op.value, dot), op.chain, op)
+        return self.chain(self.base(op, dot), op.chain, op)
+
+    def chain(self, value: Any, names: List[str], op: _Operand) -> Any:
+        for name in names:
+            if value is None:
+                raise self.fail(op, f"nil value evaluating .{name}")
+            try:
+                value = _lookup(value, name)
+            except AttributeError as e:
+                raise self.fail(op, str(e))
+            # Helpers without arguments read like fields.
+            if callable(value) and not isinstance(value, type):
+                value = self.call(op, name, value, [])
+        return value
+
+
+def render_template(report: Report, source: str, name: str = "template") -> str:
+    """Run the text/template `source` against `report` (TemplateError if it can't)."""
+    return Template(source, name).execute(report)
@@ -0,0 +1,23 @@
+# src/crowsight/render/todos.py
+
+from itertools import groupby
+from pathlib import Path
+from typing import List
+
+from ..report.models import Report
+
+
+def render_todos(report: Report) -> str:
+    """Group marker comments by file as `path:line: MARKER text` lines."""
+    base = Path(report.root)
+    if base.is_file():
+        base = base.parent
+    out: List[str] = []
+    todos = sorted(report.todos, key=lambda t: (t.file, t.line))
+    for file, items in groupby(todos, key=lambda t: t.file):
+        path = (base / file).as_posix()
+        out.append(path)
+        for t in items:
+            out.append(f"  {path}:{t.line}: {t.marker} {t.text}".rstrip())
+        out.append("")
+    return "\n".join(out)
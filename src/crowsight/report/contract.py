@@ -0,0 +1,155 @@
+# src/crowsight/report/contract.py
+
+from dataclasses import asdict, dataclass, field
+import json
+import re
+from typing import Any, Dict, List, Set
+from urllib.parse import urlsplit
+
+import yaml
+
+from ..render.openapi import openapi_path
+from .models import Endpoint, Report
+
+HTTP_METHODS = ("GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE")
+_TEMPLATE = re.compile(r"\{[^}]*\}")
+
+
+@dataclass
+class Undocumented:
+    """A route in the code that the spec does not describe."""
+
+    service: str
+    method: str
+    path: str
+    file: str
+    line: int
+
+
+@dataclass
+class Unimplemented:
+    """A spec operation no route in the code serves."""
+
+    method: str
+    path: str
+
+
+@dataclass
+class MethodMismatch:
+    """A path both sides have, registered for different methods."""
+
+    path: str
+    code: List[str] = field(default_factory=list)
+    spec: List[str] = field(default_factory=list)
+
+
+@dataclass
+class ContractDrift:
+    undocumented: List[Undocumented] = field(default_factory=list)
+    unimplemented: List[Unimplemented] = field(default_factory=list)
+    mismatched: List[MethodMismatch] = field(default_factory=list)
+
+    @property
+    def empty(self) -> bool:
+        return not (self.undocumented or self.unimplemented or self.mismatched)
+
+    def to_json(self, indent: int = 2) -> str:
+        return json.dumps(asdict(self), indent=indent)
+
+    def to_text(self) -> str:
+        lines: List[str] = []
+        for u in self.undocumented:
+            lines.append(f"+ {u.method} {u.path} ({u.service}, {u.file}:{u.line}): not in spec")
+        for op in self.unimplemented:
+            lines.append(f"- {op.method} {op.path}: not implemented")
+        for m in self.mismatched:
+            lines.append(
+                f"~ {m.path}: code has {', '.join(m.code)}, spec has {', '.join(m.spec)}"
+            )
+        return "\n".join(lines) + "\n" if lines else "Code matches the spec\n"
+
+
+def normalize(path: str) -> str:
+    """Comparison key: router syntax as OpenAPI templates, parameter names dropped."""
+    return _TEMPLATE.sub("{}", openapi_path(path)) or "/"
+
+
+def load_spec(path: str) -> Dict[str, Any]:
+    """Read an OpenAPI (or Swagger 2.0) document from a JSON or YAML file.
+
+    Specs are written by hand or by other tools, so YAML goes through
+    PyYAML rather than report/yaml.py, which only reads crowsight's own output.
+    """
+    with open(path) as fh:
+        text = fh.read()
+    if path.endswith(".json") or text.lstrip().startswith("{"):
+        spec = json.loads(text)
+    else:
+        try:
+            spec = yaml.safe_load(text)
+        except yaml.YAMLError as e:
+            raise ValueError(f"invalid YAML: {e}")
+    if not isinstance(spec, dict) or not isinstance(spec.get("paths"), dict):
+        raise ValueError(f"{path} has no `paths` mapping")
+    return spec
+
+
+def base_path(spec: Dict[str, Any]) -> str:
+    """The prefix spec paths are served under: Swagger's basePath, or a path the servers share."""
+    if isinstance(spec.get("basePath"), str):
+        return spec["basePath"].rstrip("/")
+    servers = spec.get("servers")
+    paths = {
+        urlsplit(s["url"]).path.rstrip("/")
+        for s in (servers if isinstance(servers, list) else [])
+        if isinstance(s, dict) and isinstance(s.get("url"), str)
+    }
+    return paths.pop() if len(paths) == 1 else ""
+
+
+def validate(report: Report, spec: Dict[str, Any]) -> ContractDrift:
+    """Compare the report's HTTP routes with the operations `spec` documents.
+
+    Paths are compared as templates (`:id`, `{id:[0-9]+}` and `{userId}` are
+    all one segment), under the spec's base path. A route registered for
+    any method (`ANY`) implements every operation on its path.
+    """
+    prefix = base_path(spec)
+    documented: Dict[str, Dict[str, str]] = {}
+    for path, item in spec["paths"].items():
+        if not isinstance(item, dict):
+            continue
+        methods = documented.setdefault(normalize(prefix + str(path)), {})
+        for key in item:
+            if str(key).upper() in HTTP_METHODS:
+                methods[str(key).upper()] = prefix + str(path)
+    routes: Dict[str, Dict[str, List[Undocumented]]] = {}
+    for svc in report.services:
+        for ep in svc.endpoints:
+            if ep.protocol == "http":
+                methods = routes.setdefault(normalize(ep.path), {})
+                methods.setdefault(ep.method.upper(), []).append(_ref(svc.name, ep))
+
+    drift = ContractDrift()
+    for key in sorted(routes.keys() | documented.keys()):
+        code, spec_methods = routes.get(key, {}), documented.get(key, {})
+        if not spec_methods:
+            drift.undocumented.extend(r for refs in code.values() for r in refs)
+        elif not code:
+            for method in sorted(spec_methods):
+                drift.unimplemented.append(Unimplemented(method, spec_methods[method]))
+        elif "ANY" not in code and set(code) != set(spec_methods):
+            path = next(iter(spec_methods.values()))
+            drift.mismatched.append(
+                MethodMismatch(path, _ordered(set(code)), _ordered(set(spec_methods)))
+            )
+    return drift
+
+
+def _ref(service: str, ep: Endpoint) -> Undocumented:
+    return Undocumented(service, ep.method, ep.path, ep.file, ep.line)
+
+
+def _ordered(methods: Set[str]) -> List[str]:
+    known = [m for m in HTTP_METHODS if m in methods]
+    return known + sorted(methods - set(HTTP_METHODS))
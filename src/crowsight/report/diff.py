@@ -0,0 +1,161 @@
+# src/crowsight/report/diff.py
+
+from collections import Counter
+from dataclasses import asdict, dataclass, field
+import json
+from typing import Any, Dict, List, Optional, Tuple
+
+from .models import Endpoint, Report, Todo
+
+
+@dataclass
+class EndpointRef:
+    service: str
+    method: str
+    path: str
+
+
+@dataclass
+class EndpointChange:
+    service: str
+    method: str
+    path: str
+    changes: List[str] = field(default_factory=list)
+
+
+@dataclass
+class _Entry:
+    endpoint: Endpoint
+    service: str
+    package: str
+
+
+@dataclass
+class ReportDiff:
+    added: List[EndpointRef] = field(default_factory=list)
+    removed: List[EndpointRef] = field(default_factory=list)
+    changed: List[EndpointChange] = field(default_factory=list)
+    todos_added: List[Todo] = field(default_factory=list)
+    todos_removed: List[Todo] = field(default_factory=list)
+
+    @property
+    def empty(self) -> bool:
+        return not (
+            self.added or self.removed or self.changed or self.todos_added or self.todos_removed
+        )
+
+    def to_json(self, indent: int = 2) -> str:
+        return json.dumps(asdict(self), indent=indent)
+
+    def to_text(self) -> str:
+        lines: List[str] = []
+        for ref in self.added:
+            lines.append(f"+ {ref.method} {ref.path} ({ref.service})")
+        for ref in self.removed:
+            lines.append(f"- {ref.method} {ref.path} ({ref.service})")
+        for ch in self.changed:
+            lines.append(f"~ {ch.method} {ch.path} ({ch.service}): {'; '.join(ch.changes)}")
+        for t in self.todos_added:
+            lines.append(f"+ {t.marker} {t.file}:{t.line} {t.text}".rstrip())
+        for t in self.todos_removed:
+            lines.append(f"- {t.marker} {t.file}:{t.line} {t.text}".rstrip())
+        return "\n".join(lines) + "\n" if lines else "No changes\n"
+
+
+# Services are identified by directory: a root `main` package is named after
+# the checkout directory, which differs between machines.
+Key = Tuple[str, str, str]
+
+
+def _index(report: Report) -> Dict[Key, _Entry]:
+    out: Dict[Key, _Entry] = {}
+    for svc in report.services:
+        for ep in svc.endpoints:
+            out.setdefault((svc.dir, ep.method, ep.path), _Entry(ep, svc.name, svc.package))
+    return out
+
+
+def _ref(key: Key, entry: _Entry) -> EndpointRef:
+    return EndpointRef(service=entry.service, method=key[1], path=key[2])
+
+
+def _schema(report: Report, type_name: Optional[str], package: str) -> Any:
+    """Type name plus its resolved schema, so field-level changes are noticed."""
+    from ..render.goschema import SchemaBuilder
+
+    if not type_name:
+        return None
+    builder = SchemaBuilder(report, "#/")
+    schema = builder.body_schema(type_name, package)
+    defs = {k: builder.defs[k] for k in sorted(builder.defs)}
+    return json.dumps({"type": type_name, "schema": schema, "defs": defs}, sort_keys=True)
+
+
+def _body_changes(old: Report, new: Report, o: _Entry, n: _Entry) -> List[str]:
+    oep, opkg, nep, npkg = o.endpoint, o.package, n.endpoint, n.package
+    changes: List[str] = []
+    for attr in ("request", "response"):
+        before = _schema(old, getattr(oep, attr), opkg)
+        after = _schema(new, getattr(nep, attr), npkg)
+        if before != after:
+            if getattr(oep, attr) != getattr(nep, attr):
+                changes.append(f"{attr} {getattr(oep, attr)} -> {getattr(nep, attr)}")
+            else:
+                changes.append(f"{attr} schema of {getattr(nep, attr)} changed")
+    return changes
+
+
+def diff(old: Report, new: Report) -> ReportDiff:
+    """Endpoint-level changes between two reports, independent of their ordering."""
+    before, after = _index(old), _index(new)
+    result = ReportDiff()
+
+    for key in sorted(before.keys() & after.keys()):
+        changes = _body_changes(old, new, before[key], after[key])
+        if changes:
+            ref = _ref(key, after[key])
+            result.changed.append(EndpointChange(ref.service, ref.method, ref.path, changes))
+
+    gone = sorted(before.keys() - after.keys())
+    came = sorted(after.keys() - before.keys())
+    # A route that kept its path but swapped its single method is a change,
+    # not a removal plus an addition.
+    for key in list(gone):
+        svc, method, path = key
+        olds = [k for k in gone if k[0] == svc and k[2] == path]
+        news = [k for k in came if k[0] == svc and k[2] == path]
+        if len(olds) == 1 and len(news) == 1:
+            nkey = news[0]
+            changes = [f"method {method} -> {nkey[1]}"]
+            changes += _body_changes(old, new, before[key], after[nkey])
+            ref = _ref(nkey, after[nkey])
+            result.changed.append(EndpointChange(ref.service, ref.method, ref.path, changes))
+            gone.remove(key)
+            came.remove(nkey)
+
+    result.removed = [_ref(k, before[k]) for k in gone]
+    result.added = [_ref(k, after[k]) for k in came]
+    result.changed.sort(key=lambda c: (c.service, c.path, c.method))
+    result.todos_added, result.todos_removed = _todo_changes(old, new)
+    return result
+
+
+def _todo_changes(old: Report, new: Report) -> Tuple[List[Todo], List[Todo]]:
+    """Markers keyed by file and text, so edits that only shift lines don't count."""
+
+    def key(t: Todo) -> Tuple[str, str, str]:
+        return (t.file, t.marker, t.text)
+
+    before = Counter(key(t) for t in old.todos)
+    after = Counter(key(t) for t in new.todos)
+    extra, missing = after - before, before - after
+    added = [t for t in new.todos if _take(extra, key(t))]
+    removed = [t for t in old.todos if _take(missing, key(t))]
+    return added, removed
+
+
+def _take(counts: Counter, k) -> bool:
+    if counts[k] <= 0:
+        return False
+    counts[k] -= 1
+    return True
@@ -0,0 +1,540 @@
+# src/crowsight/report/models.py
+
+from dataclasses import dataclass, field, asdict, fields, is_dataclass
+from enum import Enum
+from typing import (
+    Any,
+    Callable,
+    Dict,
+    List,
+    Optional,
+    Sequence,
+    Type,
+    TypeVar,
+    get_args,
+    get_origin,
+    get_type_hints,
+)
+import hashlib
+import json
+import threading
+
+from . import yaml
+from .schema import SCHEMA_VERSION, check_fingerprint, migrate
+
+T = TypeVar("T")
+
+
+def from_dict(cls: Type[T], data: Dict[str, Any]) -> T:
+    """Rebuild a (nested) report dataclass from its `asdict` form."""
+    hints = get_type_hints(cls)
+    kwargs = {}
+    for f in fields(cls):
+        if f.name in data:
+            kwargs[f.name] = _load_value(hints[f.name], data[f.name])
+    return cls(**kwargs)
+
+
+def _load_value(tp, value):
+    if value is None:
+        return None
+    origin = get_origin(tp)
+    if origin is list:
+        (item,) = get_args(tp)
+        return [_load_value(item, v) for v in value]
+    if origin is dict:
+        _, item = get_args(tp)
+        return {k: _load_value(item, v) for k, v in value.items()}
+    args = [a for a in get_args(tp) if a is not type(None)]
+    if args and origin is not None:
+        # Optional[X]
+        return _load_value(args[0], value)
+    if is_dataclass(tp):
+        return from_dict(tp, value)
+    if isinstance(tp, type) and issubclass(tp, Enum):
+        return tp(value)
+    return value
+
+
+@dataclass
+class Todo:
+    """A TODO/FIXME-style marker comment."""
+
+    file: str
+    line: int
+    marker: str
+    text: str
+
+
+@dataclass
+class Param:
+    """A parameter taken from an endpoint's route pattern."""
+
+    name: str
+    # JSON Schema type: "integer" when the route's regex or the handler's parsing says so.
+    type: str = "string"
+    # Regex constraint from the pattern (`{id:[0-9]+}`), if any.
+    pattern: Optional[str] = None
+    catch_all: bool = False
+    # Whether the handler reads it; None when that cannot be told statically.
+    used: Optional[bool] = None
+
+
+@dataclass
+class DBQuery:
+    """A database call made by an endpoint's handler or a function it calls."""
+
+    # The method called, e.g. `QueryRow` (database/sql, sqlx) or `Find` (gorm).
+    call: str
+    # The SQL string literal passed to it, when there is one.
+    query: Optional[str] = None
+    file: str = ""
+    line: int = 0
+    # Functions followed from the handler to reach the call, outermost first.
+    via: List[str] = field(default_factory=list)
+    # Made inside a loop (in the handler or along `via`): a likely N+1 query.
+    in_loop: bool = False
+
+    @property
+    def summary(self) -> str:
+        return f"{self.call}: {self.query}" if self.query else self.call
+
+
+@dataclass
+class CallEdge:
+    """A call from one function to another, on the way from an endpoint's handler."""
+
+    caller: str
+    # `pkg.Func` or `pkg.Type.Method`; for standard library leaves, as qualified in the code.
+    callee: str
+    file: str = ""
+    line: int = 0
+    stdlib: bool = False
+
+
+@dataclass
+class Endpoint:
+    """A single route registration discovered in a Go service."""
+
+    method: str
+    path: str
+    handler: Optional[str] = None
+    file: str = ""
+    line: int = 0
+    request: Optional[str] = None
+    response: Optional[str] = None
+    complexity: int = 0
+    # Where the handler's body is declared, once resolved from the registration.
+    handler_file: Optional[str] = None
+    handler_line: int = 0
+    # TODO-style comments inside the handler's body.
+    todos: List[Todo] = field(default_factory=list)
+    # "http" or "grpc"; gRPC endpoints use method GRPC and path /Service/Method.
+    protocol: str = "http"
+    rpc_service: Optional[str] = None
+    rpc_method: Optional[str] = None
+    path_params: List[Param] = field(default_factory=list)
+    # Middleware wrapping the handler, outermost first; unnamed ones as source text.
+    middleware: List[str] = field(default_factory=list)
+    # One summary per database call (`QueryRow: SELECT ...`), then the details.
+    db_access: List[str] = field(default_factory=list)
+    db_queries: List[DBQuery] = field(default_factory=list)
+    # HTTP statuses the handler can respond with, including an implicit 200.
+    status_codes: List[int] = field(default_factory=list)
+    # Calls reachable from the handler, with --callgraph; the handler is the first caller.
+    call_graph: List[CallEdge] = field(default_factory=list)
+
+
+@dataclass
+class Handler:
+    """A function with an HTTP handler signature."""
+
+    name: str
+    receiver: Optional[str] = None
+    file: str = ""
+    line: int = 0
+    request: Optional[str] = None
+    response: Optional[str] = None
+    complexity: int = 0
+
+
+@dataclass
+class StructField:
+    name: str
+    type: str
+    # Name from the `json` tag; None when the field has no json tag at all.
+    json: Optional[str] = None
+    omitempty: bool = False
+    embedded: bool = False
+    line: int = 0
+
+    @property
+    def exported(self) -> bool:
+        return self.name[:1].isupper()
+
+
+@dataclass
+class Struct:
+    """A struct type used as (or nested inside) a request/response DTO."""
+
+    name: str
+    package: str
+    file: str = ""
+    line: int = 0
+    fields: List[StructField] = field(default_factory=list)
+
+
+@dataclass
+class OutboundCall:
+    """An HTTP request this service makes (http.Get, NewRequest, client.Do...)."""
+
+    method: str
+    # Best-effort URL with unresolvable parts shown as `{}`; None if unknown.
+    target: Optional[str]
+    # Source expression of the URL argument, e.g. `os.Getenv("API") + "/x"`.
+    expression: str
+    resolved: bool = False
+    file: str = ""
+    line: int = 0
+    # Filled in after the scan when the target matches another service's route;
+    # names can repeat across directories, so `service_dir` is what identifies it.
+    service: Optional[str] = None
+    service_dir: Optional[str] = None
+    endpoint: Optional[str] = None
+
+
+@dataclass
+class EnvUsage:
+    """One place an environment variable is read."""
+
+    file: str
+    line: int
+    # The reading function, e.g. `os.Getenv` or `os.LookupEnv`.
+    call: str
+
+
+@dataclass
+class EnvVar:
+    """An environment variable a service reads, with every location it is read from."""
+
+    # Variable name, or the source expression when it is not a constant string.
+    name: str
+    resolved: bool = True
+    usages: List[EnvUsage] = field(default_factory=list)
+
+
+@dataclass
+class Service:
+    """A Go package that registers or serves HTTP handlers."""
+
+    name: str
+    package: str
+    dir: str
+    # Path of the go.mod module the package belongs to (the innermost one).
+    module: Optional[str] = None
+    files: List[str] = field(default_factory=list)
+    # Literal (quoted) or source expression of the address passed to ListenAndServe.
+    listen_addr: Optional[str] = None
+    # Where that call is.
+    listen_file: Optional[str] = None
+    listen_line: int = 0
+    endpoints: List[Endpoint] = field(default_factory=list)
+    handlers: List[Handler] = field(default_factory=list)
+    structs: List[Struct] = field(default_factory=list)
+    outbound_calls: List[OutboundCall] = field(default_factory=list)
+    env_vars: List[EnvVar] = field(default_factory=list)
+    # Whether the HTTP routes (other than `/`) either all end in a slash or none do;
+    # None without such routes.
+    consistent_slashes: Optional[bool] = None
+    # Whether an HTTP route serves Prometheus metrics (`/metrics`, `promhttp.Handler()`),
+    # and whether one is a health or readiness check (`/healthz`, `/readyz`, ...).
+    has_metrics: bool = False
+    has_health: bool = False
+
+    def struct(self, name: str) -> Optional[Struct]:
+        return next((s for s in self.structs if s.name == name), None)
+
+
+class Severity(str, Enum):
+    """How serious a finding is; compares by rank (INFO < WARNING < ERROR)."""
+
+    INFO = "info"
+    WARNING = "warning"
+    ERROR = "error"
+
+    def __str__(self) -> str:
+        return self.value
+
+    @property
+    def rank(self) -> int:
+        return list(Severity).index(self)
+
+
+@dataclass
+class Finding:
+    """A problem reported by one of the analyzers."""
+
+    code: str
+    severity: Severity
+    message: str
+    file: str
+    line: int
+    column: int = 0
+    # Source text of the offending expression, when there is one.
+    expression: Optional[str] = None
+    # Registered name of the analyzer that reported it.
+    analyzer: Optional[str] = None
+
+
+@dataclass
+class ScanError:
+    """A file that could not be (fully) parsed."""
+
+    file: str
+    message: str
+
+
+@dataclass
+class Metrics:
+    """Where a profiled scan (`--profile`) spent its time, in seconds."""
+
+    # Scan phases (discover, parse, extract, types, link, ...); time spent in
+    # a nested phase is not counted again in the phase around it. Parsing
+    # runs on worker threads, so its figure is summed across them.
+    phases: Dict[str, float] = field(default_factory=dict)
+    # Analyzer name -> time spent in its analyze and finish calls.
+    analyzers: Dict[str, float] = field(default_factory=dict)
+    files_parsed: int = 0
+    bytes_parsed: int = 0
+    total: float = 0.0
+    # Peak resident set size of the process, when the platform reports it.
+    peak_rss_bytes: Optional[int] = None
+
+
+@dataclass
+class ModuleGroup:
+    """A go.mod module and the directories of the services it contains."""
+
+    path: str
+    services: List[str] = field(default_factory=list)
+
+
+def _canonical(value: Any) -> str:
+    return json.dumps(value, sort_keys=True, separators=(",", ":"))
+
+
+def _fraction(part: int, whole: int) -> Optional[float]:
+    return round(part / whole, 4) if whole else None
+
+
+@dataclass
+class Summary:
+    """Totals of a scan, for CI logs and exit codes (see `Report.summarize`)."""
+
+    services: int = 0
+    endpoints: int = 0
+    findings: int = 0
+    # Severity -> number of findings; every severity is listed.
+    by_severity: Dict[str, int] = field(default_factory=dict)
+    # Analyzer name -> number of findings it reported.
+    by_analyzer: Dict[str, int] = field(default_factory=dict)
+    # Fraction of the services with HTTP routes that serve metrics, and a health
+    # check; None when no service has HTTP routes.
+    metrics_coverage: Optional[float] = None
+    health_coverage: Optional[float] = None
+
+    def line(self) -> str:
+        """`2 services, 9 endpoints, 4 findings (error: 1, warning: 3, info: 0)`.
+
+        Followed by `; metrics: 50%, health: 100%` when services have HTTP routes.
+        """
+        counts = ", ".join(
+            f"{s.value}: {self.by_severity.get(s.value, 0)}" for s in reversed(Severity)
+        )
+        line = (
+            f"{self.services} services, {self.endpoints} endpoints, "
+            f"{self.findings} findings ({counts})"
+        )
+        if self.metrics_coverage is not None and self.health_coverage is not None:
+            line += (
+                f"; metrics: {self.metrics_coverage:.0%}, health: {self.health_coverage:.0%}"
+            )
+        return line
+
+
+@dataclass
+class Report:
+    """Everything a scan learned about the services under `root`."""
+
+    root: str
+    # Layout version of the serialized report; see report/schema.py.
+    schema_version: int = SCHEMA_VERSION
+    services: List[Service] = field(default_factory=list)
+    todos: List[Todo] = field(default_factory=list)
+    findings: List[Finding] = field(default_factory=list)
+    errors: List[ScanError] = field(default_factory=list)
+    # Only set by profiled scans.
+    metrics: Optional[Metrics] = None
+    # Only set by `group_by_module` (`--group-by module`).
+    modules: Optional[List[ModuleGroup]] = None
+    # Set by `summarize`, which every scan calls last.
+    summary: Optional[Summary] = None
+    # `content_hash()` of a completed scan.
+    hash: Optional[str] = None
+
+    def __post_init__(self):
+        # Not report data: `update_files` swaps results in under `lock`, through
+        # the scanner that produced the report when it is known.
+        self.lock = threading.RLock()
+        self.scanner: Any = None
+
+    @property
+    def endpoints(self) -> List[Endpoint]:
+        return [ep for svc in self.services for ep in svc.endpoints]
+
+    # Query helpers. They read the report as it is, in its current order
+    # (stable once `sort` has run, as it has for every scanned report).
+
+    def walk(self, visitor: Callable[[Service, Endpoint], None]):
+        """Call `visitor(service, endpoint)` for every endpoint of every service."""
+        for svc in self.services:
+            for ep in svc.endpoints:
+                visitor(svc, ep)
+
+    def find_endpoints(self, predicate: Callable[[Endpoint], bool]) -> List[Endpoint]:
+        return [ep for ep in self.endpoints if predicate(ep)]
+
+    def endpoints_by_method(self, method: str) -> List[Endpoint]:
+        """Endpoints registered for `method` (case-insensitive); "ANY" only matches "ANY"."""
+        method = method.upper()
+        return self.find_endpoints(lambda ep: ep.method.upper() == method)
+
+    def service_by_name(self, name: str) -> Optional[Service]:
+        """The first service called `name`; names can repeat across directories."""
+        return next((svc for svc in self.services if svc.name == name), None)
+
+    def find_struct(self, type_name: str, package: str = "") -> Optional[Struct]:
+        """Resolve `T` (within `package`) or `pkg.T` against every service's DTOs.
+
+        A service's DTOs may come from other packages, so the struct's own
+        package is what `pkg` is matched against.
+        """
+        qualifier, _, name = type_name.rpartition(".")
+        qualifier = qualifier or package
+        for svc in self.services:
+            for st in svc.structs:
+                if st.name == name and (not qualifier or st.package == qualifier):
+                    return st
+        return None
+
+    def group_by_module(self):
+        """Fill `modules`; services outside any module go under the path ""."""
+        groups: Dict[str, ModuleGroup] = {}
+        for svc in self.services:
+            path = svc.module or ""
+            groups.setdefault(path, ModuleGroup(path=path)).services.append(svc.dir)
+        self.modules = [groups[p] for p in sorted(groups)]
+
+    def update_files(
+        self,
+        root: str,
+        changed: Sequence[str],
+        deleted: Sequence[str] = (),
+        options: Any = None,
+        cancel: Optional[threading.Event] = None,
+    ):
+        """Bring the report up to date after `changed`/`deleted` files, in place.
+
+        Paths are relative to `root` (or absolute). The result is what a full
+        scan of `root` would report; for a report `scan` returned, only the
+        directories the files affect are analyzed again. Other reports (loaded
+        from JSON, or updated with different `options`) are rescanned in full
+        once. Readers holding `lock` never see a half-merged report.
+        """
+        from ..scanner.scanner import GoScanner
+
+        scanner = self.scanner
+        if scanner is None or not scanner.scans(root, options):
+            scanner = GoScanner(root, options)
+        scanner.update(self, changed, deleted, cancel)
+
+    def summarize(self) -> Summary:
+        """Count services, endpoints and findings into `summary`."""
+        by_severity = {s.value: 0 for s in Severity}
+        by_analyzer: Dict[str, int] = {}
+        for fd in self.findings:
+            by_severity[fd.severity.value] += 1
+            name = fd.analyzer or "unknown"
+            by_analyzer[name] = by_analyzer.get(name, 0) + 1
+        served = [s for s in self.services if any(e.protocol == "http" for e in s.endpoints)]
+        self.summary = Summary(
+            services=len(self.services),
+            endpoints=len(self.endpoints),
+            findings=len(self.findings),
+            by_severity=by_severity,
+            by_analyzer=dict(sorted(by_analyzer.items())),
+            metrics_coverage=_fraction(sum(s.has_metrics for s in served), len(served)),
+            health_coverage=_fraction(sum(s.has_health for s in served), len(served)),
+        )
+        return self.summary
+
+    def sort(self):
+        """Put everything in a stable order, independent of scan scheduling."""
+        self.services.sort(key=lambda s: (s.dir, s.package))
+        for svc in self.services:
+            svc.endpoints.sort(key=lambda e: (e.path, e.method, e.file, e.line))
+            svc.handlers.sort(key=lambda h: (h.file, h.line))
+            svc.structs.sort(key=lambda s: (s.name, s.package))
+            svc.outbound_calls.sort(key=lambda c: (c.file, c.line))
+            svc.env_vars.sort(key=lambda v: (not v.resolved, v.name))
+            for var in svc.env_vars:
+                var.usages.sort(key=lambda u: (u.file, u.line))
+        self.todos.sort(key=lambda t: (t.file, t.line))
+        self.findings.sort(key=lambda f: (f.file, f.line, f.column, f.code))
+        self.errors.sort(key=lambda e: e.file)
+
+    def content_hash(self) -> str:
+        """SHA-256 of what the report says about the code, independent of where and how it ran.
+
+        The root path, profiling metrics, module grouping and summary are left
+        out, and unordered lists are sorted by content, so unchanged code
+        hashes the same on any machine and in any scan order.
+        """
+        data = asdict(self)
+        for key in ("root", "metrics", "modules", "summary", "hash"):
+            del data[key]
+        for svc in data["services"]:
+            for key in ("endpoints", "handlers", "structs", "outbound_calls", "env_vars"):
+                svc[key].sort(key=_canonical)
+        for key in ("services", "todos", "findings", "errors"):
+            data[key].sort(key=_canonical)
+        return hashlib.sha256(_canonical(data).encode()).hexdigest()
+
+    def to_dict(self) -> Dict[str, Any]:
+        check_fingerprint(Report)
+        data = asdict(self)
+        for key in ("metrics", "modules", "summary", "hash"):
+            if data[key] is None:
+                del data[key]
+        return data
+
+    def to_json(self, indent: int = 2) -> str:
+        return json.dumps(self.to_dict(), indent=indent)
+
+    @classmethod
+    def from_data(cls, data: Any) -> "Report":
+        """Load a serialized report, migrating older schema versions (SchemaError if it can't)."""
+        if not isinstance(data, dict):
+            raise ValueError("a report must be a mapping")
+        return from_dict(cls, migrate(data))
+
+    @classmethod
+    def from_json(cls, text: str) -> "Report":
+        return cls.from_data(json.loads(text))
+
+    def to_yaml(self) -> str:
+        return yaml.dumps(self.to_dict())
+
+    @classmethod
+    def from_yaml(cls, text: str) -> "Report":
+        return cls.from_data(yaml.loads(text))
@@ -0,0 +1,161 @@
+# src/crowsight/report/schema.py
+
+"""Versioning of the serialized report.
+
+Every saved report carries `schema_version`. Whenever the report
+dataclasses change, bump SCHEMA_VERSION, add a MIGRATIONS step that rewrites
+a previous-version dict into the new shape (a plain pass-through when fields
+were only added, since those load with their defaults), and record the new
+fingerprint. `check_fingerprint` warns when the classes no longer match the
+recorded fingerprint, so a forgotten bump shows up the first time a report
+is written.
+"""
+
+from dataclasses import fields, is_dataclass
+from enum import Enum
+from functools import lru_cache
+import hashlib
+from typing import Any, Callable, Dict, List, get_args, get_type_hints
+
+from loguru import logger
+
+SCHEMA_VERSION = 10
+# Fingerprint of the report dataclasses at each version (see `fingerprint`).
+FINGERPRINTS = {
+    2: "37415f0b090e",
+    3: "171de02ba4d9",
+    4: "5777a6a87e70",
+    5: "9dbccd58f1a8",
+    6: "1d1f496f8971",
+    7: "1d9eef6875fd",
+    8: "50cf44ff8aab",
+    9: "33da5b11e3d0",
+    10: "5bf4b9d8a4b2",
+}
+
+
+class SchemaError(ValueError):
+    pass
+
+
+def _from_v1(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 1 is every report written before schema_version existed. Its
+    # shape is a subset of version 2: fields added since load with defaults.
+    return data
+
+
+def _from_v2(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 3 added Finding.analyzer and Report.summary.
+    return data
+
+
+def _from_v3(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 4 added Endpoint.status_codes.
+    return data
+
+
+def _from_v4(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 5 added Endpoint.call_graph.
+    return data
+
+
+def _from_v5(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 6 added Service.consistent_slashes.
+    return data
+
+
+def _from_v6(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 7 added Service.has_metrics/has_health and their Summary coverage.
+    return data
+
+
+def _from_v7(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 8 added Report.hash.
+    return data
+
+
+def _from_v8(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 9 added Service.listen_file/listen_line.
+    return data
+
+
+def _from_v9(data: Dict[str, Any]) -> Dict[str, Any]:
+    # Version 10 added OutboundCall.service_dir; a linked service name that is
+    # unique in the report still says which directory it was.
+    dirs: Dict[str, List[str]] = {}
+    for svc in data.get("services") or []:
+        dirs.setdefault(svc.get("name"), []).append(svc.get("dir"))
+    for svc in data.get("services") or []:
+        for call in svc.get("outbound_calls") or []:
+            found = dirs.get(call.get("service"), [])
+            if len(found) == 1 and "service_dir" not in call:
+                call["service_dir"] = found[0]
+    return data
+
+
+# Version N -> function turning a version-N dict into a version N+1 dict.
+MIGRATIONS: Dict[int, Callable[[Dict[str, Any]], Dict[str, Any]]] = {
+    1: _from_v1,
+    2: _from_v2,
+    3: _from_v3,
+    4: _from_v4,
+    5: _from_v5,
+    6: _from_v6,
+    7: _from_v7,
+    8: _from_v8,
+    9: _from_v9,
+}
+
+
+def migrate(data: Dict[str, Any]) -> Dict[str, Any]:
+    """Bring a serialized report up to SCHEMA_VERSION, or raise SchemaError."""
+    version = data.get("schema_version", 1)
+    if isinstance(version, bool) or not isinstance(version, int) or version < 1:
+        raise SchemaError(f"invalid schema_version {version!r}")
+    if version > SCHEMA_VERSION:
+        raise SchemaError(
+            f"report schema version {version} is newer than this crowsight supports "
+            f"({SCHEMA_VERSION}); upgrade crowsight to read it"
+        )
+    while version < SCHEMA_VERSION:
+        step = MIGRATIONS.get(version)
+        if step is None:
+            raise SchemaError(f"cannot migrate a version {version} report")
+        data = step(dict(data))
+        version += 1
+    data["schema_version"] = SCHEMA_VERSION
+    return data
+
+
+def _describe(tp: Any, seen: List[type]) -> List[str]:
+    out: List[str] = []
+    for arg in get_args(tp):
+        out.extend(_describe(arg, seen))
+    if isinstance(tp, type) and tp not in seen:
+        if is_dataclass(tp):
+            seen.append(tp)
+            hints = get_type_hints(tp)
+            for f in fields(tp):
+                out.append(f"{tp.__name__}.{f.name}:{hints[f.name]}")
+                out.extend(_describe(hints[f.name], seen))
+        elif issubclass(tp, Enum):
+            seen.append(tp)
+            out.append(f"{tp.__name__}={','.join(str(m.value) for m in tp)}")
+    return out
+
+
+def fingerprint(cls: type) -> str:
+    """Short hash of the fields and types of `cls` and every dataclass it contains."""
+    text = "\n".join(_describe(cls, []))
+    return hashlib.sha256(text.encode()).hexdigest()[:12]
+
+
+@lru_cache(maxsize=None)
+def check_fingerprint(cls: type):
+    """Warn (once) when the report classes changed without a SCHEMA_VERSION bump."""
+    current = fingerprint(cls)
+    if FINGERPRINTS.get(SCHEMA_VERSION) != current:
+        logger.warning(
+            f"Report models changed without a schema bump: set SCHEMA_VERSION and "
+            f"FINGERPRINTS in report/schema.py (fingerprint {current})"
+        )
@@ -0,0 +1,347 @@
+# src/crowsight/report/yaml.py
+
+"""The YAML form of crowsight's own reports (`--format yaml`), read and written here.
+
+`dumps` writes block-style YAML: mappings and sequences nested by two spaces,
+multi-line strings as `|` block scalars, and anything that could be misread
+as another type double-quoted. `loads` reads that back, along with the
+edits such a file is likely to get by hand (comments, single quotes, `-`
+items at their key's indentation, `~`, one-line `[a, b]` lists of scalars).
+
+It is not a YAML parser for arbitrary documents: folded (`>`) scalars, flow
+mappings, anchors, tags, document markers and tab indentation raise
+YAMLError rather than being read some other way. Files people write
+themselves, such as crowsight.yaml and OpenAPI specs, are read with PyYAML.
+"""
+
+from enum import Enum
+import json
+import re
+from typing import Any, List, Optional, Tuple
+
+INDENT = "  "
+
+_PLAIN = re.compile(r"^[A-Za-z_/$][A-Za-z0-9_./$ (){}*<>=+@-]*$")
+_RESERVED = {"null", "true", "false", "yes", "no", "on", "off", "y", "n", "~"}
+_INT = re.compile(r"^[-+]?[0-9]+$")
+_FLOAT = re.compile(r"^[-+]?([0-9]+\.[0-9]*|\.[0-9]+|[0-9]+)([eE][-+]?[0-9]+)?$")
+
+
+class YAMLError(ValueError):
+    pass
+
+
+def dumps(data: Any) -> str:
+    """Serialize dicts, lists and scalars as block-style YAML."""
+    if isinstance(data, (dict, list)) and data:
+        return "\n".join(_dump_block(data, 0)) + "\n"
+    return _scalar(data, 0) + "\n"
+
+
+def _dump_block(data: Any, depth: int) -> List[str]:
+    pad = INDENT * depth
+    lines: List[str] = []
+    if isinstance(data, dict):
+        for key, value in data.items():
+            head = f"{pad}{_scalar(str(key), depth, key=True)}:"
+            lines.extend(_dump_entry(head, value, depth))
+    else:
+        for item in data:
+            if isinstance(item, dict) and item:
+                # `- key: value` with the remaining keys aligned under the first.
+                inner = _dump_block(item, depth + 1)
+                inner[0] = f"{pad}- {inner[0][len(pad) + len(INDENT):]}"
+                lines.extend(inner)
+            else:
+                lines.extend(_dump_entry(f"{pad}-", item, depth))
+    return lines
+
+
+def _dump_entry(head: str, value: Any, depth: int) -> List[str]:
+    if isinstance(value, (dict, list)) and value:
+        return [head, *_dump_block(value, depth + 1)]
+    return [f"{head} {_scalar(value, depth + 1)}"]
+
+
+def _scalar(value: Any, depth: int, key: bool = False) -> str:
+    if isinstance(value, Enum):
+        value = value.value
+    if value is None:
+        return "null"
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (int, float)):
+        return repr(value)
+    if isinstance(value, dict):
+        return "{}"
+    if isinstance(value, list):
+        return "[]"
+    text = str(value)
+    if not key and _block_safe(text):
+        chomp = "" if text.endswith("\n") else "-"
+        pad = INDENT * max(depth, 1)
+        body = "\n".join(pad + line if line else "" for line in text.rstrip("\n").split("\n"))
+        return f"|{chomp}\n{body}"
+    if _PLAIN.match(text) and text == text.strip() and text.lower() not in _RESERVED:
+        return text
+    return json.dumps(text)
+
+
+def _block_safe(text: str) -> bool:
+    """Multi-line text that a literal block scalar reproduces exactly."""
+    if "\n" not in text.rstrip("\n") or text.endswith("\n\n"):
+        return False
+    body = text.rstrip("\n")
+    if text[:1] in (" ", "\t", "\n") or body != body.rstrip():
+        return False
+    return all(ch == "\n" or ch == "\t" or ch.isprintable() for ch in text)
+
+
+def loads(text: str) -> Any:
+    """Parse the block subset `dumps` writes (see the module docstring)."""
+    lines = [
+        (len(raw) - len(raw.lstrip(" ")), raw)
+        for raw in text.replace("\r\n", "\n").split("\n")
+    ]
+    parser = _Parser(lines)
+    parser.skip()
+    if parser.done():
+        return None
+    value = parser.node(parser.indent())
+    parser.skip()
+    if not parser.done():
+        raise YAMLError(f"line {parser.pos + 1}: unexpected content")
+    return value
+
+
+class _Parser:
+    def __init__(self, lines: List[Tuple[int, str]]):
+        # (indent, text) pairs; `- ` items are rewritten in place as indentation.
+        self.lines = lines
+        self.pos = 0
+
+    def done(self) -> bool:
+        return self.pos >= len(self.lines)
+
+    def indent(self) -> int:
+        return self.lines[self.pos][0]
+
+    def text(self) -> str:
+        return self.lines[self.pos][1].strip()
+
+    def skip(self):
+        """Move past blank and comment-only lines, to the next line of structure."""
+        while not self.done() and (not self.text() or self.text().startswith("#")):
+            self.pos += 1
+        if self.done():
+            return
+        indent, raw = self.lines[self.pos]
+        text = self.text()
+        if raw[indent:indent + 1] == "\t":
+            raise YAMLError(f"line {self.pos + 1}: tabs cannot indent YAML")
+        if text in ("---", "...") or text.startswith("--- "):
+            raise YAMLError(f"line {self.pos + 1}: document markers are not supported")
+        if text == "?" or text.startswith("? "):
+            raise YAMLError(f"line {self.pos + 1}: complex keys are not supported")
+
+    def node(self, indent: int) -> Any:
+        if self.text() == "-" or self.text().startswith("- "):
+            return self.sequence(indent)
+        if _split_key(self.text()) is not None:
+            return self.mapping(indent)
+        return self.value(self.text(), indent - 1)
+
+    def sequence(self, indent: int) -> List[Any]:
+        items: List[Any] = []
+        while True:
+            self.skip()
+            if self.done() or self.indent() != indent:
+                break
+            text = self.text()
+            if text != "-" and not text.startswith("- "):
+                break
+            rest = text[1:].lstrip(" ")
+            if not rest:
+                self.pos += 1
+                self.skip()
+                if self.done() or self.indent() <= indent:
+                    items.append(None)
+                else:
+                    items.append(self.node(self.indent()))
+                continue
+            # Treat `- x` as `x` indented past the dash, then parse it as a node.
+            inner = indent + len(text) - len(rest)
+            self.lines[self.pos] = (inner, " " * inner + rest)
+            items.append(self.item(inner))
+        return items
+
+    def item(self, indent: int) -> Any:
+        if self.text() == "-" or self.text().startswith("- "):
+            return self.sequence(indent)
+        if _split_key(self.text()) is not None:
+            return self.mapping(indent)
+        return self.value(self.text(), indent - 1)
+
+    def mapping(self, indent: int) -> dict:
+        result: dict = {}
+        while True:
+            self.skip()
+            if self.done() or self.indent() != indent:
+                break
+            parts = _split_key(self.text())
+            if parts is None:
+                break
+            key, rest = parts
+            if key in result:
+                raise YAMLError(f"line {self.pos + 1}: duplicate key {key!r}")
+            if rest:
+                result[key] = self.value(rest, indent)
+                continue
+            self.pos += 1
+            self.skip()
+            if self.done():
+                result[key] = None
+            elif self.indent() > indent:
+                result[key] = self.node(self.indent())
+            elif self.indent() == indent and (self.text() == "-" or self.text().startswith("- ")):
+                # A sequence may sit at its key's own indentation.
+                result[key] = self.sequence(indent)
+            else:
+                result[key] = None
+        return result
+
+    def value(self, rest: str, indent: int) -> Any:
+        """An inline value on the current line; block scalars consume the lines after it."""
+        if rest[:1] == "|" and re.fullmatch(r"\|[-+]?", rest):
+            self.pos += 1
+            return self.block(rest, indent)
+        value = _parse_scalar(rest, self.pos)
+        self.pos += 1
+        return value
+
+    def block(self, header: str, indent: int) -> str:
+        body: List[str] = []
+        content: Optional[int] = None
+        while not self.done():
+            width, raw = self.lines[self.pos]
+            if raw.strip():
+                if width <= indent:
+                    break
+                if content is None:
+                    content = width
+                if width < content:
+                    raise YAMLError(f"line {self.pos + 1}: bad block indentation")
+                body.append(raw[content:])
+            else:
+                body.append(raw[content:] if content is not None else "")
+            self.pos += 1
+        # Trailing blank lines only matter to `|+`, which is read as `|`.
+        while body and not body[-1].strip():
+            body.pop()
+        text = "\n".join(body)
+        if header == "|-":
+            return text
+        return text + "\n" if text else ""
+
+
+def _split_key(text: str) -> Optional[Tuple[str, str]]:
+    """(key, rest) of a `key: value` line, or None if it is not one."""
+    if text[:1] in ('"', "'"):
+        end = _quoted_end(text)
+        if end is None or text[end:end + 1] != ":":
+            return None
+        key = _parse_scalar(text[:end], 0)
+        rest = text[end + 1:]
+        if rest and not rest.startswith(" "):
+            return None
+        return str(key), _strip_comment(rest.strip())
+    m = re.match(r"^([^#\s][^:]*?):(?:\s+(.*))?$", text)
+    if m is None or m.group(1).startswith(("- ", "[", "{")):
+        return None
+    return m.group(1), _strip_comment((m.group(2) or "").strip())
+
+
+def _quoted_end(text: str) -> Optional[int]:
+    quote = text[0]
+    i = 1
+    while i < len(text):
+        ch = text[i]
+        if quote == '"' and ch == "\\":
+            i += 2
+            continue
+        if ch == quote:
+            if quote == "'" and text[i + 1:i + 2] == "'":
+                i += 2
+                continue
+            return i + 1
+        i += 1
+    return None
+
+
+def _strip_comment(text: str) -> str:
+    if text[:1] in ('"', "'"):
+        end = _quoted_end(text)
+        # Anything but a comment after the string is left for _parse_scalar to refuse.
+        if end is not None and text[end:].strip()[:1] in ("", "#"):
+            return text[:end]
+        return text
+    idx = text.find(" #")
+    return text[:idx].rstrip() if idx >= 0 else text
+
+
+def _flow_items(text: str, pos: int) -> List[str]:
+    """Split the inside of `[a, "b, c"]` on its top-level commas."""
+    items: List[str] = []
+    rest = text.strip()
+    while rest:
+        if rest[:1] in ('"', "'"):
+            end = _quoted_end(rest)
+            if end is None:
+                raise YAMLError(f"line {pos + 1}: unterminated string in list")
+        else:
+            end = rest.find(",")
+            end = len(rest) if end < 0 else end
+        item, rest = rest[:end].strip(), rest[end:].strip()
+        if item[:1] in ("[", "{"):
+            raise YAMLError(f"line {pos + 1}: nested flow collections are not supported")
+        items.append(item)
+        if rest.startswith(","):
+            rest = rest[1:].strip()
+        elif rest:
+            raise YAMLError(f"line {pos + 1}: expected ',' in list")
+    return items
+
+
+def _parse_scalar(text: str, pos: int) -> Any:
+    text = _strip_comment(text.strip())
+    end = _quoted_end(text) if text[:1] in ('"', "'") else None
+    if end is not None and end < len(text):
+        raise YAMLError(f"line {pos + 1}: unexpected {text[end:].strip()!r} after a string")
+    if text[:1] == '"':
+        try:
+            return json.loads(text)
+        except ValueError:
+            raise YAMLError(f"line {pos + 1}: unsupported double-quoted string")
+    if text[:1] == "'":
+        if len(text) < 2 or not text.endswith("'"):
+            raise YAMLError(f"line {pos + 1}: unterminated single-quoted string")
+        return text[1:-1].replace("''", "'")
+    if text in ("", "~", "null", "Null", "NULL"):
+        return None
+    if text in ("true", "True", "TRUE"):
+        return True
+    if text in ("false", "False", "FALSE"):
+        return False
+    if text.startswith("[") and text.endswith("]"):
+        return [_parse_scalar(item, pos) for item in _flow_items(text[1:-1], pos)]
+    if text == "{}":
+        return {}
+    if text[:1] in ("[", "{", "&", "*", "!", "|", ">", "@", "`", "%"):
+        raise YAMLError(f"line {pos + 1}: unsupported YAML syntax {text[:20]!r}")
+    if ": " in text or text.endswith(":"):
+        raise YAMLError(f"line {pos + 1}: a plain value cannot contain ': ' {text[:20]!r}")
+    if _INT.match(text):
+        return int(text)
+    if _FLOAT.match(text):
+        return float(text)
+    return text
@@ -0,0 +1,192 @@
+# src/crowsight/scanner/build.py
+
+from dataclasses import dataclass, field
+from pathlib import Path
+import platform
+import re
+from typing import Callable, List, Optional
+
+from .fs import OS_FS, FileSystem
+
+# From go/build/syslist.go.
+KNOWN_OS = {
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios",
+    "js", "linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1",
+    "windows", "zos",
+}
+UNIX_OS = {
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios",
+    "linux", "netbsd", "openbsd", "solaris",
+}
+KNOWN_ARCH = {
+    "386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64",
+    "mips", "mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc",
+    "ppc64", "ppc64le", "riscv", "riscv64", "s390", "s390x", "sparc", "sparc64",
+    "wasm",
+}
+
+_MACHINE_ARCH = {
+    "x86_64": "amd64",
+    "amd64": "amd64",
+    "aarch64": "arm64",
+    "arm64": "arm64",
+    "i386": "386",
+    "i686": "386",
+    "x86": "386",
+    "armv7l": "arm",
+    "armv6l": "arm",
+    "ppc64le": "ppc64le",
+    "s390x": "s390x",
+    "riscv64": "riscv64",
+}
+
+_GO_VERSION = re.compile(r"go1\.\d+")
+_TOKEN = re.compile(r"\s*(\(|\)|!|&&|\|\||[\w.\-]+)")
+
+
+def host_goos() -> str:
+    name = platform.system().lower()
+    return name if name in KNOWN_OS else "linux"
+
+
+def host_goarch() -> str:
+    return _MACHINE_ARCH.get(platform.machine().lower(), "amd64")
+
+
+def parse_expr(expr: str) -> Callable[[Callable[[str], bool]], bool]:
+    """Compile a `//go:build` expression into a predicate over tag membership."""
+    tokens = [m.group(1) for m in _TOKEN.finditer(expr)]
+    pos = 0
+
+    def peek() -> Optional[str]:
+        return tokens[pos] if pos < len(tokens) else None
+
+    def take() -> str:
+        nonlocal pos
+        if pos >= len(tokens):
+            raise ValueError(f"unexpected end of build expression {expr!r}")
+        pos += 1
+        return tokens[pos - 1]
+
+    def or_expr():
+        terms = [and_expr()]
+        while peek() == "||":
+            take()
+            terms.append(and_expr())
+        return lambda has: any(t(has) for t in terms)
+
+    def and_expr():
+        terms = [unary()]
+        while peek() == "&&":
+            take()
+            terms.append(unary())
+        return lambda has: all(t(has) for t in terms)
+
+    def unary():
+        tok = take()
+        if tok == "!":
+            inner = unary()
+            return lambda has: not inner(has)
+        if tok == "(":
+            inner = or_expr()
+            if take() != ")":
+                raise ValueError(f"missing ')' in build expression {expr!r}")
+            return inner
+        if tok in (")", "&&", "||"):
+            raise ValueError(f"unexpected {tok!r} in build expression {expr!r}")
+        return lambda has: has(tok)
+
+    result = or_expr()
+    if peek() is not None:
+        raise ValueError(f"unexpected {peek()!r} in build expression {expr!r}")
+    return result
+
+
+def plus_build_expr(lines: List[str]) -> str:
+    """Translate legacy `// +build` lines (ANDed; space = OR, comma = AND)."""
+    clauses = []
+    for line in lines:
+        options = ["(" + " && ".join(opt.split(",")) + ")" for opt in line.split()]
+        clauses.append("(" + " || ".join(options) + ")")
+    return " && ".join(clauses)
+
+
+def header_constraint(source: str) -> Optional[str]:
+    """The build expression from a file's header comments, if any.
+
+    `//go:build` wins over `// +build`, which only counts when it sits in the
+    comment block before the package clause.
+    """
+    go_build: Optional[str] = None
+    plus: List[str] = []
+    in_block = False
+    for raw in source.splitlines():
+        line = raw.strip()
+        if in_block:
+            if "*/" in line:
+                in_block = False
+            continue
+        if not line:
+            continue
+        if line.startswith("/*"):
+            in_block = "*/" not in line
+            continue
+        if not line.startswith("//"):
+            break  # package clause (or anything else) ends the header
+        body = line[2:].strip()
+        if line.startswith("//go:build") and go_build is None:
+            go_build = line[len("//go:build"):].strip()
+        elif body.startswith("+build"):
+            plus.append(body[len("+build"):].strip())
+    if go_build is not None:
+        return go_build
+    return plus_build_expr(plus) if plus else None
+
+
+@dataclass
+class BuildContext:
+    """The GOOS/GOARCH/tags a scan pretends to build for, like go/build.Context."""
+
+    goos: str = field(default_factory=host_goos)
+    goarch: str = field(default_factory=host_goarch)
+    tags: List[str] = field(default_factory=list)
+    include_tests: bool = False
+
+    def has(self, tag: str) -> bool:
+        if tag in (self.goos, self.goarch, "gc") or tag in self.tags:
+            return True
+        if tag == "unix":
+            return self.goos in UNIX_OS
+        # Some ports also satisfy the tags of the OS they derive from.
+        if (self.goos, tag) in (("android", "linux"), ("illumos", "solaris"), ("ios", "darwin")):
+            return True
+        # Every go1.N release tag is satisfied; the scanned code targets a modern toolchain.
+        return bool(_GO_VERSION.fullmatch(tag))
+
+    def matches_name(self, name: str) -> bool:
+        """Apply go/build's filename rules (`_test.go`, `_GOOS`, `_GOARCH` suffixes)."""
+        if name.startswith(("_", ".")):
+            return False
+        stem = name[: -len(".go")] if name.endswith(".go") else name
+        if stem.endswith("_test"):
+            if not self.include_tests:
+                return False
+            stem = stem[: -len("_test")]
+        parts = stem.split("_")[1:]
+        if len(parts) >= 2 and parts[-2] in KNOWN_OS and parts[-1] in KNOWN_ARCH:
+            return self.has(parts[-2]) and self.has(parts[-1])
+        if parts and (parts[-1] in KNOWN_OS or parts[-1] in KNOWN_ARCH):
+            return self.has(parts[-1])
+        return True
+
+    def matches_source(self, source: str) -> bool:
+        expr = header_constraint(source)
+        return expr is None or parse_expr(expr)(self.has)
+
+    def matches(self, path: Path, fs: FileSystem = OS_FS) -> bool:
+        if not self.matches_name(path.name):
+            return False
+        # Constraints live in the header; no need to read the whole file.
+        with fs.open(path) as fh:
+            head = fh.read(8192).decode("utf-8", errors="replace")
+        return self.matches_source(head)
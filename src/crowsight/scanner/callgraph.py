@@ -0,0 +1,181 @@
+# src/crowsight/scanner/callgraph.py
+
+from collections import deque
+from typing import Dict, List, Optional, Set, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import CallEdge
+from .goast import unquote
+from .handlers import local_type, receiver_type
+from .package import GoFile, PackageInfo
+from .resolve import HandlerResolver
+from .structs import base_type
+from .types import TypeResolver
+
+# How many calls deep --callgraph follows from each handler by default.
+DEFAULT_CALLGRAPH_DEPTH = 3
+
+Decl = Tuple[PackageInfo, GoFile, NodeWrapper]
+
+
+def func_label(decl: NodeWrapper) -> str:
+    name = decl.field("name")
+    label = name.text if name is not None else "func literal"
+    recv = receiver_type(decl)
+    return f"{base_type(recv)}.{label}" if recv else label
+
+
+def is_stdlib(import_path: str) -> bool:
+    """Whether an import path is the standard library's: no dot in its first element."""
+    return "." not in import_path.split("/", 1)[0]
+
+
+def file_imports(f: GoFile) -> Dict[str, str]:
+    """Qualifier -> import path for the file's named imports."""
+    found: Dict[str, str] = {}
+    for spec in f.root.descendants():
+        if spec.type != "import_spec":
+            continue
+        alias, path = spec.field("name"), spec.field("path")
+        if path is None:
+            continue
+        import_path = unquote(path.text)
+        name = alias.text if alias is not None else import_path.rsplit("/", 1)[-1]
+        if name not in ("_", "."):
+            found[name] = import_path
+    return found
+
+
+def method_of(call: NodeWrapper) -> Optional[Tuple[NodeWrapper, str]]:
+    """(operand, method) of a `x.M(...)` call."""
+    fn = call.field("function")
+    if fn is None or fn.type != "selector_expression":
+        return None
+    operand, name = fn.field("operand"), fn.field("field")
+    if operand is None or name is None:
+        return None
+    return operand, name.text
+
+
+class CallFollower:
+    """Resolves the module function or method a call expression calls.
+
+    Package functions, `pkg.Func` calls into other packages of the scanned
+    modules, and methods on receivers whose type can be read from the code
+    are followed; an interface method resolves when its name is unique in
+    the package that declares the interface's type.
+    """
+
+    def __init__(self, pkg: PackageInfo, resolver: HandlerResolver, types: TypeResolver):
+        self.pkg = pkg
+        self.types = types
+        self._resolvers: Dict[str, HandlerResolver] = {pkg.dir: resolver}
+
+    def callee(
+        self, pkg: PackageInfo, f: GoFile, call: NodeWrapper, scope: NodeWrapper
+    ) -> Optional[Decl]:
+        fn = call.field("function")
+        if fn is None:
+            return None
+        resolver = self.resolver(pkg)
+        if fn.type == "identifier":
+            return self._foreign(pkg, resolver.funcs.get(fn.text))
+        parts = method_of(call)
+        if parts is None:
+            return None
+        operand, name = parts
+        if operand.type == "identifier" and local_type(scope, operand.text) is None:
+            target = self.types.imported(operand.text, f)
+            if target is not None:
+                found = self.resolver(target).funcs.get(name)
+                return self._foreign(target, found)
+        typ = resolver.type_of(operand, scope)
+        if typ:
+            qualifier, _, base = typ.rpartition(".")
+            target = self.types.imported(qualifier, f) if qualifier else pkg
+            if target is None:
+                return None
+            other = self.resolver(target)
+            found = other.methods.get((base, name))
+            if found is None:
+                # Interfaces (repositories, services): a method name unique in the package.
+                candidates = other.methods_by_name.get(name, [])
+                found = candidates[0] if len(candidates) == 1 else None
+            return self._foreign(target, found)
+        if operand.type == "identifier":
+            candidates = resolver.methods_by_name.get(name, [])
+            return self._foreign(pkg, candidates[0] if len(candidates) == 1 else None)
+        return None
+
+    def resolver(self, pkg: PackageInfo) -> HandlerResolver:
+        resolver = self._resolvers.get(pkg.dir)
+        if resolver is None:
+            resolver = self._resolvers[pkg.dir] = HandlerResolver(pkg)
+        return resolver
+
+    def _foreign(
+        self, target: PackageInfo, found: Optional[Tuple[GoFile, NodeWrapper]]
+    ) -> Optional[Decl]:
+        if found is None:
+            return None
+        if target.dir != self.pkg.dir:
+            # Results now depend on that package's files.
+            self.types.deps.add(target.dir)
+        return (target, *found)
+
+
+class CallGraphBuilder:
+    """The functions reachable from a handler within the scanned modules.
+
+    Calls are followed breadth-first up to `depth` levels; a function
+    reached twice (recursion included) is expanded once, and the edge back
+    to it is still recorded. Standard library calls are leaves.
+    """
+
+    def __init__(self, calls: CallFollower, depth: int):
+        self.calls = calls
+        self.depth = depth
+        self._imports: Dict[str, Dict[str, str]] = {}
+
+    def edges(self, f: GoFile, fn: NodeWrapper, root: str) -> List[CallEdge]:
+        found: Dict[Tuple[str, str], CallEdge] = {}
+        seen: Set[NodeWrapper] = {fn}
+        queue = deque([((self.calls.pkg, f, fn), root, 0)])
+        while queue:
+            (pkg, gf, decl), caller, level = queue.popleft()
+            for call in decl.descendants():
+                if call.type != "call_expression":
+                    continue
+                callee = self.calls.callee(pkg, gf, call, decl)
+                if callee is not None:
+                    label = f"{callee[0].name}.{func_label(callee[2])}"
+                    edge = CallEdge(caller, label, gf.rel, call.line)
+                    if callee[2] not in seen and level + 1 < self.depth:
+                        seen.add(callee[2])
+                        queue.append((callee, label, level + 1))
+                else:
+                    stdlib = self._stdlib(pkg, gf, call, decl)
+                    if stdlib is None:
+                        continue
+                    edge = CallEdge(caller, stdlib, gf.rel, call.line, stdlib=True)
+                found.setdefault((edge.caller, edge.callee), edge)
+        return list(found.values())
+
+    def _stdlib(
+        self, pkg: PackageInfo, f: GoFile, call: NodeWrapper, scope: NodeWrapper
+    ) -> Optional[str]:
+        """`http.Error`, or `http.ResponseWriter.Write` for a method on a standard type."""
+        parts = method_of(call)
+        if parts is None:
+            return None
+        operand, name = parts
+        imports = self._imports.get(f.rel)
+        if imports is None:
+            imports = self._imports[f.rel] = file_imports(f)
+        if operand.type == "identifier" and local_type(scope, operand.text) is None:
+            path = imports.get(operand.text)
+            return f"{operand.text}.{name}" if path is not None and is_stdlib(path) else None
+        typ = self.calls.resolver(pkg).type_of(operand, scope)
+        qualifier = typ.rpartition(".")[0] if typ else ""
+        path = imports.get(qualifier)
+        return f"{typ}.{name}" if path is not None and is_stdlib(path) else None
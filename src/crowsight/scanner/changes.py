@@ -0,0 +1,54 @@
+# src/crowsight/scanner/changes.py
+
+from pathlib import Path
+import os
+import subprocess
+from typing import List, Set
+
+from loguru import logger
+
+
+class GitError(ValueError):
+    """git is missing, the root is not a checkout, or the ref does not resolve."""
+
+
+def _git(cwd: Path, *args: str) -> str:
+    try:
+        proc = subprocess.run(
+            ["git", *args], cwd=cwd, capture_output=True, text=True, check=False
+        )
+    except OSError as e:
+        raise GitError(f"cannot run git: {e}")
+    if proc.returncode != 0:
+        detail = proc.stderr.strip().splitlines()
+        raise GitError(detail[-1] if detail else f"git {args[0]} failed")
+    return proc.stdout
+
+
+def changed_files(root: Path, ref: str) -> Set[str]:
+    """Files under `root` (relative to it) that differ from where HEAD forked off `ref`.
+
+    Like a pull request diff this compares against the merge base of `ref`
+    and HEAD, so `origin/main` only counts the branch's own changes; committed,
+    staged, unstaged and untracked (not ignored) files are all included.
+    """
+    cwd = (root if root.is_dir() else root.parent).resolve()
+    try:
+        top = Path(_git(cwd, "rev-parse", "--show-toplevel").strip())
+    except GitError:
+        raise GitError(f"{cwd} is not inside a git checkout; --since needs one")
+    try:
+        base = _git(cwd, "merge-base", ref, "HEAD").strip()
+    except GitError:
+        raise GitError(f"unknown git ref {ref!r}")
+    names: List[str] = _git(top, "diff", "--name-only", "-z", base).split("\0")
+    names += _git(top, "ls-files", "--others", "--exclude-standard", "-z").split("\0")
+    found: Set[str] = set()
+    for name in names:
+        if not name:
+            continue
+        rel = os.path.relpath(top / name, cwd)
+        if rel != ".." and not rel.startswith(".." + os.sep):
+            found.add(Path(rel).as_posix())
+    logger.info(f"{len(found)} files changed since {ref} ({base[:12]})")
+    return found
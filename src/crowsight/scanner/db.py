@@ -0,0 +1,196 @@
+# src/crowsight/scanner/db.py
+
+from typing import Dict, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import DBQuery
+from .callgraph import CallFollower, Decl, func_label, method_of
+from .goast import call_args, local_value, string_value
+from .package import GoFile, PackageInfo
+from .resolve import HandlerResolver
+from .types import TypeResolver
+
+# database/sql and sqlx methods -> index of their SQL argument.
+SQL_METHODS = {
+    "Query": 0,
+    "QueryContext": 1,
+    "QueryRow": 0,
+    "QueryRowContext": 1,
+    "Exec": 0,
+    "ExecContext": 1,
+    "Prepare": 0,
+    "PrepareContext": 1,
+    "Get": 1,
+    "GetContext": 2,
+    "Select": 1,
+    "SelectContext": 2,
+    "Queryx": 0,
+    "QueryxContext": 1,
+    "QueryRowx": 0,
+    "QueryRowxContext": 1,
+    "NamedExec": 0,
+    "NamedExecContext": 1,
+    "NamedQuery": 0,
+    "NamedQueryContext": 1,
+    "MustExec": 0,
+    "MustExecContext": 1,
+    # gorm's raw SQL, usually followed by .Scan(&out).
+    "Raw": 0,
+}
+# gorm methods that run a query, often at the end of a `db.Where(...)` chain.
+GORM_METHODS = (
+    "Find",
+    "First",
+    "Last",
+    "Take",
+    "Scan",
+    "Pluck",
+    "Count",
+    "Create",
+    "Save",
+    "Update",
+    "Updates",
+    "UpdateColumn",
+    "UpdateColumns",
+    "Delete",
+)
+DB_TYPES = {
+    "sql.DB",
+    "sql.Tx",
+    "sql.Conn",
+    "sql.Stmt",
+    "sqlx.DB",
+    "sqlx.Tx",
+    "sqlx.Conn",
+    "gorm.DB",
+    "pgx.Conn",
+    "pgx.Tx",
+    "pgxpool.Pool",
+}
+# Receivers of unknown type that are taken to be a database handle.
+DB_NAMES = ("db", "tx", "conn", "pool", "database", "orm", "gorm", "sqlx")
+DB_SUFFIXES = ("DB", "Db", "Tx")
+
+# How many calls deep to follow from the handler into repository/service code.
+MAX_DEPTH = 2
+
+
+def is_query_method(name: str) -> bool:
+    return name in SQL_METHODS or name in GORM_METHODS
+
+
+def chain_root(call: NodeWrapper) -> NodeWrapper:
+    """`s.db` in `s.db.Where(...).Order(...).Find(&xs)`."""
+    node = call
+    while node.type == "call_expression":
+        parts = method_of(node)
+        if parts is None:
+            break
+        node = parts[0]
+    return node
+
+
+def in_loop(node: NodeWrapper, fn: NodeWrapper) -> bool:
+    """Whether `node` sits inside a `for` loop of `fn`."""
+    p = node.parent
+    while p is not None and p is not fn:
+        if p.type == "for_statement":
+            return True
+        p = p.parent
+    return False
+
+
+class QueryFinder:
+    """Finds the database calls an endpoint makes, following its call graph.
+
+    database/sql, sqlx and gorm calls are recognised by method name on a
+    receiver typed (or, failing that, named) like a database handle. Calls
+    into functions and methods of the module are followed up to MAX_DEPTH
+    levels, across packages when the callee's package can be loaded.
+    """
+
+    def __init__(self, pkg: PackageInfo, resolver: HandlerResolver, types: TypeResolver):
+        self.pkg = pkg
+        self.calls = CallFollower(pkg, resolver, types)
+
+    def queries(self, f: GoFile, fn: NodeWrapper) -> List[DBQuery]:
+        found: Dict[Tuple[str, int, int], DBQuery] = {}
+        self._walk((self.pkg, f, fn), [], False, found, [fn])
+        return sorted(found.values(), key=lambda q: (q.file, q.line))
+
+    def _walk(
+        self,
+        decl: Decl,
+        via: List[str],
+        looped: bool,
+        found: Dict[Tuple[str, int, int], DBQuery],
+        stack: List[NodeWrapper],
+    ):
+        pkg, f, fn = decl
+        for call in fn.descendants():
+            if call.type != "call_expression":
+                continue
+            loop = looped or in_loop(call, fn)
+            query = self._query(pkg, f, call, fn)
+            if query is not None:
+                key = (f.rel, call.line, call.column)
+                query.via, query.in_loop = list(via), loop
+                if key not in found or (loop and not found[key].in_loop):
+                    found[key] = query
+                continue
+            if len(via) >= MAX_DEPTH:
+                continue
+            callee = self.calls.callee(pkg, f, call, fn)
+            if callee is not None and callee[2] not in stack:
+                label = func_label(callee[2])
+                self._walk(callee, via + [label], loop, found, stack + [callee[2]])
+
+    def _query(
+        self, pkg: PackageInfo, f: GoFile, call: NodeWrapper, scope: NodeWrapper
+    ) -> Optional[DBQuery]:
+        parts = method_of(call)
+        if parts is None or not is_query_method(parts[1]):
+            return None
+        # In `db.Raw(q).Scan(&x)` the outermost call stands for the whole chain.
+        sel = call.parent
+        outer = sel.parent if sel is not None and sel.type == "selector_expression" else None
+        if outer is not None and outer.type == "call_expression":
+            outer_parts = method_of(outer)
+            if outer_parts is not None and is_query_method(outer_parts[1]):
+                return None
+        if not self._is_db(pkg, chain_root(call), scope):
+            return None
+        method, sql = self._sql(pkg, call, scope)
+        return DBQuery(call=method or parts[1], query=sql, file=f.rel, line=call.line)
+
+    def _sql(
+        self, pkg: PackageInfo, call: NodeWrapper, scope: NodeWrapper
+    ) -> Tuple[Optional[str], Optional[str]]:
+        """The chain's SQL-taking method (`QueryRow` in `db.QueryRow(q).Scan(...)`) and its SQL."""
+        node = call
+        while node.type == "call_expression":
+            parts = method_of(node)
+            if parts is None:
+                break
+            idx = SQL_METHODS.get(parts[1])
+            args = call_args(node)
+            if idx is not None:
+                arg = args[idx] if idx < len(args) else None
+                value = string_value(arg, pkg.consts)
+                if value is None and arg is not None and arg.type == "identifier":
+                    value = string_value(local_value(scope, arg.text), pkg.consts)
+                return parts[1], " ".join(value.split()) if value is not None else None
+            node = parts[0]
+        return None, None
+
+    def _is_db(self, pkg: PackageInfo, node: NodeWrapper, scope: NodeWrapper) -> bool:
+        typ = self.calls.resolver(pkg).type_of(node, scope)
+        if typ:
+            return typ in DB_TYPES
+        if node.type == "identifier":
+            name = node.text
+        elif node.type == "selector_expression" and node.field("field") is not None:
+            name = node.field("field").text
+        else:
+            return False
+        return name.lower() in DB_NAMES or name.endswith(DB_SUFFIXES)
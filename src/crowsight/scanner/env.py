@@ -0,0 +1,62 @@
+# src/crowsight/scanner/env.py
+
+import re
+from typing import Dict, List, Optional, Tuple
+
+from loguru import logger
+
+from ..core.node import NodeWrapper
+from ..report.models import EnvUsage, EnvVar
+from .goast import (
+    call_args,
+    call_target,
+    enclosing_function,
+    iter_calls,
+    local_value,
+    string_value,
+)
+from .package import PackageInfo
+
+# Functions whose first argument names a single variable.
+LOOKUP_CALLS = {
+    ("os", "Getenv"),
+    ("os", "LookupEnv"),
+    ("syscall", "Getenv"),
+}
+# os.ExpandEnv("$HOST:${PORT}") reads each variable referenced in the template.
+EXPAND_CALLS = {("os", "ExpandEnv")}
+_EXPAND_REF = re.compile(r"\$(?:\{(\w+)\}|([A-Za-z_]\w*))")
+
+
+class EnvDetector:
+    """Collects environment variables a package reads, deduplicated by name."""
+
+    def detect(self, pkg: PackageInfo) -> List[EnvVar]:
+        found: Dict[Tuple[str, bool], EnvVar] = {}
+        for f in pkg.files:
+            for call in iter_calls(f.root):
+                target = call_target(call)
+                if target not in LOOKUP_CALLS | EXPAND_CALLS:
+                    continue
+                usage = EnvUsage(file=f.rel, line=call.line, call=".".join(target))
+                for name, resolved in dict.fromkeys(self._names(target, call, pkg)):
+                    var = found.setdefault((name, resolved), EnvVar(name=name, resolved=resolved))
+                    var.usages.append(usage)
+        logger.debug(f"Detected {len(found)} environment variables in package {pkg.name}")
+        return list(found.values())
+
+    def _names(
+        self, target: Tuple[str, str], call: NodeWrapper, pkg: PackageInfo
+    ) -> List[Tuple[str, bool]]:
+        args = call_args(call)
+        if not args:
+            return []
+        value: Optional[str] = string_value(args[0], pkg.consts)
+        if value is None and args[0].type == "identifier":
+            scope = enclosing_function(call) or call
+            value = string_value(local_value(scope, args[0].text), pkg.consts)
+        if value is None:
+            return [(args[0].text, False)]
+        if target in EXPAND_CALLS:
+            return [(m.group(1) or m.group(2), True) for m in _EXPAND_REF.finditer(value)]
+        return [(value, True)]
@@ -0,0 +1,147 @@
+# src/crowsight/scanner/fs.py
+
+"""The filesystem a scan reads from.
+
+GoScanner reads every file, directory listing and go.mod through a
+FileSystem, so a scan can run over sources that only exist in memory
+(generated code, test fixtures) as well as over the OS filesystem.
+"""
+
+import io
+import os
+from pathlib import Path, PurePosixPath
+from typing import BinaryIO, Dict, Iterator, List, Mapping, Set, Tuple, Union
+
+# An os.walk-style entry: (directory, subdirectory names, file names).
+WalkEntry = Tuple[str, List[str], List[str]]
+
+
+class FileSystem:
+    """Read-only file access; paths are the Paths the scanner builds from `path`."""
+
+    def path(self, root: str) -> Path:
+        """Where a scan root given by the caller lives in this filesystem."""
+        return Path(root)
+
+    def is_file(self, path: Path) -> bool:
+        raise NotImplementedError
+
+    def is_dir(self, path: Path) -> bool:
+        raise NotImplementedError
+
+    def open(self, path: Path) -> BinaryIO:
+        raise NotImplementedError
+
+    def listdir(self, path: Path) -> List[str]:
+        raise NotImplementedError
+
+    def walk(self, top: Path) -> Iterator[WalkEntry]:
+        """Top-down like os.walk; pruning the yielded dir list skips those dirs."""
+        raise NotImplementedError
+
+    def samefile(self, a: Path, b: Path) -> bool:
+        raise NotImplementedError
+
+    def read_bytes(self, path: Path) -> bytes:
+        with self.open(path) as fh:
+            return fh.read()
+
+    def read_text(self, path: Path) -> str:
+        return self.read_bytes(path).decode("utf-8", errors="replace")
+
+
+class OSFileSystem(FileSystem):
+    def is_file(self, path: Path) -> bool:
+        return Path(path).is_file()
+
+    def is_dir(self, path: Path) -> bool:
+        return Path(path).is_dir()
+
+    def open(self, path: Path) -> BinaryIO:
+        return open(path, "rb")
+
+    def listdir(self, path: Path) -> List[str]:
+        return sorted(os.listdir(path))
+
+    def walk(self, top: Path) -> Iterator[WalkEntry]:
+        return os.walk(top)
+
+    def samefile(self, a: Path, b: Path) -> bool:
+        return os.path.samefile(a, b)
+
+
+OS_FS = OSFileSystem()
+
+
+class MemoryFileSystem(FileSystem):
+    """Files held in a mapping of slash-separated paths to text or bytes.
+
+    The tree is mounted at MOUNT, so `{"go.mod": ..., "cmd/api/main.go": ...}`
+    is scanned as `/go.mod` and `/cmd/api/main.go`.
+    """
+
+    MOUNT = Path("/")
+
+    def path(self, root: str) -> Path:
+        return self.MOUNT / self._key(root)
+
+    def __init__(self, files: Mapping[str, Union[str, bytes]]):
+        self.files: Dict[str, bytes] = {}
+        self.dirs: Set[str] = {""}
+        for name, content in files.items():
+            key = self._key(name)
+            if not key:
+                raise ValueError(f"invalid file path {name!r}")
+            self.files[key] = content.encode() if isinstance(content, str) else bytes(content)
+            parent = str(PurePosixPath(key).parent)
+            while parent not in ("", "."):
+                self.dirs.add(parent)
+                parent = str(PurePosixPath(parent).parent)
+
+    @staticmethod
+    def _key(path: Union[str, Path]) -> str:
+        key = os.path.normpath(str(path)).replace(os.sep, "/").lstrip("/")
+        return "" if key == "." else key
+
+    def is_file(self, path: Path) -> bool:
+        return self._key(path) in self.files
+
+    def is_dir(self, path: Path) -> bool:
+        return self._key(path) in self.dirs
+
+    def open(self, path: Path) -> BinaryIO:
+        key = self._key(path)
+        if key not in self.files:
+            raise FileNotFoundError(f"no such file: {path}")
+        return io.BytesIO(self.files[key])
+
+    def listdir(self, path: Path) -> List[str]:
+        key = self._key(path)
+        if key not in self.dirs:
+            raise FileNotFoundError(f"no such directory: {path}")
+        dirs, files = self._children(key)
+        return sorted(dirs + files)
+
+    def walk(self, top: Path) -> Iterator[WalkEntry]:
+        key = self._key(top)
+        if key not in self.dirs:
+            return
+        dirs, files = self._children(key)
+        yield str(self.MOUNT / key), dirs, files
+        for d in list(dirs):
+            yield from self.walk(self.MOUNT / key / d)
+
+    def samefile(self, a: Path, b: Path) -> bool:
+        return self._key(a) == self._key(b)
+
+    def _children(self, key: str) -> Tuple[List[str], List[str]]:
+        prefix = f"{key}/" if key else ""
+
+        def direct(names) -> List[str]:
+            return sorted(
+                n[len(prefix) :]
+                for n in names
+                if n.startswith(prefix) and n != key and "/" not in n[len(prefix) :]
+            )
+
+        return direct(self.dirs), direct(self.files)
@@ -0,0 +1,178 @@
+# src/crowsight/scanner/goast.py
+
+import json
+from typing import Dict, Iterator, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+
+STRING_TYPES = ("interpreted_string_literal", "raw_string_literal")
+FUNC_TYPES = ("function_declaration", "method_declaration", "func_literal")
+
+
+def unquote(text: str) -> str:
+    """Strip Go string delimiters and decode escapes in interpreted strings."""
+    if text.startswith("`") and text.endswith("`"):
+        return text[1:-1]
+    try:
+        return json.loads(text)
+    except ValueError:
+        return text[1:-1]
+
+
+def string_value(
+    node: Optional[NodeWrapper], consts: Optional[Dict[str, str]] = None
+) -> Optional[str]:
+    """Return the value of a string literal, or of an identifier naming a const."""
+    if node is None:
+        return None
+    if node.type in STRING_TYPES:
+        return unquote(node.text)
+    if node.type == "identifier" and consts:
+        return consts.get(node.text)
+    if node.type == "parenthesized_expression":
+        inner = node.named_children
+        return string_value(inner[0], consts) if inner else None
+    return None
+
+
+def iter_calls(root: NodeWrapper) -> Iterator[NodeWrapper]:
+    for node in root.descendants():
+        if node.type == "call_expression":
+            yield node
+
+
+def call_target(call: NodeWrapper) -> Tuple[Optional[str], str]:
+    """Split a call into (receiver text, function name): `r.Get` → ("r", "Get")."""
+    fn = call.field("function")
+    if fn is None:
+        return None, ""
+    if fn.type == "selector_expression":
+        operand = fn.field("operand")
+        name = fn.field("field")
+        return (operand.text if operand else None), (name.text if name else "")
+    return None, fn.text
+
+
+def call_args(call: NodeWrapper) -> List[NodeWrapper]:
+    args = call.field("arguments")
+    if args is None:
+        return []
+    return [a for a in args.named_children if a.type != "comment"]
+
+
+def enclosing_function(node: NodeWrapper) -> Optional[NodeWrapper]:
+    p = node.parent
+    while p is not None and p.type not in FUNC_TYPES:
+        p = p.parent
+    return p
+
+
+def unwrap_element(node: NodeWrapper) -> NodeWrapper:
+    """Look through the `literal_element` wrapper newer grammars put around values."""
+    if node.type == "literal_element" and node.named_children:
+        return node.named_children[0]
+    return node
+
+
+def literal_fields(lit: NodeWrapper) -> Dict[str, NodeWrapper]:
+    """Keyed values of a composite literal: `T{A: 1}` → {"A": <1>}."""
+    body = lit.field("body")
+    fields: Dict[str, NodeWrapper] = {}
+    if body is None:
+        return fields
+    for el in body.named_children:
+        if el.type != "keyed_element":
+            continue
+        parts = [unwrap_element(c) for c in el.named_children if c.type != "comment"]
+        if len(parts) == 2:
+            fields[parts[0].text] = parts[1]
+    return fields
+
+
+def local_value(scope: NodeWrapper, name: str) -> Optional[NodeWrapper]:
+    """The expression first assigned to `name` by `:=`, `=` or `var` within `scope`."""
+    for node in scope.descendants():
+        if node.type == "var_spec":
+            names = [n.text for n in node.fields("name")]
+            value = node.field("value")
+            if name in names and value is not None:
+                values = value.named_children
+                if len(values) == len(names):
+                    return values[names.index(name)]
+        elif node.type in ("short_var_declaration", "assignment_statement"):
+            left = node.field("left")
+            right = node.field("right")
+            if left is None or right is None:
+                continue
+            names = [n.text for n in left.named_children]
+            values = right.named_children
+            if name in names and len(values) == len(names):
+                return values[names.index(name)]
+            if name in names and len(values) == 1:
+                # `req, err := http.NewRequest(...)`: the call produces every name.
+                return values[0]
+    return None
+
+
+def strip_address_of(node: NodeWrapper) -> NodeWrapper:
+    """`&T{...}` and `(expr)` → the inner expression."""
+    while True:
+        if node.type == "unary_expression" and node.field("operand") is not None:
+            node = node.field("operand")
+        elif node.type == "parenthesized_expression" and node.named_children:
+            node = node.named_children[0]
+        else:
+            return node
+
+
+def statements(block: Optional[NodeWrapper]) -> List[NodeWrapper]:
+    """Direct statements of a block or case clause (flattening `statement_list`)."""
+    if block is None:
+        return []
+    out: List[NodeWrapper] = []
+    for child in block.named_children:
+        if child.type == "statement_list":
+            out.extend(c for c in child.named_children if is_statement(c))
+        elif is_statement(child):
+            out.append(child)
+    return out
+
+
+def is_statement(node: NodeWrapper) -> bool:
+    # Case clauses mix their value/type expressions in with the statements.
+    return node.type == "block" or node.type.endswith(("_statement", "_declaration"))
+
+
+def walk_body(node: NodeWrapper) -> Iterator[NodeWrapper]:
+    """Like `descendants`, but does not enter nested function literals."""
+    yield node
+    for child in node.children:
+        if child.type == "func_literal":
+            continue
+        yield from walk_body(child)
+
+
+# gocyclo counts +1 for each if, for/range, non-default case/comm clause, && and ||.
+BRANCH_TYPES = (
+    "if_statement",
+    "for_statement",
+    "expression_case",
+    "type_case",
+    "communication_case",
+)
+
+
+def cyclomatic(fn: NodeWrapper) -> int:
+    """Cyclomatic complexity using gocyclo's counting rules (nested literals included)."""
+    body = fn.field("body")
+    if body is None:
+        return 1
+    score = 1
+    for node in body.descendants():
+        if node.type in BRANCH_TYPES:
+            score += 1
+        elif node.type == "binary_expression":
+            op = node.field("operator")
+            if op is not None and op.text in ("&&", "||"):
+                score += 1
+    return score
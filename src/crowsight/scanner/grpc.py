@@ -0,0 +1,133 @@
+# src/crowsight/scanner/grpc.py
+
+import re
+from typing import Dict, List, Optional, Tuple
+
+from loguru import logger
+
+from ..core.node import NodeWrapper
+from ..report.models import Endpoint
+from .goast import call_args, call_target, enclosing_function, iter_calls
+from .handlers import expr_type, func_params, receiver_type
+from .package import PackageInfo
+from .routes import Route
+from .structs import StructCollector, base_type
+
+# Generated `RegisterGreeterServer(s, impl)` and `UnimplementedGreeterServer`.
+_REGISTER = re.compile(r"^Register(\w+)Server$")
+_UNIMPLEMENTED = re.compile(r"^(?:\w+\.)?Unimplemented(\w+)Server$")
+
+GRPC_METHOD = "GRPC"
+
+
+def result_types(fn: NodeWrapper) -> List[str]:
+    result = fn.field("result")
+    if result is None:
+        return []
+    if result.type != "parameter_list":
+        return [result.text]
+    types: List[str] = []
+    for decl in result.named_children:
+        typ = decl.field("type")
+        if typ is not None:
+            types.extend([typ.text] * max(1, len(decl.fields("name"))))
+    return types
+
+
+def rpc_messages(
+    service: str, method: str, fn: NodeWrapper
+) -> Optional[Tuple[Optional[str], Optional[str]]]:
+    """(request, response) message types if `fn` has a generated RPC signature.
+
+    Unary: `(ctx, *Req) (*Resp, error)`; server streaming: `(*Req, Svc_MServer) error`;
+    client/bidi streaming: `(Svc_MServer) error`, whose messages live on the stream.
+    """
+    params = [t for _, t in func_params(fn)]
+    results = result_types(fn)
+    stream = f"{service}_{method}Server"
+    if len(params) == 2 and params[0] == "context.Context" and len(results) == 2:
+        if results[1] == "error" and params[1].startswith("*") and results[0].startswith("*"):
+            return params[1].lstrip("*"), results[0].lstrip("*")
+    if results == ["error"] and params and params[-1].rsplit(".", 1)[-1] == stream:
+        if len(params) == 2 and params[0].startswith("*"):
+            return params[0].lstrip("*"), None
+        if len(params) == 1:
+            return None, None
+    return None
+
+
+class GrpcDetector:
+    """Finds gRPC service implementations and turns each RPC into an Endpoint.
+
+    An implementation is a type passed to a generated `RegisterXxxServer` call
+    (usually alongside `grpc.NewServer()`), or one embedding
+    `UnimplementedXxxServer`; its methods with RPC signatures are the RPCs.
+    """
+
+    def detect(self, pkg: PackageInfo) -> List[Route]:
+        impls = self._implementations(pkg)
+        if not impls:
+            return []
+        routes: List[Route] = []
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                if decl.type != "method_declaration":
+                    continue
+                recv, name = receiver_type(decl), decl.field("name")
+                found = impls.get(base_type(recv)) if recv else None
+                if found is None or name is None or not name.text[:1].isupper():
+                    continue
+                service, site_file, site_line = found
+                messages = rpc_messages(service, name.text, decl)
+                if messages is None:
+                    continue
+                ep = Endpoint(
+                    method=GRPC_METHOD,
+                    path=f"/{service}/{name.text}",
+                    handler=f"{base_type(recv)}.{name.text}",
+                    file=site_file,
+                    line=site_line,
+                    request=messages[0],
+                    response=messages[1],
+                    protocol="grpc",
+                    rpc_service=service,
+                    rpc_method=name.text,
+                )
+                routes.append(Route(endpoint=ep, handler=decl, call=decl, file=f))
+        logger.debug(f"Detected {len(routes)} gRPC methods in package {pkg.name}")
+        return routes
+
+    def _implementations(self, pkg: PackageInfo) -> Dict[str, Tuple[str, str, int]]:
+        """impl type -> (gRPC service name, file, line of its registration or type)."""
+        impls: Dict[str, Tuple[str, str, int]] = {}
+        for f in pkg.files:
+            for call in iter_calls(f.root):
+                _, name = call_target(call)
+                m = _REGISTER.match(name or "")
+                args = call_args(call)
+                if m is None or len(args) != 2:
+                    continue
+                typ = self._impl_type(args[1], call, pkg)
+                if typ:
+                    impls[typ] = (m.group(1), f.rel, call.line)
+        for st in StructCollector().collect(pkg).values():
+            for fld in st.fields:
+                m = _UNIMPLEMENTED.match(fld.type.lstrip("*")) if fld.embedded else None
+                if m and st.name not in impls:
+                    impls[st.name] = (m.group(1), st.file, st.line)
+        return impls
+
+    @staticmethod
+    def _impl_type(node: NodeWrapper, call: NodeWrapper, pkg: PackageInfo) -> Optional[str]:
+        scope = enclosing_function(call) or call
+        typ = expr_type(node, scope)
+        if typ is None and node.type == "call_expression":
+            # `newServer(...)` constructor declared in this package.
+            _, ctor = call_target(node)
+            for f in pkg.files:
+                for decl in f.root.named_children:
+                    name = decl.field("name")
+                    if decl.type == "function_declaration" and name and name.text == ctor:
+                        results = result_types(decl)
+                        typ = results[0] if results else None
+        return base_type(typ) if typ else None
@@ -0,0 +1,198 @@
+# src/crowsight/scanner/handlers.py
+
+from typing import Iterator, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Handler
+from .goast import call_args, call_target, cyclomatic, iter_calls
+from .package import GoFile, PackageInfo
+
+# Parameter types that mark a function as an HTTP handler.
+NET_HTTP_PARAMS = {"http.ResponseWriter", "*http.Request"}
+FRAMEWORK_CONTEXTS = {"*gin.Context", "echo.Context", "*fiber.Ctx"}
+
+# Calls that decode a request body into their (index)th argument.
+DECODE_METHODS = {"Decode": 0, "BindJSON": 0, "ShouldBindJSON": 0, "Bind": 0, "ShouldBind": 0}
+# Calls that encode a response from their (index)th argument.
+ENCODE_METHODS = {"Encode": 0, "JSON": 1}
+
+
+def func_params(fn: NodeWrapper) -> List[Tuple[str, str]]:
+    """(name, type) pairs of a function's parameters; unnamed params get ''."""
+    params = fn.field("parameters")
+    out: List[Tuple[str, str]] = []
+    if params is None:
+        return out
+    for decl in params.named_children:
+        if decl.type not in ("parameter_declaration", "variadic_parameter_declaration"):
+            continue
+        typ = decl.field("type")
+        t = typ.text if typ else ""
+        names = decl.fields("name")
+        if not names:
+            out.append(("", t))
+        out.extend((n.text, t) for n in names)
+    return out
+
+
+def is_handler(fn: NodeWrapper) -> bool:
+    types = {t for _, t in func_params(fn)}
+    return NET_HTTP_PARAMS <= types or bool(types & FRAMEWORK_CONTEXTS)
+
+
+def receiver_type(fn: NodeWrapper) -> Optional[str]:
+    if fn.type != "method_declaration":
+        return None
+    recv = fn.field("receiver")
+    for decl in recv.named_children if recv else []:
+        typ = decl.field("type")
+        if typ is not None:
+            return typ.text.lstrip("*")
+    return None
+
+
+def iter_handlers(
+    pkg: PackageInfo, include_literals: bool = False
+) -> Iterator[Tuple[GoFile, NodeWrapper]]:
+    """Top-level functions and methods with a handler signature.
+
+    With `include_literals`, inline `func(w, r) {...}` handlers are yielded too.
+    """
+    for f in pkg.files:
+        for decl in f.root.named_children:
+            if decl.type in ("function_declaration", "method_declaration") and is_handler(decl):
+                yield f, decl
+        if include_literals:
+            for node in f.root.descendants():
+                if node.type == "func_literal" and is_handler(node):
+                    yield f, node
+
+
+def handler_params(fn: NodeWrapper) -> Tuple[Optional[str], Optional[str]]:
+    """Names bound to the ResponseWriter and *Request (or framework context)."""
+    writer = request = None
+    for name, typ in func_params(fn):
+        if typ == "http.ResponseWriter":
+            writer = name or None
+        elif typ == "*http.Request":
+            request = name or None
+        elif typ in FRAMEWORK_CONTEXTS:
+            writer = request = name or None
+    return writer, request
+
+
+def local_type(scope: NodeWrapper, name: str, depth: int = 0) -> Optional[str]:
+    """Best-effort static type of a local variable or parameter inside `scope`."""
+    if depth > 3:
+        return None
+    for node in scope.descendants():
+        if node.type in ("var_spec", "parameter_declaration"):
+            if name not in (n.text for n in node.fields("name")):
+                continue
+            typ = node.field("type")
+            if typ is not None:
+                return typ.text
+            value = node.field("value")
+            if node.type == "var_spec" and value is not None:
+                idx = [n.text for n in node.fields("name")].index(name)
+                values = value.named_children
+                if idx < len(values):
+                    return expr_type(values[idx], scope, depth + 1)
+        elif node.type == "short_var_declaration":
+            left = node.field("left")
+            right = node.field("right")
+            if left is None or right is None:
+                continue
+            names = [n.text for n in left.named_children]
+            if name not in names:
+                continue
+            values = right.named_children
+            idx = names.index(name)
+            if idx < len(values) and len(values) == len(names):
+                return expr_type(values[idx], scope, depth + 1)
+    return None
+
+
+def expr_type(node: NodeWrapper, scope: NodeWrapper, depth: int = 0) -> Optional[str]:
+    """Type of `T{}`, `&T{}`, `new(T)` or a local variable holding one."""
+    if node.type == "unary_expression":
+        operand = node.field("operand")
+        return expr_type(operand, scope, depth) if operand else None
+    if node.type == "composite_literal":
+        typ = node.field("type")
+        return typ.text if typ else None
+    if node.type == "call_expression":
+        fn = node.field("function")
+        args = call_args(node)
+        if fn is not None and fn.text == "new" and args:
+            return args[0].text
+        return None
+    if node.type == "identifier":
+        return local_type(scope, node.text, depth)
+    return None
+
+
+def is_json_codec(call: NodeWrapper, ctor: str) -> bool:
+    """Is `call` a method on a `json.NewDecoder(...)`/`json.NewEncoder(...)` value?"""
+    fn = call.field("function")
+    operand = fn.field("operand") if fn is not None else None
+    if operand is None:
+        return False
+    if operand.type == "call_expression":
+        inner = operand.field("function")
+        return inner is not None and inner.text.endswith(ctor)
+    return operand.type == "identifier"
+
+
+def find_dtos(body: NodeWrapper, scope: NodeWrapper) -> Tuple[Optional[str], Optional[str]]:
+    """Request/response types used by json decode/encode calls inside `body`."""
+    request = response = None
+    for call in iter_calls(body):
+        receiver, name = call_target(call)
+        args = call_args(call)
+        target = None
+        if receiver == "json" and name == "Unmarshal" and len(args) == 2:
+            target, slot = args[1], "request"
+        elif receiver == "json" and name == "Marshal" and len(args) == 1:
+            target, slot = args[0], "response"
+        elif name in DECODE_METHODS and receiver and len(args) > DECODE_METHODS[name]:
+            if name == "Decode" and not is_json_codec(call, "NewDecoder"):
+                continue
+            target, slot = args[DECODE_METHODS[name]], "request"
+        elif name in ENCODE_METHODS and receiver and len(args) > ENCODE_METHODS[name]:
+            if name == "Encode" and not is_json_codec(call, "NewEncoder"):
+                continue
+            target, slot = args[ENCODE_METHODS[name]], "response"
+        if target is None:
+            continue
+        typ = expr_type(target, scope)
+        if typ is None:
+            continue
+        typ = typ.lstrip("*")
+        if slot == "request" and request is None:
+            request = typ
+        elif slot == "response" and response is None:
+            response = typ
+    return request, response
+
+
+class HandlerDetector:
+    """Finds handler functions and the DTOs they decode and encode."""
+
+    def detect(self, pkg: PackageInfo) -> List[Handler]:
+        handlers: List[Handler] = []
+        for f, fn in iter_handlers(pkg):
+            body = fn.field("body")
+            request, response = find_dtos(body, fn) if body else (None, None)
+            handlers.append(
+                Handler(
+                    name=fn.field("name").text,
+                    receiver=receiver_type(fn),
+                    file=f.rel,
+                    line=fn.line,
+                    request=request,
+                    response=response,
+                    complexity=cyclomatic(fn),
+                )
+            )
+        return handlers
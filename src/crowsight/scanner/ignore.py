@@ -0,0 +1,110 @@
+# src/crowsight/scanner/ignore.py
+
+from dataclasses import dataclass
+from pathlib import Path
+import re
+from typing import Iterable, List, Pattern
+
+from loguru import logger
+
+from .fs import OS_FS, FileSystem
+
+IGNORE_FILE = ".crowsightignore"
+
+
+@dataclass
+class IgnoreRule:
+    regex: Pattern
+    negate: bool
+    dir_only: bool
+
+
+def _translate(pat: str) -> str:
+    """Turn a gitignore glob (without anchoring) into a regex fragment."""
+    out, i, n = [], 0, len(pat)
+    while i < n:
+        c = pat[i]
+        if c == "*":
+            if pat.startswith("**/", i):
+                out.append("(?:.*/)?")
+                i += 3
+                continue
+            if pat.startswith("**", i):
+                out.append(".*")
+                i += 2
+                continue
+            out.append("[^/]*")
+        elif c == "?":
+            out.append("[^/]")
+        elif c == "[":
+            j = pat.find("]", i + 1)
+            if j == -1:
+                out.append(re.escape(c))
+            else:
+                body = pat[i + 1 : j]
+                if body.startswith("!"):
+                    body = "^" + body[1:]
+                out.append(f"[{body}]")
+                i = j
+        elif c == "\\" and i + 1 < n:
+            i += 1
+            out.append(re.escape(pat[i]))
+        else:
+            out.append(re.escape(c))
+        i += 1
+    return "".join(out)
+
+
+class IgnoreRules:
+    """gitignore-style path matcher: last matching rule wins, `!` re-includes."""
+
+    def __init__(self, patterns: Iterable[str] = ()):
+        self.rules: List[IgnoreRule] = []
+        for p in patterns:
+            self.add(p)
+
+    @classmethod
+    def for_root(
+        cls, root: Path, extra: Iterable[str] = (), fs: FileSystem = OS_FS
+    ) -> "IgnoreRules":
+        rules = cls()
+        ignore_file = root / IGNORE_FILE
+        if fs.is_file(ignore_file):
+            for line in fs.read_text(ignore_file).splitlines():
+                rules.add(line)
+            logger.info(f"Loaded {len(rules.rules)} ignore rules from {ignore_file}")
+        for p in extra:
+            rules.add(p)
+        return rules
+
+    def add(self, line: str):
+        line = line.rstrip("\n")
+        if not line.strip() or line.startswith("#"):
+            return
+        # Trailing spaces are insignificant unless escaped.
+        if not line.endswith("\\ "):
+            line = line.rstrip()
+        negate = line.startswith("!")
+        if negate:
+            line = line[1:]
+        elif line.startswith("\\!") or line.startswith("\\#"):
+            line = line[1:]
+        dir_only = line.endswith("/")
+        line = line.rstrip("/")
+        if not line:
+            return
+        # A slash anywhere but the end anchors the pattern to the root.
+        if "/" in line:
+            regex = "^" + _translate(line.lstrip("/")) + "$"
+        else:
+            regex = "^(?:.*/)?" + _translate(line) + "$"
+        self.rules.append(IgnoreRule(re.compile(regex), negate, dir_only))
+
+    def ignored(self, rel: str, is_dir: bool) -> bool:
+        result = False
+        for rule in self.rules:
+            if rule.dir_only and not is_dir:
+                continue
+            if rule.regex.match(rel):
+                result = not rule.negate
+        return result
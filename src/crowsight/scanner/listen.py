@@ -0,0 +1,99 @@
+# src/crowsight/scanner/listen.py
+
+from typing import Dict, List, Optional, Tuple
+
+from loguru import logger
+
+from ..core.node import NodeWrapper
+from ..report.models import Service
+from .goast import (
+    call_args,
+    call_target,
+    enclosing_function,
+    iter_calls,
+    literal_fields,
+    local_value,
+    strip_address_of,
+)
+from .package import PackageInfo
+
+LISTEN_FUNCS = ("ListenAndServe", "ListenAndServeTLS")
+# What net/http binds to when the address is empty.
+DEFAULT_ADDRS = {"ListenAndServe": '":http"', "ListenAndServeTLS": '":https"'}
+
+
+def address_text(node: NodeWrapper, pkg: PackageInfo) -> str:
+    """Quoted literal for constants, otherwise the source expression itself."""
+    if node.type == "identifier" and node.text in pkg.consts:
+        return f'"{pkg.consts[node.text]}"'
+    return node.text
+
+
+def literal_port(addr: str) -> Optional[str]:
+    """`":8081"` → "8081"; None for anything that is not a quoted literal."""
+    if not (addr.startswith('"') and addr.endswith('"')):
+        return None
+    host_port = addr[1:-1]
+    _, sep, port = host_port.rpartition(":")
+    return port if sep and port else None
+
+
+class ListenDetector:
+    """Finds the address a package's HTTP server binds to."""
+
+    def detect(self, pkg: PackageInfo) -> Optional[Tuple[str, str, int]]:
+        """(address, file, line) of the first call that starts a server."""
+        for f in pkg.files:
+            for call in iter_calls(f.root):
+                addr = self._address(call, pkg)
+                if addr is not None:
+                    logger.debug(f"{pkg.name} listens on {addr} ({f.rel}:{call.line})")
+                    return addr, f.rel, call.line
+        return None
+
+    def _address(self, call: NodeWrapper, pkg: PackageInfo) -> Optional[str]:
+        addr = self._address_expr(call, pkg)
+        if addr == '""':
+            _, name = call_target(call)
+            return DEFAULT_ADDRS[name]
+        return addr
+
+    def _address_expr(self, call: NodeWrapper, pkg: PackageInfo) -> Optional[str]:
+        receiver, name = call_target(call)
+        if name not in LISTEN_FUNCS:
+            return None
+        args = call_args(call)
+        if receiver == "http":
+            # http.ListenAndServe(addr, handler) / http.ListenAndServeTLS(addr, ...)
+            return address_text(args[0], pkg) if args else None
+
+        # (*http.Server).ListenAndServe(): find the Server literal's Addr field.
+        # A bare `ListenAndServe()` is some local function (or a dot-import).
+        fn = call.field("function")
+        if fn is None or fn.type != "selector_expression" or fn.field("operand") is None:
+            return None
+        operand = strip_address_of(fn.field("operand"))
+        if operand.type == "identifier":
+            scope = enclosing_function(call) or call
+            value = local_value(scope, operand.text)
+            operand = strip_address_of(value) if value is not None else operand
+        if operand.type != "composite_literal":
+            return operand.text + ".Addr"
+        typ = operand.field("type")
+        if typ is None or not typ.text.endswith("Server"):
+            return None
+        addr = literal_fields(operand).get("Addr")
+        if addr is None:
+            # http.Server defaults to ":http" (":https" for TLS) when Addr is empty.
+            return DEFAULT_ADDRS[name]
+        return address_text(addr, pkg)
+
+
+def port_conflicts(services: List[Service]) -> List[Tuple[str, List[Service]]]:
+    """(port, services) for each literal port that more than one service binds."""
+    by_port: Dict[str, List[Service]] = {}
+    for svc in services:
+        port = literal_port(svc.listen_addr) if svc.listen_addr else None
+        if port:
+            by_port.setdefault(port, []).append(svc)
+    return [(port, svcs) for port, svcs in sorted(by_port.items()) if len(svcs) > 1]
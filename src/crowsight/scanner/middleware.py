@@ -0,0 +1,344 @@
+# src/crowsight/scanner/middleware.py
+
+from dataclasses import dataclass, field
+from typing import Callable, Dict, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+from .goast import (
+    call_args,
+    call_target,
+    enclosing_function,
+    iter_calls,
+    literal_fields,
+    string_value,
+    strip_address_of,
+    unwrap_element,
+    walk_body,
+)
+from .handlers import func_params
+from .package import GoFile, PackageInfo
+from .routes import HANDLER_ADAPTERS, METHOD_CALLS, Route
+
+# Router methods that add middleware to every route registered on the router.
+USE_CALLS = ("Use",)
+# chi's `r.With(mw).Get(...)` and `ar := r.With(mw)`: a router with extra middleware.
+WITH_CALLS = ("With",)
+# gin/echo `r.Group("/v1", mw...)` and chi `r.Route("/v1", fn)` / `r.Group(fn)`.
+GROUP_CALLS = ("Group", "Route")
+# gorilla's `r.PathPrefix("/api").Subrouter()` chains inherit the parent's middleware.
+SUBROUTER_CALLS = ("Subrouter", "PathPrefix", "Host", "Methods", "Schemes", "Headers", "Name")
+# Derived routers whose routes live under their first argument.
+PREFIX_CALLS = ("Group", "Route", "PathPrefix")
+# chi subrouters, which are mounted rather than prefixed (see `MiddlewareResolver.path`).
+MOUNT_POINTS = ("Route",)
+# Calls that serve a handler; whatever wraps it there wraps every route.
+SERVE_CALLS = ("ListenAndServe", "ListenAndServeTLS", "Serve", "ServeTLS")
+MOUNT_CALLS = ("Mount",)
+# Where net/http's package-level HandleFunc/Handle register.
+DEFAULT_MUX = "http.DefaultServeMux"
+
+# (scope, router variable); scope None means net/http's DefaultServeMux.
+Origin = Tuple[Optional[NodeWrapper], str]
+# is_handler(expr, file, scope): whether `expr` resolves to a handler function.
+HandlerCheck = Callable[[NodeWrapper, GoFile, NodeWrapper], bool]
+
+MAX_DEPTH = 8
+
+
+@dataclass
+class Attachment:
+    """A router that is served or mounted, with the middleware wrapped around it."""
+
+    wraps: List[str]
+    # Router it is mounted on (and that router's scope); None when it is served.
+    parent: Optional[NodeWrapper] = None
+    parent_scope: Optional[NodeWrapper] = None
+    # Where it is mounted: `r.Mount("/admin", admin)`.
+    prefix: str = ""
+
+
+@dataclass
+class Chain:
+    """What a router adds to the routes registered on it: a path prefix and middleware."""
+
+    prefix: str = ""
+    wraps: List[str] = field(default_factory=list)
+    # Whether the innermost prefix is a chi mount point (`Route`, `Mount`).
+    mount: bool = False
+
+    def extend(self, wraps: List[str]) -> "Chain":
+        return Chain(self.prefix, self.wraps + wraps, self.mount)
+
+    def under(self, path: str, mount: bool = False) -> "Chain":
+        return Chain(join_path(self.prefix, path), self.wraps, mount)
+
+
+def join_path(prefix: str, path: str) -> str:
+    """`/v1` + `/users` -> `/v1/users`, the way gin and echo join group paths."""
+    if not prefix:
+        return path
+    if not path:
+        return prefix
+    return prefix.rstrip("/") + "/" + path.lstrip("/")
+
+
+def middleware_name(node: NodeWrapper) -> str:
+    """`auth` / `middleware.Logger` as written; anything else as its (one-line) source."""
+    return " ".join(node.text.split())
+
+
+def peel(
+    node: NodeWrapper, accept: Callable[[NodeWrapper], bool]
+) -> Tuple[List[str], NodeWrapper]:
+    """Split `logging(auth(h))` into (["logging", "auth"], h).
+
+    A call counts as a wrapper when one of its arguments is accepted as the
+    handler being wrapped; adapters such as http.HandlerFunc are skipped.
+    """
+    names: List[str] = []
+    for _ in range(MAX_DEPTH):
+        if node.type != "call_expression":
+            break
+        fn = node.field("function")
+        args = call_args(node)
+        if fn is None:
+            break
+        if fn.text in HANDLER_ADAPTERS and len(args) == 1:
+            node = args[0]
+            continue
+        inner = next((a for a in reversed(args) if _wraps(a, accept)), None)
+        if inner is None:
+            break
+        names.append(middleware_name(fn))
+        node = strip_address_of(inner)
+    return names, node
+
+
+def _wraps(arg: NodeWrapper, accept: Callable[[NodeWrapper], bool]) -> bool:
+    arg = strip_address_of(arg)
+    if accept(arg):
+        return True
+    return arg.type == "call_expression" and bool(peel(arg, accept)[0])
+
+
+def _assigned_values(scope: NodeWrapper, name: str) -> List[NodeWrapper]:
+    """Every value assigned to `name` in `scope`, in source order."""
+    values: List[NodeWrapper] = []
+    for node in walk_body(scope):
+        if node.type == "var_spec":
+            names = [n.text for n in node.fields("name")]
+            value = node.field("value")
+            if name in names and value is not None and len(value.named_children) == len(names):
+                values.append(value.named_children[names.index(name)])
+        elif node.type in ("short_var_declaration", "assignment_statement"):
+            left, right = node.field("left"), node.field("right")
+            if left is None or right is None:
+                continue
+            names = [n.text for n in left.named_children]
+            if name in names and len(right.named_children) == len(names):
+                values.append(right.named_children[names.index(name)])
+    return values
+
+
+class MiddlewareResolver:
+    """Works out the middleware chain around each route, outermost first.
+
+    Covers `r.Use(...)` on the registering router and on the routers it is
+    derived from (chi `Route`/`Group`/`With`/`Mount`, gin/echo `Group`,
+    gorilla subrouters), wrappers around the served or mounted router
+    (`handler = mw(handler)`, `ListenAndServe(addr, mw(r))`), gin's extra
+    route arguments, and wrappers around the registered handler itself.
+    The same derivations give the path prefix a route is registered under.
+    """
+
+    def __init__(self, pkg: PackageInfo, is_handler: HandlerCheck):
+        self.pkg = pkg
+        self.is_handler = is_handler
+        self.funcs: Dict[str, List[NodeWrapper]] = {}
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                name = decl.field("name")
+                if decl.type in ("function_declaration", "method_declaration") and name is not None:
+                    self.funcs.setdefault(name.text, []).append(decl)
+        self.attachments: Dict[Origin, Attachment] = {}
+        for f in pkg.files:
+            self._collect_attachments(f)
+
+    def path(self, route: Route) -> str:
+        """The route's path under the group, route and mount prefixes of its router."""
+        chain = self._registered_on(route)
+        path = route.endpoint.path
+        if chain.mount and path == "/":
+            # chi serves a mounted subrouter's "/" at the mount point itself.
+            return chain.prefix
+        return join_path(chain.prefix, path)
+
+    def chain(self, route: Route) -> List[str]:
+        scope = enclosing_function(route.call) or route.file.root
+        names = list(self._registered_on(route).wraps)
+        _, method = call_target(route.call)
+        args = call_args(route.call)
+        if method in METHOD_CALLS and method.isupper() and len(args) > 2:
+            # gin: r.GET("/x", mw1, mw2, handler).
+            names += [middleware_name(a) for a in args[1:-1]]
+        wraps, _ = peel(route.handler, lambda a: self.is_handler(a, route.file, scope))
+        return names + wraps
+
+    def _registered_on(self, route: Route) -> Chain:
+        scope = enclosing_function(route.call) or route.file.root
+        fn = route.call.field("function")
+        selector = fn is not None and fn.type == "selector_expression"
+        receiver = fn.field("operand") if selector else None
+        return self._router_chain(receiver, scope, 0) if receiver is not None else Chain()
+
+    def _router_chain(self, node: NodeWrapper, scope: NodeWrapper, depth: int) -> Chain:
+        if depth > MAX_DEPTH:
+            return Chain()
+        node = strip_address_of(node)
+        if node.type == "identifier" and node.text == "http":
+            att = self.attachments.get((None, DEFAULT_MUX))
+            return Chain(wraps=list(att.wraps) if att else [])
+        if node.type == "identifier":
+            chain = self._inherited(node.text, scope, depth)
+            return chain.extend(self._uses(node.text, scope))
+        if node.type != "call_expression":
+            return Chain()
+        fn = node.field("function")
+        if fn is None or fn.type != "selector_expression":
+            return Chain()
+        operand, method = fn.field("operand"), fn.field("field")
+        if operand is None or method is None:
+            return Chain()
+        args = call_args(node)
+        if method.text in WITH_CALLS:
+            chain = self._router_chain(operand, scope, depth + 1)
+            return chain.extend([middleware_name(a) for a in args])
+        if method.text in GROUP_CALLS:
+            chain = self._derived(operand, method.text, args, scope, depth)
+            return chain.extend([middleware_name(a) for a in args[1:] if a.type != "func_literal"])
+        if method.text in SUBROUTER_CALLS:
+            return self._derived(operand, method.text, args, scope, depth)
+        return Chain()
+
+    def _derived(
+        self,
+        operand: NodeWrapper,
+        method: str,
+        args: List[NodeWrapper],
+        scope: NodeWrapper,
+        depth: int,
+    ) -> Chain:
+        """The chain of `operand.method(args...)`, the router it derives from."""
+        chain = self._router_chain(operand, scope, depth + 1)
+        # chi's `r.Group(fn)` has no path; string_value is None for it.
+        path = string_value(args[0], self.pkg.consts) if args else None
+        if method in PREFIX_CALLS and path is not None:
+            return chain.under(path, mount=method in MOUNT_POINTS)
+        return chain
+
+    def _inherited(self, name: str, scope: NodeWrapper, depth: int) -> Chain:
+        """What a router variable gets from where it is served, mounted or derived."""
+        att = self.attachments.get((scope, name))
+        if att is not None:
+            chain = Chain()
+            if att.parent is not None:
+                chain = self._router_chain(att.parent, att.parent_scope, depth + 1)
+            if att.prefix:
+                chain = chain.under(att.prefix, mount=True)
+            return chain.extend(att.wraps)
+        # chi: r.Route("/x", func(r chi.Router) {...}) and r.Group(func(r chi.Router) {...}).
+        if scope.type == "func_literal" and name in (n for n, _ in func_params(scope)):
+            args = scope.parent
+            call = args.parent if args is not None else None
+            if call is not None and call.type == "call_expression":
+                fn = call.field("function")
+                if fn is not None and fn.type == "selector_expression":
+                    method, operand = fn.field("field"), fn.field("operand")
+                    if method is not None and method.text in GROUP_CALLS and operand is not None:
+                        outer = enclosing_function(call) or scope
+                        return self._derived(operand, method.text, call_args(call), outer, depth)
+            return Chain()
+        values = _assigned_values(scope, name)
+        if values and values[0].type == "call_expression":
+            return self._router_chain(values[0], scope, depth + 1)
+        return Chain()
+
+    def _uses(self, name: str, scope: NodeWrapper) -> List[str]:
+        names: List[str] = []
+        for node in walk_body(scope):
+            if node.type != "call_expression":
+                continue
+            receiver, method = call_target(node)
+            if receiver == name and method in USE_CALLS:
+                names.extend(middleware_name(a) for a in call_args(node))
+        return names
+
+    def _collect_attachments(self, f: GoFile):
+        for call in iter_calls(f.root):
+            receiver, name = call_target(call)
+            args = call_args(call)
+            scope = enclosing_function(call) or f.root
+            target = None
+            parent = None
+            prefix = ""
+            if name in SERVE_CALLS and args:
+                target = args[-1]
+            elif name in MOUNT_CALLS and len(args) == 2:
+                fn = call.field("function")
+                target, parent = args[1], fn.field("operand") if fn is not None else None
+                prefix = string_value(args[0], self.pkg.consts) or ""
+            if target is not None:
+                att = Attachment([], parent, scope if parent else None, prefix)
+                self._attach(target, scope, att)
+        for node in f.root.descendants():
+            if node.type == "composite_literal" and node.field("type") is not None:
+                if node.field("type").text in ("http.Server", "&http.Server"):
+                    handler = literal_fields(node).get("Handler")
+                    if handler is not None:
+                        scope = enclosing_function(node) or f.root
+                        self._attach(unwrap_element(handler), scope, Attachment([]))
+
+    def _attach(self, node: NodeWrapper, scope: NodeWrapper, att: Attachment, depth: int = 0):
+        """Follow `node` back to the router it serves, collecting wrappers on the way."""
+        if depth > MAX_DEPTH:
+            return
+        names, inner = peel(node, lambda a: a.type in ("identifier", "selector_expression", "nil"))
+        att.wraps = att.wraps + names
+        if inner.text in ("nil", DEFAULT_MUX):
+            self.attachments.setdefault((None, DEFAULT_MUX), att)
+            return
+        if inner.type == "identifier":
+            values = _assigned_values(scope, inner.text)
+            # `handler = mw(handler)` reassignments wrap, newest outermost.
+            for value in reversed(values):
+                wraps, base = peel(value, lambda a: a.type == "identifier" and a.text == inner.text)
+                if not wraps or base.text != inner.text:
+                    break
+                att.wraps = att.wraps + wraps
+                values = values[:-1]
+            first = values[-1] if values else None
+            if first is not None and (
+                first.type == "identifier" or peel(first, lambda a: a.type == "identifier")[0]
+            ):
+                # `handler := r` or `handler := mw(r)`: keep following to the router.
+                self._attach(first, scope, att, depth + 1)
+            else:
+                self.attachments.setdefault((scope, inner.text), att)
+            return
+        if inner.type == "call_expression" and not call_args(inner):
+            # A constructor in this package: `routes()` / `s.router()` returning its router.
+            _, fname = call_target(inner)
+            for decl in self.funcs.get(fname, []):
+                returned = self._returned_name(decl)
+                if returned is not None:
+                    self.attachments.setdefault((decl, returned), att)
+
+    @staticmethod
+    def _returned_name(decl: NodeWrapper) -> Optional[str]:
+        for node in walk_body(decl):
+            if node.type == "return_statement":
+                results = [c for c in node.named_children if c.type == "expression_list"]
+                exprs = results[0].named_children if results else node.named_children
+                if len(exprs) == 1 and exprs[0].type == "identifier":
+                    return exprs[0].text
+        return None
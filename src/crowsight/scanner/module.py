@@ -0,0 +1,184 @@
+# src/crowsight/scanner/module.py
+
+from dataclasses import dataclass
+from pathlib import Path
+import os
+import re
+from typing import List, Optional, Pattern
+
+from loguru import logger
+
+from .fs import OS_FS, FileSystem
+
+GO_MOD = "go.mod"
+WILDCARD = "..."
+# Directories never searched for nested modules, as `go` itself skips them.
+VENDOR = "vendor"
+SKIP_DIRS = (VENDOR, "testdata", "node_modules")
+
+
+@dataclass
+class GoModule:
+    """A directory with a go.mod, and the module path it declares."""
+
+    root: Path
+    # Module path from the `module` directive; "" if go.mod does not declare one.
+    path: str
+
+
+def read_module_path(gomod: Path, fs: FileSystem = OS_FS) -> str:
+    for line in fs.read_text(gomod).splitlines():
+        line = line.split("//", 1)[0].strip()
+        if line.startswith("module"):
+            return line[len("module"):].strip().strip('"`')
+    return ""
+
+
+def find_module(start: Path, fs: FileSystem = OS_FS) -> Optional[GoModule]:
+    """Nearest go.mod at or above `start` (a file or directory)."""
+    here = Path(os.path.abspath(start))
+    if fs.is_file(here):
+        here = here.parent
+    for d in (here, *here.parents):
+        gomod = d / GO_MOD
+        if fs.is_file(gomod):
+            logger.debug(f"Using module context from {gomod}")
+            return GoModule(root=d, path=read_module_path(gomod, fs))
+    return None
+
+
+def find_modules(
+    root: Path, enclosing: Optional[GoModule] = None, fs: FileSystem = OS_FS
+) -> List[GoModule]:
+    """Every go.mod at or below `root`, plus `enclosing` (the module around it)."""
+    modules: List[GoModule] = [enclosing] if enclosing is not None else []
+    for dirpath, dirnames, filenames in fs.walk(root):
+        dirnames[:] = sorted(
+            d for d in dirnames if d not in SKIP_DIRS and d[:1] not in (".", "_")
+        )
+        d = Path(dirpath)
+        if GO_MOD in filenames and not any(fs.samefile(d, m.root) for m in modules):
+            modules.append(GoModule(root=d, path=read_module_path(d / GO_MOD, fs)))
+    if len(modules) > 1:
+        logger.info(f"Found {len(modules)} Go modules under {root}")
+    return modules
+
+
+class ModuleSet:
+    """The modules of a (possibly multi-module) tree, relative to the scan root.
+
+    A directory belongs to the innermost module around it, and an import path
+    to the module with the longest matching path, so packages and imports on
+    either side of a nested go.mod are attributed the way `go` would. Other
+    import paths are found in a module's vendor directory, if it has one.
+    """
+
+    def __init__(self, modules: List[GoModule], root: Path, fs: FileSystem = OS_FS):
+        self.root = Path(os.path.abspath(root))
+        self.fs = fs
+        # Innermost first, so the first module containing a directory owns it.
+        self.modules = sorted(
+            modules, key=lambda m: len(Path(os.path.abspath(m.root)).parts), reverse=True
+        )
+        self._by_path = sorted(
+            (m for m in modules if m.path), key=lambda m: len(m.path), reverse=True
+        )
+
+    @property
+    def paths(self) -> List[str]:
+        return sorted(m.path for m in self.modules)
+
+    def owner(self, rel_dir: str) -> Optional[GoModule]:
+        """The module that the directory (relative to the scan root) belongs to."""
+        d = self.root / rel_dir
+        for m in self.modules:
+            base = Path(os.path.abspath(m.root))
+            if d == base or base in d.parents:
+                return m
+        return None
+
+    def import_dir(self, import_path: str) -> Optional[str]:
+        """Directory (relative to the scan root) of an import path inside one of the modules."""
+        for m in self._by_path:
+            if import_path != m.path and not import_path.startswith(m.path + "/"):
+                continue
+            sub = import_path[len(m.path) :].lstrip("/")
+            return self._under_root(os.path.join(os.path.abspath(m.root), sub))
+        for m in self.modules:
+            vendored = os.path.join(os.path.abspath(m.root), VENDOR, import_path)
+            if self.fs.is_dir(Path(vendored)):
+                return self._under_root(vendored)
+        return None
+
+    def import_path(self, rel_dir: str) -> Optional[str]:
+        """Import path of a directory (relative to the scan root), if a module owns it."""
+        m = self.owner(rel_dir)
+        if m is None:
+            return None
+        sub = os.path.relpath(self.root / rel_dir, os.path.abspath(m.root)).replace(os.sep, "/")
+        if sub.startswith(VENDOR + "/"):
+            return sub[len(VENDOR) + 1 :]
+        if not m.path:
+            return None
+        return m.path if sub == "." else f"{m.path}/{sub}"
+
+    def _under_root(self, path: str) -> Optional[str]:
+        rel = os.path.relpath(path, self.root)
+        if rel == ".." or rel.startswith("../"):
+            return None
+        return rel.replace(os.sep, "/")
+
+
+@dataclass
+class PackagePattern:
+    """A `go list`-style pattern (`./services/...`) relative to the scan root."""
+
+    pattern: str
+    # Directory (relative to the scan root) to start walking from.
+    start: str
+    regex: Pattern
+    wildcard: bool
+
+    def matches(self, rel_dir: str) -> bool:
+        return bool(self.regex.fullmatch(rel_dir))
+
+    def prunes(self, rel_dir: str) -> bool:
+        """Like `go list`, `...` never descends into testdata, `.x` or `_x` dirs."""
+        name = rel_dir.rsplit("/", 1)[-1]
+        return self.wildcard and (name == "testdata" or name[:1] in (".", "_"))
+
+
+def compile_pattern(
+    pattern: str, cwd: Path, root: Path, modules: ModuleSet
+) -> Optional[PackagePattern]:
+    """Resolve a relative or import-path pattern to one rooted at `root`.
+
+    Returns None (and logs) for patterns outside the scanned modules.
+    """
+    if pattern.startswith((".", "/")):
+        rel = os.path.relpath(os.path.join(cwd, pattern), root)
+        if rel == ".." or rel.startswith("../"):
+            logger.warning(f"Pattern {pattern!r} is outside {root}; skipping")
+            return None
+        rel = Path(rel).as_posix()
+    else:
+        found = modules.import_dir(pattern)
+        if found is None:
+            logger.warning(f"Pattern {pattern!r} matches no packages in these modules; skipping")
+            return None
+        rel = found
+
+    if WILDCARD not in rel:
+        return PackagePattern(pattern, rel, re.compile(re.escape(rel)), wildcard=False)
+
+    prefix = rel[: rel.index(WILDCARD)]
+    start = prefix.rsplit("/", 1)[0] if "/" in prefix else "."
+    if rel == WILDCARD:
+        regex = ".*"
+    elif rel.endswith("/" + WILDCARD):
+        # `x/...` also matches `x` itself.
+        head = rel[: -len("/" + WILDCARD)]
+        regex = ".*".join(map(re.escape, head.split(WILDCARD))) + "(?:/.*)?"
+    else:
+        regex = ".*".join(map(re.escape, rel.split(WILDCARD)))
+    return PackagePattern(pattern, start, re.compile(regex), wildcard=True)
@@ -0,0 +1,28 @@
+# src/crowsight/scanner/observability.py
+
+from typing import List
+
+from ..report.models import Endpoint, Service
+
+METRICS_PATHS = ("/metrics",)
+# Prometheus client handlers, wrapped or not (`gin.WrapH(promhttp.Handler())`).
+METRICS_HANDLERS = ("promhttp.Handler", "promhttp.HandlerFor", "promhttp.InstrumentMetricHandler")
+HEALTH_PATHS = ("/healthz", "/readyz", "/livez", "/health", "/ready")
+
+
+def serves_metrics(ep: Endpoint) -> bool:
+    if ep.path.rstrip("/") in METRICS_PATHS:
+        return True
+    return any(h + "(" in (ep.handler or "") for h in METRICS_HANDLERS)
+
+
+def serves_health(ep: Endpoint) -> bool:
+    return ep.path.rstrip("/") in HEALTH_PATHS
+
+
+def mark_observability(services: List[Service]):
+    """Set each service's `has_metrics` and `has_health` from its HTTP routes."""
+    for svc in services:
+        routes = [ep for ep in svc.endpoints if ep.protocol == "http"]
+        svc.has_metrics = any(map(serves_metrics, routes))
+        svc.has_health = any(map(serves_health, routes))
@@ -0,0 +1,178 @@
+# src/crowsight/scanner/outbound.py
+
+import re
+from typing import List, Optional, Tuple
+from urllib.parse import urlsplit
+
+from loguru import logger
+
+from ..core.node import NodeWrapper
+from ..report.models import OutboundCall, Service
+from .goast import (
+    STRING_TYPES,
+    call_args,
+    call_target,
+    enclosing_function,
+    iter_calls,
+    local_value,
+    unquote,
+)
+from .package import PackageInfo
+from .routes import ROUTE_PARAM
+
+# http.<Func>(url, ...) helpers and the method each one issues.
+HTTP_HELPERS = {"Get": "GET", "Head": "HEAD", "Post": "POST", "PostForm": "POST"}
+METHOD_CONSTS = {
+    f"http.Method{m.capitalize()}": m
+    for m in ("GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE")
+}
+# Stands in for any part of a URL that cannot be resolved statically.
+PLACEHOLDER = "{}"
+_PRINTF_VERB = re.compile(r"%[-+# 0-9.]*[a-zA-Z]")
+
+
+def _is_client(receiver: Optional[str]) -> bool:
+    return receiver is not None and (receiver == "http" or receiver.lower().endswith("client"))
+
+
+class OutboundDetector:
+    """Finds outbound HTTP client calls and the URLs they target."""
+
+    def detect(self, pkg: PackageInfo) -> List[OutboundCall]:
+        calls: List[OutboundCall] = []
+        for f in pkg.files:
+            for call in iter_calls(f.root):
+                found = self._outbound(call, pkg)
+                if found is None:
+                    continue
+                method, url = found
+                target, resolved = self._target(url, pkg, call) if url else (None, False)
+                calls.append(
+                    OutboundCall(
+                        method=method,
+                        target=target,
+                        expression=url.text if url else call.text,
+                        resolved=resolved,
+                        file=f.rel,
+                        line=call.line,
+                    )
+                )
+        logger.debug(f"Detected {len(calls)} outbound calls in package {pkg.name}")
+        return calls
+
+    def _outbound(self, call, pkg) -> Optional[Tuple[str, Optional[NodeWrapper]]]:
+        receiver, name = call_target(call)
+        args = call_args(call)
+        if name in HTTP_HELPERS and _is_client(receiver) and args:
+            return HTTP_HELPERS[name], args[0]
+        if receiver == "http" and name in ("NewRequest", "NewRequestWithContext"):
+            offset = 1 if name == "NewRequestWithContext" else 0
+            if len(args) < offset + 2:
+                return None
+            return self._method(args[offset], pkg), args[offset + 1]
+        if name == "Do" and _is_client(receiver) and len(args) == 1:
+            # client.Do(req) is reported at the NewRequest site when we can see it.
+            req = args[0]
+            scope = enclosing_function(call) or call
+            value = local_value(scope, req.text) if req.type == "identifier" else None
+            if value is not None and value.type == "call_expression":
+                _, ctor = call_target(value)
+                if ctor in ("NewRequest", "NewRequestWithContext"):
+                    return None
+            return "", None
+        return None
+
+    def _method(self, node: NodeWrapper, pkg: PackageInfo) -> str:
+        if node.type in STRING_TYPES:
+            return unquote(node.text).upper()
+        if node.text in METHOD_CONSTS:
+            return METHOD_CONSTS[node.text]
+        if node.type == "identifier" and node.text in pkg.consts:
+            return pkg.consts[node.text].upper()
+        return ""
+
+    def _target(self, node, pkg, call) -> Tuple[str, bool]:
+        """Flatten literals, consts, `+` and Sprintf; unknown parts become {}."""
+        scope = enclosing_function(call) or call
+        parts = self._parts(node, pkg, scope, depth=0)
+        return (
+            "".join(PLACEHOLDER if p is None else p for p in parts),
+            all(p is not None for p in parts),
+        )
+
+    def _parts(self, node, pkg, scope, depth) -> List[Optional[str]]:
+        if node.type in STRING_TYPES:
+            return [unquote(node.text)]
+        if node.type == "parenthesized_expression" and node.named_children:
+            return self._parts(node.named_children[0], pkg, scope, depth)
+        if node.type == "binary_expression":
+            op = node.field("operator")
+            if op is not None and op.text == "+":
+                return self._parts(node.field("left"), pkg, scope, depth) + self._parts(
+                    node.field("right"), pkg, scope, depth
+                )
+        if node.type == "identifier":
+            if node.text in pkg.consts:
+                return [pkg.consts[node.text]]
+            value = local_value(scope, node.text) if depth < 3 else None
+            if value is not None:
+                return self._parts(value, pkg, scope, depth + 1)
+        if node.type == "call_expression":
+            fn = node.field("function")
+            args = call_args(node)
+            sprintf = fn is not None and fn.text == "fmt.Sprintf"
+            if sprintf and args and args[0].type in STRING_TYPES:
+                pieces = _PRINTF_VERB.split(unquote(args[0].text))
+                parts: List[Optional[str]] = [pieces[0]]
+                for piece in pieces[1:]:
+                    parts.extend([None, piece])
+                return parts
+        return [None]
+
+
+def target_path(target: str) -> Optional[str]:
+    """The path part of a best-effort URL: `{}/users/{}` → `/users/{}`."""
+    if target.startswith(("http://", "https://")):
+        return urlsplit(target).path or "/"
+    if target.startswith(PLACEHOLDER):
+        # Unresolved base URL (e.g. from an env var) followed by a path.
+        rest = target[len(PLACEHOLDER) :]
+        return rest if rest.startswith("/") else None
+    return target.split("?")[0] if target.startswith("/") else None
+
+
+def paths_match(outbound: str, route: str) -> bool:
+    a = outbound.split("?")[0].strip("/").split("/")
+    b = route.strip("/").split("/")
+    if len(a) != len(b):
+        return False
+    for x, y in zip(a, b):
+        if x == y or x == PLACEHOLDER or ROUTE_PARAM.match(y):
+            continue
+        return False
+    return True
+
+
+def link_outbound(services: List[Service]):
+    """Point each outbound call at the other service whose endpoint it targets."""
+    for svc in services:
+        for call in svc.outbound_calls:
+            call.service = call.service_dir = call.endpoint = None
+            path = target_path(call.target) if call.target else None
+            if path is None:
+                continue
+            for other in services:
+                if other is svc:
+                    continue
+                ep = next(
+                    (
+                        e
+                        for e in other.endpoints
+                        if paths_match(path, e.path)
+                        and (not call.method or e.method in ("ANY", call.method))
+                    ),
+                    None,
+                )
+                if ep is not None:
+                    call.service, call.service_dir, call.endpoint = other.name, other.dir, ep.path
+                    break
@@ -0,0 +1,77 @@
+# src/crowsight/scanner/package.py
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Dict, List, Optional
+
+from ..core.node import NodeWrapper
+from .goast import string_value
+
+if TYPE_CHECKING:
+    from .types import TypeResolver
+
+
+@dataclass
+class GoFile:
+    """A parsed Go source file.
+
+    `root` is the tree-sitter `source_file` node, wrapped so `.text`, `.line`
+    (1-based), `.field(name)` and `.descendants()` work without the raw bytes;
+    `rel` is the path relative to the scan root, as used in findings.
+    """
+
+    path: Path
+    rel: str
+    package: str
+    root: NodeWrapper
+
+
+@dataclass
+class PackageInfo:
+    """All files of one Go package (one directory, one package clause).
+
+    This is what analyzers receive. There is no type checker: types are read
+    from declarations with the helpers in scanner/goast.py and handlers.py
+    (`expr_type`, `local_type`, `receiver_type`), and `consts` resolves
+    package-level string constants. `dir` is relative to the scan root.
+    `resolver` follows the package's imports into the scanned modules; the
+    scanner sets it before the analyzers run.
+    """
+
+    dir: str
+    name: str
+    files: List[GoFile] = field(default_factory=list)
+    resolver: Optional["TypeResolver"] = field(default=None, repr=False)
+    _consts: Optional[Dict[str, str]] = field(default=None, repr=False)
+
+    @property
+    def consts(self) -> Dict[str, str]:
+        """Package-level string constants, keyed by name."""
+        if self._consts is None:
+            self._consts = self._collect_consts()
+        return self._consts
+
+    def _collect_consts(self) -> Dict[str, str]:
+        raw: Dict[str, NodeWrapper] = {}
+        for f in self.files:
+            for decl in f.root.named_children:
+                if decl.type != "const_declaration":
+                    continue
+                for spec in decl.named_children:
+                    if spec.type != "const_spec":
+                        continue
+                    names = spec.fields("name")
+                    value = spec.field("value")
+                    values = value.named_children if value else []
+                    for n, v in zip(names, values):
+                        raw[n.text] = v
+
+        consts: Dict[str, str] = {}
+        # Resolve literals first, then one level of const-to-const aliasing.
+        for _ in range(2):
+            for name, node in raw.items():
+                if name not in consts:
+                    val = string_value(node, consts)
+                    if val is not None:
+                        consts[name] = val
+        return consts
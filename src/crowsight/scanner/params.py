@@ -0,0 +1,130 @@
+# src/crowsight/scanner/params.py
+
+import re
+from typing import Dict, List, Optional
+
+from ..core.node import NodeWrapper
+from ..report.models import Param
+from .goast import call_args, call_target, iter_calls, local_value, string_value
+
+# `{id}`, `{id:[0-9]+}` (chi, gorilla) and `{path...}` (Go 1.22 ServeMux).
+_BRACE = re.compile(r"\{([^}:]*?)(\.\.\.)?(?::([^}]*))?\}")
+# `:id` (gin, echo, httprouter) and trailing `*rest` / `*` catch-alls.
+_COLON = re.compile(r"(?:^|/):(\w+)")
+_STAR = re.compile(r"/\*(\w*)$")
+
+INT_PATTERNS = ("[0-9]+", r"\d+", "[1-9][0-9]*", r"[1-9]\d*", "-?[0-9]+", r"-?\d+")
+INT_PARSERS = {("strconv", "Atoi"), ("strconv", "ParseInt"), ("strconv", "ParseUint")}
+
+# Calls that read one path parameter by name: (receiver or None for any, name, name arg).
+LOOKUPS = (
+    ("chi", "URLParam", 1),
+    ("chi", "URLParamFromCtx", 1),
+    (None, "PathValue", 0),  # net/http (Go 1.22)
+    (None, "Param", 0),  # gin, echo
+    (None, "ByName", 0),  # httprouter, gin's c.Params
+)
+
+
+def path_params(path: str) -> List[Param]:
+    """Parameters declared by a route pattern, in order."""
+    params: List[Param] = []
+    for m in _BRACE.finditer(path):
+        name, rest, pattern = m.group(1), m.group(2), m.group(3)
+        if name in ("", "$"):
+            continue  # `{$}` only anchors the end of a Go 1.22 pattern
+        typ = "integer" if pattern in INT_PATTERNS else "string"
+        params.append(Param(name=name, type=typ, pattern=pattern, catch_all=bool(rest)))
+    for m in _COLON.finditer(path):
+        params.append(Param(name=m.group(1)))
+    m = _STAR.search(path)
+    if m:
+        # chi exposes an unnamed catch-all as URLParam(r, "*").
+        params.append(Param(name=m.group(1) or "*", catch_all=True))
+    return params
+
+
+def _is_vars_call(node: Optional[NodeWrapper]) -> bool:
+    if node is None or node.type != "call_expression":
+        return False
+    return call_target(node) == ("mux", "Vars")
+
+
+class ParamReads:
+    """Path parameters a handler body reads, and whether it parses them as integers."""
+
+    def __init__(self, fn: NodeWrapper):
+        self.names: Dict[str, bool] = {}
+        # A lookup with a computed name, or `mux.Vars(r)` handed on whole.
+        self.opaque = False
+        body = fn.field("body")
+        if body is not None:
+            self._collect(body, fn)
+
+    def _collect(self, body: NodeWrapper, scope: NodeWrapper):
+        for call in iter_calls(body):
+            receiver, name = call_target(call)
+            args = call_args(call)
+            if _is_vars_call(call):
+                self.opaque = self.opaque or not self._indexed(call, scope)
+                continue
+            for want_recv, want_name, slot in LOOKUPS:
+                if name != want_name or (want_recv and receiver != want_recv):
+                    continue
+                if len(args) <= slot:
+                    continue
+                key = string_value(args[slot])
+                if key is None:
+                    self.opaque = True
+                else:
+                    self._read(key, call)
+                break
+        for node in body.descendants():
+            if node.type != "index_expression":
+                continue
+            operand, index = node.field("operand"), node.field("index")
+            key = string_value(index)
+            if operand is None or key is None:
+                continue
+            if _is_vars_call(operand) or (
+                operand.type == "identifier" and _is_vars_call(local_value(scope, operand.text))
+            ):
+                self._read(key, node)
+
+    def _indexed(self, call: NodeWrapper, scope: NodeWrapper) -> bool:
+        """Whether a `mux.Vars` result is only ever indexed by key."""
+        parent = call.parent
+        if parent is not None and parent.type == "index_expression":
+            return True
+        # `vars := mux.Vars(r)`; later `vars["id"]` reads are picked up separately.
+        while parent is not None and parent.type == "expression_list":
+            parent = parent.parent
+        return parent is not None and parent.type in (
+            "short_var_declaration",
+            "assignment_statement",
+            "var_spec",
+        )
+
+    def _read(self, key: str, node: NodeWrapper):
+        parent = node.parent
+        while parent is not None and parent.type == "argument_list":
+            parent = parent.parent
+        parsed = (
+            parent is not None
+            and parent.type == "call_expression"
+            and call_target(parent) in INT_PARSERS
+        )
+        self.names[key] = self.names.get(key, False) or parsed
+
+
+def link_params(params: List[Param], fn: NodeWrapper):
+    """Mark which of `params` the handler `fn` reads, refining types from its parsing."""
+    reads = ParamReads(fn)
+    for p in params:
+        if p.name in reads.names:
+            p.used = True
+            if reads.names[p.name]:
+                p.type = "integer"
+        elif not reads.opaque:
+            p.used = False
+
@@ -0,0 +1,118 @@
+# src/crowsight/scanner/profile.py
+
+from contextlib import contextmanager, nullcontext
+import sys
+import threading
+import time
+from typing import ContextManager, Dict, Iterator, List, Optional
+
+from ..report.models import Metrics
+
+
+class NullProfiler:
+    """Stand-in used when profiling is off; every hook is a no-op."""
+
+    enabled = False
+
+    def phase(self, name: str) -> ContextManager:
+        return nullcontext()
+
+    def analyzer(self, name: str) -> ContextManager:
+        return nullcontext()
+
+    def parsed(self, size: int):
+        pass
+
+    def metrics(self) -> Optional[Metrics]:
+        return None
+
+
+class Profiler(NullProfiler):
+    """Accumulates wall-clock time per scan phase and per analyzer.
+
+    Phases nest per thread: time spent in an inner phase is charged to it
+    and taken out of the phase around it, so the figures add up.
+    """
+
+    enabled = True
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self._local = threading.local()
+        self._started = time.perf_counter()
+        self.phases: Dict[str, float] = {}
+        self.analyzers: Dict[str, float] = {}
+        self.files = 0
+        self.bytes = 0
+
+    @contextmanager
+    def phase(self, name: str) -> Iterator[None]:
+        with self._timed(self.phases, name):
+            yield
+
+    @contextmanager
+    def analyzer(self, name: str) -> Iterator[None]:
+        with self._timed(self.analyzers, name):
+            yield
+
+    def parsed(self, size: int):
+        with self._lock:
+            self.files += 1
+            self.bytes += size
+
+    def metrics(self) -> Metrics:
+        with self._lock:
+            return Metrics(
+                phases={k: round(v, 4) for k, v in sorted(self.phases.items())},
+                analyzers={k: round(v, 4) for k, v in sorted(self.analyzers.items())},
+                files_parsed=self.files,
+                bytes_parsed=self.bytes,
+                total=round(time.perf_counter() - self._started, 4),
+                peak_rss_bytes=peak_rss(),
+            )
+
+    @contextmanager
+    def _timed(self, totals: Dict[str, float], name: str) -> Iterator[None]:
+        stack: List[float] = getattr(self._local, "stack", None) or []
+        self._local.stack = stack
+        # Each frame holds the time its nested phases took.
+        stack.append(0.0)
+        started = time.perf_counter()
+        try:
+            yield
+        finally:
+            elapsed = time.perf_counter() - started
+            nested = stack.pop()
+            if stack:
+                stack[-1] += elapsed
+            with self._lock:
+                totals[name] = totals.get(name, 0.0) + elapsed - nested
+
+
+def peak_rss() -> Optional[int]:
+    try:
+        import resource
+    except ImportError:
+        return None
+    peak = resource.getrusage(resource.RUSAGE_SELF).ru_maxrss
+    # Linux reports kilobytes, macOS bytes.
+    return peak if sys.platform == "darwin" else peak * 1024
+
+
+def format_metrics(m: Metrics) -> str:
+    """A human-readable breakdown, slowest first."""
+    lines = [
+        f"Scan profile: {m.total:.3f}s total, "
+        f"{m.files_parsed} files ({m.bytes_parsed} bytes) parsed"
+    ]
+    for title, times in (("phases", m.phases), ("analyzers", m.analyzers)):
+        if not times:
+            continue
+        lines.append(f"  {title}:")
+        width = max(len(k) for k in times)
+        for name, secs in sorted(times.items(), key=lambda kv: -kv[1]):
+            share = 100 * secs / m.total if m.total else 0.0
+            lines.append(f"    {name:<{width}}  {secs * 1000:9.1f}ms  {share:5.1f}%")
+    if m.peak_rss_bytes is not None:
+        lines.append(f"  peak memory: {m.peak_rss_bytes / (1 << 20):.1f} MiB")
+    return "\n".join(lines) + "\n"
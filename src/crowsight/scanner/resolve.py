@@ -0,0 +1,125 @@
+# src/crowsight/scanner/resolve.py
+
+from typing import Dict, List, Optional, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Struct
+from .goast import call_args, enclosing_function, local_value, strip_address_of, unquote
+from .handlers import expr_type, local_type, receiver_type
+from .package import GoFile, PackageInfo
+from .routes import Route, unwrap_handler
+from .structs import StructCollector, base_type
+
+Decl = Tuple[GoFile, NodeWrapper]
+
+# How many `h := other` hops to follow before giving up.
+MAX_DEPTH = 4
+
+
+def imported_names(f: GoFile) -> List[str]:
+    """Names a file's imports are referred to by (alias or last path element)."""
+    names: List[str] = []
+    for spec in f.root.descendants():
+        if spec.type != "import_spec":
+            continue
+        alias, path = spec.field("name"), spec.field("path")
+        if alias is not None:
+            names.append(alias.text)
+        elif path is not None:
+            names.append(unquote(path.text).rsplit("/", 1)[-1])
+    return names
+
+
+class HandlerResolver:
+    """Follows a registered handler expression to the function that implements it.
+
+    Handles inline literals, package functions (in any file of the package),
+    local aliases, method values such as `srv.handleFoo` or `s.api.list`, and
+    `http.Handler` values whose type has a ServeHTTP method.
+    """
+
+    def __init__(self, pkg: PackageInfo):
+        self.pkg = pkg
+        self.funcs: Dict[str, Decl] = {}
+        self.methods: Dict[Tuple[str, str], Decl] = {}
+        self.methods_by_name: Dict[str, List[Decl]] = {}
+        self._structs: Optional[Dict[str, Struct]] = None
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                name = decl.field("name")
+                if name is None:
+                    continue
+                if decl.type == "function_declaration":
+                    self.funcs[name.text] = (f, decl)
+                elif decl.type == "method_declaration":
+                    recv = receiver_type(decl)
+                    if recv:
+                        self.methods[(base_type(recv), name.text)] = (f, decl)
+                    self.methods_by_name.setdefault(name.text, []).append((f, decl))
+
+    def resolve(self, route: Route) -> Optional[Decl]:
+        scope = enclosing_function(route.call)
+        return self._resolve(route.handler, route.file, scope, 0)
+
+    def _resolve(
+        self, node: NodeWrapper, f: GoFile, scope: Optional[NodeWrapper], depth: int
+    ) -> Optional[Decl]:
+        node = unwrap_handler(strip_address_of(node))
+        if depth > MAX_DEPTH:
+            return None
+        if node.type in ("func_literal", "function_declaration", "method_declaration"):
+            return f, node
+        if node.type == "identifier":
+            value = local_value(scope, node.text) if scope is not None else None
+            if value is not None:
+                return self._resolve(value, f, scope, depth + 1)
+            return self.funcs.get(node.text)
+        if node.type == "selector_expression":
+            operand, name = node.field("operand"), node.field("field")
+            if operand is None or name is None:
+                return None
+            typ = self.type_of(operand, scope)
+            if typ is not None:
+                return self.methods.get((typ, name.text))
+            if operand.type != "identifier" or operand.text in imported_names(f):
+                # Handlers from other packages are not resolvable here.
+                return None
+            # Unknown receiver: accept a method name that is unique in the package.
+            candidates = self.methods_by_name.get(name.text, [])
+            return candidates[0] if len(candidates) == 1 else None
+        if node.type in ("composite_literal", "call_expression") and scope is not None:
+            typ = expr_type(node, scope)
+            found = self.methods.get((base_type(typ), "ServeHTTP")) if typ else None
+            if found is None and node.type == "call_expression":
+                # Middleware such as `auth(h)` or `http.StripPrefix("/x", h)`: the wrapped handler.
+                for arg in reversed(call_args(node)):
+                    found = self._resolve(arg, f, scope, depth + 1)
+                    if found is not None:
+                        break
+            return found
+        return None
+
+    def resolves(self, node: NodeWrapper, f: GoFile, scope: Optional[NodeWrapper]) -> bool:
+        """Whether `node` (in `f`, inside `scope`) names a handler this resolver can find."""
+        return self._resolve(node, f, scope, 0) is not None
+
+    def type_of(self, node: NodeWrapper, scope: Optional[NodeWrapper]) -> Optional[str]:
+        """Named (pointer-stripped) type of a receiver or struct-field chain."""
+        node = strip_address_of(node)
+        if node.type == "identifier":
+            typ = local_type(scope, node.text) if scope is not None else None
+            return base_type(typ) if typ else None
+        if node.type == "selector_expression":
+            operand, name = node.field("operand"), node.field("field")
+            owner = self.type_of(operand, scope) if operand is not None else None
+            struct = self.structs.get(owner) if owner else None
+            for fd in struct.fields if struct else []:
+                if fd.name == name.text:
+                    return base_type(fd.type)
+        return None
+
+    @property
+    def structs(self) -> Dict[str, Struct]:
+        if self._structs is None:
+            self._structs = StructCollector().collect(self.pkg)
+        return self._structs
@@ -0,0 +1,179 @@
+# src/crowsight/scanner/routes.py
+
+from dataclasses import dataclass
+import re
+from typing import Dict, List, Optional, Tuple
+
+from loguru import logger
+
+from ..core.node import NodeWrapper
+from ..report.models import Endpoint, Service
+from .goast import call_args, call_target, iter_calls, string_value
+from .package import GoFile, PackageInfo
+from .params import path_params
+
+HTTP_METHODS = ("GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE")
+
+# net/http, gorilla/mux and chi: pattern first, handler second, any method.
+HANDLE_CALLS = ("HandleFunc", "Handle")
+# chi (`r.Get`), gin (`router.GET`) and echo (`e.POST`) encode the method in the name.
+METHOD_CALLS = {
+    **{m.capitalize(): m for m in HTTP_METHODS},
+    **{m: m for m in HTTP_METHODS},
+    "Any": "ANY",
+}
+# chi's `r.Method("GET", "/x", h)` and `r.MethodFunc(...)` take the method as an argument.
+EXPLICIT_METHOD_CALLS = ("Method", "MethodFunc")
+
+# Receivers whose method-named functions are HTTP clients, not routers.
+CLIENT_RECEIVERS = ("http",)
+
+# `http.HandlerFunc(h)` and friends only adapt the handler; look through them.
+HANDLER_ADAPTERS = ("http.HandlerFunc", "HandlerFunc")
+
+# A path segment that is a parameter in any router's syntax: `{id}`, `{id:[0-9]+}`, `:id`, `*rest`.
+ROUTE_PARAM = re.compile(r"^(\{[^}$][^}]*\}|:\w+|\*\w*)$")
+
+# Go 1.22 ServeMux patterns: `[METHOD ][HOST]/[PATH]`.
+_METHOD_PREFIX = re.compile(r"^([A-Z]+)\s+(\S.*)$")
+
+
+def route_pattern(path: str) -> str:
+    """Path with every parameter segment as `{}`, so router syntaxes compare equal."""
+    return "/".join("{}" if ROUTE_PARAM.match(seg) else seg for seg in path.split("/"))
+
+
+def without_trailing_slash(path: str) -> str:
+    """`route_pattern(path)` with any trailing slash dropped; `/` stays `/`."""
+    return route_pattern(path).rstrip("/") or "/"
+
+
+def mark_trailing_slashes(services: List[Service]):
+    """Set each service's `consistent_slashes` from its HTTP routes other than `/`."""
+    for svc in services:
+        styles = {
+            ep.path.endswith("/")
+            for ep in svc.endpoints
+            if ep.protocol == "http" and ep.path not in ("", "/")
+        }
+        svc.consistent_slashes = len(styles) == 1 if styles else None
+
+
+def split_pattern(pattern: str) -> Tuple[Optional[str], str]:
+    """Split a ServeMux pattern into its method (None for any) and path.
+
+    A host prefix (`example.com/x`) is dropped; crowsight keys routes by path.
+    """
+    method = None
+    m = _METHOD_PREFIX.match(pattern.strip())
+    if m:
+        method, pattern = m.group(1), m.group(2).strip()
+    slash = pattern.find("/")
+    if slash > 0:
+        pattern = pattern[slash:]
+    return method, pattern
+
+
+@dataclass
+class Route:
+    """An endpoint plus the AST it came from, for detectors that need the handler."""
+
+    endpoint: Endpoint
+    handler: NodeWrapper
+    call: NodeWrapper
+    file: GoFile
+
+
+def method_value(node: NodeWrapper, consts: Dict[str, str]) -> Optional[str]:
+    """`"GET"`, a const holding it, or net/http's `http.MethodGet`; upper-cased."""
+    if node.type == "selector_expression" and node.text.startswith("http.Method"):
+        name = node.text[len("http.Method") :].upper()
+        return name if name in HTTP_METHODS else None
+    value = string_value(node, consts)
+    return value.upper() if value else None
+
+
+def unwrap_handler(node: NodeWrapper) -> NodeWrapper:
+    while node.type == "call_expression":
+        fn = node.field("function")
+        args = call_args(node)
+        if fn is None or fn.text not in HANDLER_ADAPTERS or len(args) != 1:
+            break
+        node = args[0]
+    return node
+
+
+def handler_name(node: NodeWrapper) -> Optional[str]:
+    """Inline func literals have no name; everything else is named by its text."""
+    node = unwrap_handler(node)
+    return None if node.type == "func_literal" else node.text
+
+
+class RouteDetector:
+    """Finds route registrations for net/http and common third-party routers."""
+
+    def detect(self, pkg: PackageInfo) -> List[Route]:
+        routes: List[Route] = []
+        for f in pkg.files:
+            for call in iter_calls(f.root):
+                for ep in self._endpoints(call, pkg):
+                    ep.file = f.rel
+                    ep.line = call.line
+                    ep.path_params = path_params(ep.path)
+                    handler = unwrap_handler(call_args(call)[-1])
+                    routes.append(Route(endpoint=ep, handler=handler, call=call, file=f))
+        logger.debug(f"Detected {len(routes)} routes in package {pkg.name}")
+        return routes
+
+    def _endpoints(self, call, pkg: PackageInfo) -> List[Endpoint]:
+        receiver, name = call_target(call)
+        if receiver is None:
+            return []
+        args = call_args(call)
+
+        if name in HANDLE_CALLS and len(args) >= 2:
+            path = string_value(args[0], pkg.consts)
+            if path is None:
+                logger.debug(f"Unresolved route pattern {args[0].text!r}")
+                return []
+            method, path = split_pattern(path)
+            methods = self._chained_methods(call, pkg) or [method or "ANY"]
+            return [
+                Endpoint(method=m, path=path, handler=handler_name(args[-1]))
+                for m in methods
+            ]
+
+        if name in EXPLICIT_METHOD_CALLS and len(args) >= 3:
+            method = method_value(args[0], pkg.consts)
+            path = string_value(args[1], pkg.consts)
+            if method is None or path is None:
+                return []
+            return [Endpoint(method=method, path=path, handler=handler_name(args[-1]))]
+
+        if name in METHOD_CALLS and receiver not in CLIENT_RECEIVERS and len(args) >= 2:
+            path = string_value(args[0], pkg.consts)
+            # Header.Get, client.Post(url, ...) and friends share the names; real
+            # route patterns always start with a slash.
+            if path is None or not path.startswith("/"):
+                return []
+            return [
+                Endpoint(
+                    method=METHOD_CALLS[name], path=path, handler=handler_name(args[-1])
+                )
+            ]
+
+        return []
+
+    def _chained_methods(self, call, pkg: PackageInfo) -> List[str]:
+        """gorilla/mux: `r.HandleFunc("/x", h).Methods("GET", "POST")`."""
+        sel = call.parent
+        if sel is None or sel.type != "selector_expression":
+            return []
+        fld = sel.field("field")
+        outer = sel.parent
+        if fld is None or fld.text != "Methods" or outer is None:
+            return []
+        if outer.type != "call_expression":
+            return []
+        methods = [method_value(a, pkg.consts) for a in call_args(outer)]
+        return [m for m in methods if m]
@@ -0,0 +1,835 @@
+# src/crowsight/scanner/scanner.py
+
+from concurrent.futures import Future, ThreadPoolExecutor
+from dataclasses import asdict, dataclass, field
+from pathlib import Path
+from typing import (
+    Any,
+    Dict,
+    Iterator,
+    List,
+    Mapping,
+    Optional,
+    Sequence,
+    Set,
+    Tuple,
+    Union,
+)
+import os
+import threading
+import time
+
+from loguru import logger
+from tree_sitter_language_pack import get_parser
+
+from ..analyzers.auth import DEFAULT_AUTH_MIDDLEWARE, DEFAULT_PUBLIC_PATHS
+from ..analyzers.complexity import DEFAULT_MAX_COMPLEXITY
+from ..analyzers.policy import FindingsPolicy
+from ..analyzers.registry import build_analyzers
+from ..analyzers.webhooks import DEFAULT_WEBHOOK_PATTERNS
+from ..cache.scan_cache import ScanCache
+from ..core.parser import ParserEngine
+from ..report.models import Finding, Report, ScanError, Service, Todo, from_dict
+from ..report.schema import SCHEMA_VERSION
+from .build import BuildContext, host_goarch, host_goos
+from .callgraph import CallGraphBuilder, func_label
+from .db import QueryFinder
+from .changes import changed_files
+from .env import EnvDetector
+from .fs import OS_FS, FileSystem, MemoryFileSystem
+from .goast import cyclomatic
+from .grpc import GrpcDetector
+from .handlers import HandlerDetector, find_dtos
+from .ignore import IGNORE_FILE, IgnoreRules
+from .listen import ListenDetector
+from .middleware import MiddlewareResolver
+from .module import (
+    GO_MOD,
+    ModuleSet,
+    PackagePattern,
+    compile_pattern,
+    find_module,
+    find_modules,
+)
+from .observability import mark_observability
+from .outbound import OutboundDetector, link_outbound
+from .package import GoFile, PackageInfo
+from .params import link_params, path_params
+from .profile import NullProfiler, Profiler
+from .resolve import HandlerResolver
+from .routes import RouteDetector, mark_trailing_slashes
+from .status import status_codes
+from .todos import DEFAULT_MARKERS, TodoDetector
+from .types import TypeResolver
+
+# Bump whenever detectors change what they extract, so stale caches are dropped.
+ANALYSIS_VERSION = 20
+
+
+@dataclass
+class ScanOptions:
+    """Knobs for a Go service scan."""
+
+    todo_markers: List[str] = field(default_factory=lambda: list(DEFAULT_MARKERS))
+    # Parser threads; 0 means one per CPU.
+    concurrency: int = 0
+    # gitignore-style patterns applied after the root's .crowsightignore.
+    exclude: List[str] = field(default_factory=list)
+    # Directory for the incremental cache (cache.json); None disables caching.
+    cache_dir: Optional[str] = None
+    # Handlers above this cyclomatic complexity become findings; 0 disables.
+    max_complexity: int = DEFAULT_MAX_COMPLEXITY
+    # Build context: files whose constraints or _GOOS/_GOARCH suffixes don't
+    # match are skipped, as are _test.go files unless include_tests is set.
+    build_tags: List[str] = field(default_factory=list)
+    goos: str = field(default_factory=host_goos)
+    goarch: str = field(default_factory=host_goarch)
+    include_tests: bool = False
+    # Finding code -> "info" | "warning" | "error" | "ignore".
+    severities: Dict[str, str] = field(default_factory=dict)
+    # Analyzer names to run on top of the defaults, and to skip.
+    enable: List[str] = field(default_factory=list)
+    disable: List[str] = field(default_factory=list)
+    # For the opt-in "auth" analyzer: globs naming authentication middleware,
+    # and route globs that are public on purpose.
+    auth_middleware: List[str] = field(default_factory=lambda: list(DEFAULT_AUTH_MIDDLEWARE))
+    public_paths: List[str] = field(default_factory=lambda: list(DEFAULT_PUBLIC_PATHS))
+    # For the "secrets" analyzer: regexes for file paths or literals that are
+    # known test fixtures rather than leaks.
+    secret_allowlist: List[str] = field(default_factory=list)
+    # For the "imports" analyzer: "FROM:TO" package globs; packages matching
+    # FROM may not import packages matching TO.
+    forbid_imports: List[str] = field(default_factory=list)
+    # For the "webhooks" analyzer: globs naming webhook routes or handlers.
+    webhook_patterns: List[str] = field(default_factory=lambda: list(DEFAULT_WEBHOOK_PATTERNS))
+    # For the "slashes" analyzer: route globs registered both with and without
+    # a trailing slash on purpose.
+    trailing_slash_paths: List[str] = field(default_factory=list)
+    # How many calls deep to record each endpoint's call graph; 0 records none.
+    callgraph_depth: int = 0
+    # Git ref (e.g. origin/main): only report findings in files changed since it.
+    # The rest of the report still covers the whole tree.
+    since: Optional[str] = None
+    # Record time per phase and analyzer into Report.metrics.
+    profile: bool = False
+
+
+@dataclass
+class ScanEvent:
+    """One streamed scan result.
+
+    `kind` is "service", "endpoint", "todo", "finding" or "error"; `item` is the
+    matching report model. Endpoint events also carry their service's name, and
+    arrive after the "service" event that already contains them.
+    """
+
+    kind: str
+    item: Any
+    service: Optional[str] = None
+
+
+class GoScanner:
+    """Parses every Go package under a root and builds a service Report.
+
+    `root` may be a directory or a single .go file. Paths in the report are
+    relative to the nearest enclosing go.mod, so scanning a subdirectory yields
+    the same service entries as scanning the whole module. With `patterns`
+    (e.g. `./services/...`, resolved against `root`) only matching packages
+    are scanned. Files are read through `fs`, the OS filesystem by default
+    (see `scan_fs` for in-memory sources).
+    """
+
+    def __init__(
+        self,
+        root: str,
+        options: Optional[ScanOptions] = None,
+        patterns: Optional[List[str]] = None,
+        fs: FileSystem = OS_FS,
+    ):
+        self.fs = fs
+        self.target = Path(root)
+        target_dir = self.target.parent if fs.is_file(self.target) else self.target
+        self.module = find_module(self.target, fs)
+        if (
+            self.module is not None
+            and fs.is_dir(target_dir)
+            and not fs.samefile(self.module.root, target_dir)
+        ):
+            self.root = self.module.root
+        else:
+            self.root = target_dir
+        # Every module under the root; packages and imports are attributed to them.
+        self.modules = self._find_modules()
+        self.patterns: Optional[List[PackagePattern]] = None
+        if patterns is not None:
+            compiled = (compile_pattern(p, self.target, self.root, self.modules) for p in patterns)
+            self.patterns = [p for p in compiled if p is not None]
+        self.options = options or ScanOptions()
+        self.build = BuildContext(
+            goos=self.options.goos,
+            goarch=self.options.goarch,
+            tags=list(self.options.build_tags),
+            include_tests=self.options.include_tests,
+        )
+        self._local = threading.local()
+        # Watch mode keeps parsed trees and an in-memory cache between scans so
+        # only changed files are parsed again.
+        self.reuse_trees = False
+        self._trees: Dict[str, Tuple[str, GoFile]] = {}
+        self._cache: Optional[ScanCache] = None
+        # Packages parsed during the current scan, by directory, for type resolution.
+        self._packages: Dict[str, Optional[PackageInfo]] = {}
+        # Each directory's results from the last complete scan, and the other
+        # directories they resolved types from, for `update`.
+        self._parts: Optional[Dict[str, Report]] = None
+        self._deps: Dict[str, Set[str]] = {}
+        # Directories a cancelled update left behind.
+        self._stale: Set[str] = set()
+        self.routes = RouteDetector()
+        self.grpc = GrpcDetector()
+        self.handlers = HandlerDetector()
+        self.listen = ListenDetector()
+        self.outbound = OutboundDetector()
+        self.env = EnvDetector()
+        self.todos = TodoDetector(self.options.todo_markers)
+        self.policy = FindingsPolicy.from_mapping(self.options.severities)
+        self.analyzers = build_analyzers(self.options)
+        # Replaced by a Profiler at the start of each profiled scan.
+        self.profiler: NullProfiler = NullProfiler()
+        # Files findings are limited to, relative to root; None means all.
+        self.changed: Optional[Set[str]] = None
+        if self.options.since:
+            if fs is not OS_FS:
+                raise ValueError("--since needs a git checkout on disk")
+            self.changed = changed_files(self.root, self.options.since)
+
+    def scan(self) -> Report:
+        started = time.perf_counter()
+        report = Report(root=str(self.root))
+        for event in self.stream():
+            if event.kind == "service":
+                report.services.append(event.item)
+            elif event.kind == "todo":
+                report.todos.append(event.item)
+            elif event.kind == "finding":
+                report.findings.append(event.item)
+            elif event.kind == "error":
+                report.errors.append(event.item)
+        self._complete(report)
+        elapsed = time.perf_counter() - started
+        logger.bind(
+            services=len(report.services),
+            endpoints=len(report.endpoints),
+            findings=len(report.findings),
+            errors=len(report.errors),
+            duration=round(elapsed, 3),
+        ).info(
+            f"Found {len(report.services)} services, "
+            f"{len(report.endpoints)} endpoints in {elapsed:.2f}s"
+        )
+        return report
+
+    def scans(self, root: str, options: Optional[ScanOptions] = None) -> bool:
+        """Whether this scanner covers `root` (with `options`, if given)."""
+        return Path(root) == self.target and options in (None, self.options)
+
+    def update(
+        self,
+        report: Report,
+        changed: Sequence[str],
+        deleted: Sequence[str] = (),
+        cancel: Optional[threading.Event] = None,
+    ):
+        """Re-analyze what `changed`/`deleted` files affect and merge it into `report`.
+
+        `report` must come from this scanner. The directories of the files are
+        analyzed again, along with the directories whose types or imports were
+        read from them; every other directory keeps its results from the last
+        scan. Parsed trees are kept from the first update on, so later updates
+        parse only the files that changed. Edits to go.mod or .crowsightignore,
+        a `since` filter, or a scanner that has not completed a scan yet fall
+        back to a full scan. Setting `cancel` leaves `report` as it was.
+        """
+        rels = {self._rel(self.root / p) for p in [*changed, *deleted]}
+        if (
+            self._parts is None
+            or self.options.since
+            or any(Path(r).name in (GO_MOD, IGNORE_FILE) for r in rels)
+        ):
+            logger.info("Rescanning in full")
+            replace(report, self.scan())
+            return
+        started = time.perf_counter()
+        self.profiler = Profiler() if self.options.profile else NullProfiler()
+        by_dir = self._by_dir()
+        dirty = self._stale | {dir_of(r) for r in rels if r.endswith(".go")}
+        # Packages that resolved types through a dirty one, and ones that came or went.
+        dirty |= {d for d, deps in self._deps.items() if deps & dirty}
+        dirty |= set(by_dir) ^ set(self._parts)
+        parts = {d: p for d, p in self._parts.items() if d not in dirty}
+        dep_dirs = {d: deps for d, deps in self._deps.items() if d not in dirty}
+        self.reuse_trees = True
+        self._packages = {}
+        cache = self._open_cache()
+        pool = ThreadPoolExecutor(max_workers=self._workers())
+        try:
+            plan = []
+            for d in sorted(dirty & set(by_dir)):
+                hashes = {self._rel(p): ScanCache.checksum(p, self.fs) for p in by_dir[d]}
+                plan.append((d, hashes, [self._submit(pool, p, hashes) for p in by_dir[d]]))
+            for d, hashes, futures in plan:
+                if cancel is not None and cancel.is_set():
+                    logger.info("Update cancelled")
+                    self._stale = dirty
+                    return
+                parsed = [fu.result() for fu in futures]
+                self._keep_trees(parsed, hashes)
+                part, deps = self._analyze_dir(parsed)
+                if cache is not None:
+                    cache.update(d, hashes, {**dump_partial(part, d), "deps": deps})
+                part.findings = self._reported(part.findings)
+                parts[d], dep_dirs[d] = part, {dir_of(rel) for rel in deps}
+        finally:
+            pool.shutdown(wait=False, cancel_futures=True)
+            if cache is not None:
+                cache.save()
+        current = {self._rel(p) for files in by_dir.values() for p in files}
+        self._trees = {k: v for k, v in self._trees.items() if k in current}
+        self._parts, self._deps, self._stale = parts, dep_dirs, set()
+        merged = Report(root=str(self.root))
+        # In discovery order, as a full scan would have merged them.
+        for d in by_dir:
+            merged.services.extend(parts[d].services)
+            merged.todos.extend(parts[d].todos)
+            merged.findings.extend(parts[d].findings)
+            merged.errors.extend(parts[d].errors)
+        self._complete(merged)
+        replace(report, merged)
+        elapsed = time.perf_counter() - started
+        logger.bind(dirs=len(dirty), files=len(rels), duration=round(elapsed, 3)).info(
+            f"Updated {len(dirty)} directories in {elapsed:.2f}s"
+        )
+
+    def _complete(self, report: Report):
+        """Link and check across services once every directory is merged."""
+        with self.profiler.phase("link"):
+            link_outbound(report.services)
+            mark_trailing_slashes(report.services)
+            mark_observability(report.services)
+        # Cross-service checks only make sense once every package is merged.
+        for name, analyzer in self.analyzers:
+            with self.profiler.analyzer(name):
+                found = tagged(name, analyzer.finish(report))
+                report.findings.extend(self._reported(found))
+        report.sort()
+        report.metrics = self.profiler.metrics()
+        report.summarize()
+        report.hash = report.content_hash()
+        report.scanner = self
+        # The parsed packages are not needed again until the next scan.
+        self._packages = {}
+
+    def stream(self, cancel: Optional[threading.Event] = None) -> Iterator[ScanEvent]:
+        """Yield results directory by directory as soon as each is analyzed.
+
+        Setting `cancel` (or closing the generator) stops the scan early.
+        """
+        logger.info(f"Scanning Go services under '{self.root}'")
+        self.profiler = Profiler() if self.options.profile else NullProfiler()
+        by_dir = self._by_dir()
+
+        self._packages = {}
+        if self.reuse_trees:
+            # Watch mode: go.mod files may have come or gone since the last scan.
+            with self.profiler.phase("discover"):
+                self.modules = self._find_modules()
+        if self.options.since and self.reuse_trees:
+            # Watch mode: files edited since the last scan join the changed set.
+            self.changed = changed_files(self.root, self.options.since)
+        cache = self._open_cache()
+        pool = ThreadPoolExecutor(max_workers=self._workers())
+        parts: Dict[str, Report] = {}
+        dep_dirs: Dict[str, Set[str]] = {}
+        try:
+            # Queue every dirty file up front so parsing runs ahead of analysis.
+            plan = []
+            for d, files in by_dir.items():
+                with self.profiler.phase("cache"):
+                    hashes = (
+                        {self._rel(p): cache.checksum(p, self.fs) for p in files} if cache else {}
+                    )
+                    entry = cache.get(d, hashes, self.root) if cache else None
+                futures = [] if entry else [self._submit(pool, p, hashes) for p in files]
+                plan.append((d, len(files), hashes, entry, futures))
+
+            for d, count, hashes, entry, futures in plan:
+                if cancel is not None and cancel.is_set():
+                    logger.info("Scan cancelled")
+                    return
+                started = time.perf_counter()
+                if entry is not None:
+                    part = load_partial(entry)
+                    deps = entry.get("deps", {})
+                else:
+                    parsed = [fu.result() for fu in futures]
+                    if self.reuse_trees:
+                        self._keep_trees(parsed, hashes)
+                    part, deps = self._analyze_dir(parsed)
+                    if cache is not None:
+                        cache.update(d, hashes, {**dump_partial(part, d), "deps": deps})
+                # Cached findings keep analyzer defaults; the policy and the
+                # --since filter apply on the way out.
+                part.findings = self._reported(part.findings)
+                parts[d], dep_dirs[d] = part, {dir_of(rel) for rel in deps}
+                elapsed = time.perf_counter() - started
+                logger.bind(
+                    dir=d,
+                    files=count,
+                    findings=len(part.findings),
+                    cached=entry is not None,
+                    duration=round(elapsed, 3),
+                ).debug(f"Analyzed {d} ({count} files) in {elapsed * 1000:.0f}ms")
+                yield from events(part)
+
+            self._parts, self._deps = parts, dep_dirs
+            if self.reuse_trees:
+                current = {self._rel(p) for files in by_dir.values() for p in files}
+                self._trees = {k: v for k, v in self._trees.items() if k in current}
+            if cache is not None and self._full_scan:
+                # Entries for deleted directories must not be merged next time.
+                cache.retain(list(by_dir))
+                logger.info(f"Scan cache: {cache.hits} of {len(by_dir)} directories reused")
+        finally:
+            pool.shutdown(wait=False, cancel_futures=True)
+            if cache is not None:
+                with self.profiler.phase("cache"):
+                    cache.save()
+
+    def _by_dir(self) -> Dict[str, List[Path]]:
+        """Discovered files by directory, in discovery order."""
+        by_dir: Dict[str, List[Path]] = {}
+        with self.profiler.phase("discover"):
+            for p in self.discover():
+                by_dir.setdefault(dir_of(self._rel(p)), []).append(p)
+        return by_dir
+
+    def _reported(self, findings: List[Finding]) -> List[Finding]:
+        findings = self.policy.apply(findings)
+        if self.changed is None:
+            return findings
+        return [f for f in findings if f.file in self.changed]
+
+    def _submit(self, pool: ThreadPoolExecutor, path: Path, hashes: Dict[str, str]) -> Future:
+        kept = self._trees.get(self._rel(path)) if self.reuse_trees else None
+        if kept is not None and kept[0] == hashes.get(self._rel(path)):
+            done: Future = Future()
+            done.set_result(kept[1])
+            return done
+        return pool.submit(self._parse, path)
+
+    def _keep_trees(self, parsed: List[Union[GoFile, ScanError]], hashes: Dict[str, str]):
+        for gf in parsed:
+            if isinstance(gf, GoFile) and gf.rel in hashes:
+                self._trees[gf.rel] = (hashes[gf.rel], gf)
+
+    def _analyze_dir(
+        self, parsed: List[Union[GoFile, ScanError]]
+    ) -> Tuple[Report, Dict[str, str]]:
+        """The directory's partial report, plus hashes of other files its DTOs came from."""
+        part = Report(root=str(self.root))
+        dep_dirs: Set[str] = set()
+        packages = self._group_packages(parsed, part)
+        for pkg in packages:
+            if not pkg.name.endswith("_test"):
+                self._packages.setdefault(pkg.dir, pkg)
+        for pkg in packages:
+            with self.profiler.phase("extract"):
+                part.services.append(self._analyze(pkg, part, dep_dirs))
+            for name, analyzer in self.analyzers:
+                with self.profiler.analyzer(name):
+                    part.findings.extend(tagged(name, analyzer.analyze(pkg, part)))
+            # Analyzers may have followed imports of their own.
+            if pkg.resolver is not None:
+                dep_dirs.update(pkg.resolver.deps)
+        deps = {
+            gf.rel: ScanCache.checksum(gf.path, self.fs)
+            for d in sorted(dep_dirs)
+            for gf in self._packages[d].files
+        }
+        return part, deps
+
+    def _open_cache(self) -> Optional[ScanCache]:
+        if self._cache is not None:
+            self._cache.hits = 0
+            return self._cache
+        if not self.options.cache_dir and not self.reuse_trees:
+            return None
+        # Options that change what gets extracted are part of the cache key.
+        version = ":".join(
+            (
+                str(ANALYSIS_VERSION),
+                str(SCHEMA_VERSION),
+                ",".join(self.options.todo_markers),
+                str(self.options.max_complexity),
+                ",".join(self.options.auth_middleware),
+                ",".join(self.options.public_paths),
+                "\n".join(self.options.secret_allowlist),
+                "\n".join(self.options.forbid_imports),
+                ",".join(self.options.webhook_patterns),
+                str(self.options.callgraph_depth),
+                ",".join(name for name, _ in self.analyzers),
+                ",".join(self.modules.paths),
+            )
+        )
+        cache_dir = Path(self.options.cache_dir) if self.options.cache_dir else None
+        self._cache = ScanCache(cache_dir, version, self.fs)
+        self._cache.load_if_exists()
+        return self._cache
+
+    def _analyze(self, pkg: PackageInfo, report: Report, dep_dirs: Set[str]) -> Service:
+        owner = self.modules.owner(pkg.dir)
+        svc = Service(
+            name=service_name(pkg, self.root),
+            package=pkg.name,
+            dir=pkg.dir,
+            module=(owner.path if owner is not None else "") or None,
+            files=[f.rel for f in pkg.files],
+        )
+        todos = {f.rel: self.todos.detect(f) for f in pkg.files}
+        routes = self.routes.detect(pkg) + self.grpc.detect(pkg)
+        resolver = HandlerResolver(pkg)
+        middleware = MiddlewareResolver(pkg, resolver.resolves)
+        types = pkg.resolver = TypeResolver(pkg, self.modules, self._load_package)
+        queries = QueryFinder(pkg, resolver, types)
+        graph = CallGraphBuilder(queries.calls, self.options.callgraph_depth)
+        for route in routes:
+            if route.endpoint.protocol == "http":
+                # `v1 := r.Group("/v1"); v1.GET("/users", h)` serves `/v1/users`.
+                path = middleware.path(route)
+                if path != route.endpoint.path:
+                    route.endpoint.path = path
+                    route.endpoint.path_params = path_params(path)
+                route.endpoint.middleware = middleware.chain(route)
+            found = resolver.resolve(route)
+            if found is None:
+                continue
+            f, fn = found
+            ep = route.endpoint
+            body = fn.field("body")
+            if body is not None:
+                request, response = find_dtos(body, fn)
+                ep.request = ep.request or request
+                ep.response = ep.response or response
+            with self.profiler.phase("types"):
+                ep.request = types.canonical(ep.request, f)
+                ep.response = types.canonical(ep.response, f)
+            ep.complexity = cyclomatic(fn)
+            ep.status_codes = status_codes(fn)
+            ep.handler_file = f.rel
+            ep.handler_line = fn.line
+            ep.todos = [t for t in todos[f.rel] if fn.line <= t.line <= fn.end_line]
+            if ep.path_params:
+                link_params(ep.path_params, fn)
+            ep.db_queries = queries.queries(f, fn)
+            ep.db_access = list(dict.fromkeys(q.summary for q in ep.db_queries))
+            if self.options.callgraph_depth > 0:
+                if fn.type == "func_literal":
+                    root = ep.handler or func_label(fn)
+                else:
+                    root = f"{pkg.name}.{func_label(fn)}"
+                ep.call_graph = graph.edges(f, fn, root)
+        svc.endpoints = [r.endpoint for r in routes]
+        svc.handlers = self.handlers.detect(pkg)
+        files = {f.rel: f for f in pkg.files}
+        with self.profiler.phase("types"):
+            for h in svc.handlers:
+                h.request = types.canonical(h.request, files[h.file])
+                h.response = types.canonical(h.response, files[h.file])
+        listen = self.listen.detect(pkg)
+        if listen is not None:
+            svc.listen_addr, svc.listen_file, svc.listen_line = listen
+        svc.outbound_calls = self.outbound.detect(pkg)
+        svc.env_vars = self.env.detect(pkg)
+        with self.profiler.phase("types"):
+            svc.structs = sorted(types.reachable(), key=lambda st: (st.name, st.package))
+        dep_dirs.update(types.deps)
+        for items in todos.values():
+            report.todos.extend(items)
+        return svc
+
+    def _load_package(self, rel_dir: str) -> Optional[PackageInfo]:
+        """Parse the (non-test) package in a directory of the module, once per scan."""
+        if rel_dir in self._packages:
+            return self._packages[rel_dir]
+        d = self.root / rel_dir
+        names = self.fs.listdir(d) if self.fs.is_dir(d) else []
+        paths = [
+            d / n
+            for n in names
+            if n.endswith(".go") and self.fs.is_file(d / n) and self._buildable(d / n)
+        ]
+        parsed = [gf for gf in map(self._parse, paths) if isinstance(gf, GoFile)]
+        names = sorted({gf.package for gf in parsed if not gf.package.endswith("_test")})
+        pkg = None
+        if names:
+            pkg = PackageInfo(dir=rel_dir, name=names[0])
+            pkg.files = [gf for gf in parsed if gf.package == names[0]]
+        else:
+            logger.debug(f"No Go package to resolve types from in {rel_dir}")
+        self._packages[rel_dir] = pkg
+        return pkg
+
+    def _find_modules(self) -> ModuleSet:
+        if self.fs.is_file(self.target):
+            found = [self.module] if self.module is not None else []
+        else:
+            found = find_modules(self.root, self.module, self.fs)
+        return ModuleSet(found, self.root, self.fs)
+
+    @property
+    def _full_scan(self) -> bool:
+        return (
+            self.patterns is None
+            and self.fs.is_dir(self.target)
+            and self.fs.samefile(self.target, self.root)
+        )
+
+    def discover(self) -> List[Path]:
+        """The Go files a scan would read, after ignore rules and build constraints."""
+        if self.patterns is None and self.fs.is_file(self.target):
+            return [self.target] if self._buildable(self.target) else []
+        rules = IgnoreRules.for_root(self.root, self.options.exclude, self.fs)
+        if self.patterns is None:
+            return sorted(self._walk(self.target, rules, None))
+        found = set()
+        for pattern in self.patterns:
+            matched = self._walk(self.root / pattern.start, rules, pattern)
+            if not matched:
+                logger.warning(f"Pattern {pattern.pattern!r} matched no Go files")
+            found.update(matched)
+        return sorted(found)
+
+    def walk(self) -> Iterator[Tuple[Path, List[str], bool]]:
+        """(directory, file names, matched) for every directory discovery descends into.
+
+        `matched` says whether the directory's Go files are scanned; a package
+        pattern also walks the directories leading to its matches. Nothing is
+        read, so this is cheap to repeat, e.g. to see what to watch.
+        """
+        if self.patterns is None and self.fs.is_file(self.target):
+            yield self.target.parent, [self.target.name], True
+            return
+        rules = IgnoreRules.for_root(self.root, self.options.exclude, self.fs)
+        if self.patterns is None:
+            yield from self._dirs(self.target, rules, None)
+            return
+        for pattern in self.patterns:
+            yield from self._dirs(self.root / pattern.start, rules, pattern)
+
+    def _dirs(
+        self, start: Path, rules: IgnoreRules, pattern: Optional[PackagePattern]
+    ) -> Iterator[Tuple[Path, List[str], bool]]:
+        for dirpath, dirnames, filenames in self.fs.walk(start):
+            base = Path(dirpath)
+            # Prune ignored directories in place so they are never descended into.
+            dirnames[:] = sorted(
+                d
+                for d in dirnames
+                if not rules.ignored(self._rel(base / d), is_dir=True)
+                and not (pattern and pattern.prunes(self._rel(base / d)))
+            )
+            if pattern is not None and not pattern.wildcard:
+                dirnames[:] = []
+            yield base, filenames, pattern is None or pattern.matches(self._rel(base))
+
+    def _walk(
+        self, start: Path, rules: IgnoreRules, pattern: Optional[PackagePattern]
+    ) -> List[Path]:
+        found: List[Path] = []
+        for base, filenames, matched in self._dirs(start, rules, pattern):
+            if not matched:
+                continue
+            for name in filenames:
+                p = base / name
+                if (
+                    name.endswith(".go")
+                    and not rules.ignored(self._rel(p), is_dir=False)
+                    and self._buildable(p)
+                ):
+                    found.append(p)
+        return found
+
+    def _buildable(self, path: Path) -> bool:
+        try:
+            ok = self.build.matches(path, self.fs)
+        except (OSError, ValueError) as e:
+            # Let the parse step report unreadable files; keep malformed constraints.
+            rel = self._rel(path)
+            logger.bind(file=rel).warning(f"Cannot evaluate build constraints of {rel}: {e}")
+            return True
+        if not ok:
+            logger.debug(f"Skipping {self._rel(path)}: excluded by build constraints")
+        return ok
+
+    def _rel(self, path: Path) -> str:
+        return Path(os.path.relpath(path, self.root)).as_posix()
+
+    @property
+    def parser(self) -> ParserEngine:
+        # tree-sitter parsers are not thread-safe; keep one per worker.
+        engine = getattr(self._local, "parser", None)
+        if engine is None:
+            engine = self._local.parser = ParserEngine(get_parser("go"))
+        return engine
+
+    def _parse(self, path: Path) -> Union[GoFile, ScanError]:
+        rel = self._rel(path)
+        started = time.perf_counter()
+        try:
+            with self.profiler.phase("parse"):
+                source = self.fs.read_bytes(path)
+                root = self.parser.parse(source)
+            self.profiler.parsed(len(source))
+        except Exception as e:
+            logger.bind(file=rel).error(f"Failed to parse {rel}: {e}")
+            # OSError's text quotes the absolute path; `file` already says which one.
+            message = e.strerror if isinstance(e, OSError) and e.strerror else str(e)
+            return ScanError(file=rel, message=message)
+        logger.bind(file=rel, duration=round(time.perf_counter() - started, 4)).trace(
+            f"Parsed {rel}"
+        )
+        clause = next((c for c in root.named_children if c.type == "package_clause"), None)
+        if clause is None or not clause.named_children:
+            return ScanError(file=rel, message="missing package clause")
+        ident = clause.named_children[0].text
+        return GoFile(path=path, rel=rel, package=ident, root=root)
+
+    def _workers(self) -> int:
+        return self.options.concurrency or os.cpu_count() or 1
+
+    def _group_packages(
+        self, parsed: List[Union[GoFile, ScanError]], report: Report
+    ) -> List[PackageInfo]:
+        packages: Dict[Tuple[str, str], PackageInfo] = {}
+        for gf in parsed:
+            if isinstance(gf, ScanError):
+                report.errors.append(gf)
+                continue
+            if gf.root.has_error:
+                logger.bind(file=gf.rel).warning(
+                    f"Syntax errors in {gf.rel}; results may be partial"
+                )
+                report.errors.append(ScanError(file=gf.rel, message="syntax error"))
+            d = dir_of(gf.rel)
+            key = (d, gf.package)
+            if key not in packages:
+                packages[key] = PackageInfo(dir=d, name=gf.package)
+            packages[key].files.append(gf)
+        return [packages[k] for k in sorted(packages)]
+
+
+def replace(report: Report, fresh: Report):
+    """Swap `fresh` results into `report` under its lock."""
+    with report.lock:
+        report.services = fresh.services
+        report.todos = fresh.todos
+        report.findings = fresh.findings
+        report.errors = fresh.errors
+        report.metrics = fresh.metrics
+        report.summary = fresh.summary
+        report.hash = fresh.hash
+        report.scanner = fresh.scanner
+        if report.modules is not None:
+            report.group_by_module()
+
+
+def dir_of(rel: str) -> str:
+    return Path(rel).parent.as_posix()
+
+
+def dump_partial(report: Report, directory: str) -> Dict[str, Any]:
+    """The slice of `report` contributed by the files of one directory.
+
+    Findings all belong to the directory whose analysis produced them, even
+    those pointing into packages its code calls (an N+1 query in a repository).
+    """
+    return {
+        "services": [asdict(s) for s in report.services if s.dir == directory],
+        "todos": [asdict(t) for t in report.todos if dir_of(t.file) == directory],
+        "findings": [asdict(f) for f in report.findings],
+        "errors": [asdict(e) for e in report.errors if dir_of(e.file) == directory],
+    }
+
+
+def load_partial(entry: Dict[str, Any]) -> Report:
+    return Report(
+        root="",
+        services=[from_dict(Service, s) for s in entry.get("services", [])],
+        todos=[from_dict(Todo, t) for t in entry.get("todos", [])],
+        findings=[from_dict(Finding, f) for f in entry.get("findings", [])],
+        errors=[from_dict(ScanError, e) for e in entry.get("errors", [])],
+    )
+
+
+def events(part: Report) -> Iterator[ScanEvent]:
+    for svc in part.services:
+        yield ScanEvent("service", svc)
+        for ep in svc.endpoints:
+            yield ScanEvent("endpoint", ep, service=svc.name)
+    for t in part.todos:
+        yield ScanEvent("todo", t)
+    for f in part.findings:
+        yield ScanEvent("finding", f)
+    for e in part.errors:
+        yield ScanEvent("error", e)
+
+
+def service_name(pkg: PackageInfo, root: Path) -> str:
+    """`main` packages are named after their directory, others after the package."""
+    if pkg.name == "main":
+        base = Path(pkg.dir).name
+        if not base:
+            base = (root.parent if root.is_file() else root).resolve().name
+        return base or pkg.name
+    return pkg.name
+
+
+def tagged(name: str, findings: List[Finding]) -> List[Finding]:
+    """Record which analyzer reported `findings`."""
+    for fd in findings:
+        fd.analyzer = name
+    return findings
+
+
+def scan(root: str, options: Optional[ScanOptions] = None) -> Report:
+    return scan_fs(OS_FS, root, options)
+
+
+def scan_fs(
+    fs: Union[FileSystem, Mapping[str, Union[str, bytes]]],
+    root: str = ".",
+    options: Optional[ScanOptions] = None,
+) -> Report:
+    """Scan `root` inside `fs`: a FileSystem, or a mapping of paths to file contents.
+
+    A mapping is scanned in memory, e.g. generated code before it is written
+    out: `scan_fs({"go.mod": "module x", "main.go": src})`.
+    """
+    if not isinstance(fs, FileSystem):
+        fs = MemoryFileSystem(fs)
+    return GoScanner(str(fs.path(root)), options, fs=fs).scan()
+
+
+def scan_packages(
+    patterns: List[str], root: str = ".", options: Optional[ScanOptions] = None
+) -> Report:
+    """Scan only the packages matching go-style `patterns`, relative to `root`."""
+    return GoScanner(root, options, patterns).scan()
+
+
+def scan_stream(
+    root: str,
+    options: Optional[ScanOptions] = None,
+    cancel: Optional[threading.Event] = None,
+) -> Iterator[ScanEvent]:
+    return GoScanner(root, options).stream(cancel)
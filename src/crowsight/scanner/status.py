@@ -0,0 +1,179 @@
+# src/crowsight/scanner/status.py
+
+from typing import List, Optional, Set
+
+from ..core.node import NodeWrapper
+from .goast import call_args, call_target, walk_body
+from .handlers import func_params
+
+# net/http's Status constants.
+STATUS_CODES = {
+    "StatusContinue": 100,
+    "StatusSwitchingProtocols": 101,
+    "StatusProcessing": 102,
+    "StatusEarlyHints": 103,
+    "StatusOK": 200,
+    "StatusCreated": 201,
+    "StatusAccepted": 202,
+    "StatusNonAuthoritativeInfo": 203,
+    "StatusNoContent": 204,
+    "StatusResetContent": 205,
+    "StatusPartialContent": 206,
+    "StatusMultiStatus": 207,
+    "StatusAlreadyReported": 208,
+    "StatusIMUsed": 226,
+    "StatusMultipleChoices": 300,
+    "StatusMovedPermanently": 301,
+    "StatusFound": 302,
+    "StatusSeeOther": 303,
+    "StatusNotModified": 304,
+    "StatusUseProxy": 305,
+    "StatusTemporaryRedirect": 307,
+    "StatusPermanentRedirect": 308,
+    "StatusBadRequest": 400,
+    "StatusUnauthorized": 401,
+    "StatusPaymentRequired": 402,
+    "StatusForbidden": 403,
+    "StatusNotFound": 404,
+    "StatusMethodNotAllowed": 405,
+    "StatusNotAcceptable": 406,
+    "StatusProxyAuthRequired": 407,
+    "StatusRequestTimeout": 408,
+    "StatusConflict": 409,
+    "StatusGone": 410,
+    "StatusLengthRequired": 411,
+    "StatusPreconditionFailed": 412,
+    "StatusRequestEntityTooLarge": 413,
+    "StatusRequestURITooLong": 414,
+    "StatusUnsupportedMediaType": 415,
+    "StatusRequestedRangeNotSatisfiable": 416,
+    "StatusExpectationFailed": 417,
+    "StatusTeapot": 418,
+    "StatusMisdirectedRequest": 421,
+    "StatusUnprocessableEntity": 422,
+    "StatusLocked": 423,
+    "StatusFailedDependency": 424,
+    "StatusTooEarly": 425,
+    "StatusUpgradeRequired": 426,
+    "StatusPreconditionRequired": 428,
+    "StatusTooManyRequests": 429,
+    "StatusRequestHeaderFieldsTooLarge": 431,
+    "StatusUnavailableForLegalReasons": 451,
+    "StatusInternalServerError": 500,
+    "StatusNotImplemented": 501,
+    "StatusBadGateway": 502,
+    "StatusServiceUnavailable": 503,
+    "StatusGatewayTimeout": 504,
+    "StatusHTTPVersionNotSupported": 505,
+    "StatusVariantAlsoNegotiates": 506,
+    "StatusInsufficientStorage": 507,
+    "StatusLoopDetected": 508,
+    "StatusNotExtended": 510,
+    "StatusNetworkAuthenticationRequired": 511,
+}
+# Calls that set the status from a plain integer argument (gin, echo: `c.JSON(200, v)`).
+STATUS_ARG_METHODS = {
+    "WriteHeader": 0,
+    "Error": 2,
+    "Redirect": 3,
+    "JSON": 0,
+    "IndentedJSON": 0,
+    "XML": 0,
+    "String": 0,
+    "Status": 0,
+    "NoContent": 0,
+    "AbortWithStatus": 0,
+    "AbortWithStatusJSON": 0,
+    "SendStatus": 0,
+}
+# net/http helpers that send a fixed status.
+FIXED_STATUS = {"http.NotFound": 404}
+# Calls that send the writer an error response, not the handler's success body.
+ERROR_WRITERS = ("http.Error", "http.Redirect", "http.NotFound")
+
+
+def status_value(node: NodeWrapper) -> Optional[int]:
+    """`http.StatusNotFound` or an integer literal in the HTTP status range."""
+    if node.type == "selector_expression":
+        operand, name = node.field("operand"), node.field("field")
+        if operand is not None and operand.text == "http" and name is not None:
+            return STATUS_CODES.get(name.text)
+        return None
+    if node.type == "int_literal" and node.text.isdigit() and 100 <= int(node.text) <= 599:
+        return int(node.text)
+    return None
+
+
+def status_codes(fn: NodeWrapper) -> List[int]:
+    """Statuses a handler can respond with, sorted.
+
+    Collects `w.WriteHeader(code)`, `http.Error(w, msg, code)`, framework
+    calls such as `c.JSON(code, v)`, and any `http.StatusXxx` passed to a
+    call. A net/http handler also answers with the implicit 200 when some
+    path sets no status, or a body is written before a status is set.
+    """
+    body = fn.field("body")
+    if body is None:
+        return []
+    writer = next((n for n, t in func_params(fn) if t == "http.ResponseWriter"), None)
+    codes: Set[int] = set()
+    # Calls that set the status, and calls that write a body with whatever status is set.
+    explicit: List[NodeWrapper] = []
+    writes: List[NodeWrapper] = []
+    for call in walk_body(body):
+        if call.type != "call_expression":
+            continue
+        func = call.field("function")
+        target = func.text if func is not None else ""
+        receiver, name = call_target(call)
+        args = call_args(call)
+        found = {c for c in map(status_value_arg, args) if c is not None}
+        index = STATUS_ARG_METHODS.get(name)
+        if index is not None and index < len(args):
+            code = status_value(args[index])
+            if code is not None:
+                found.add(code)
+        if target in FIXED_STATUS:
+            found.add(FIXED_STATUS[target])
+        codes |= found
+        if not writer:
+            continue
+        if found or target in ERROR_WRITERS or (receiver == writer and name == "WriteHeader"):
+            explicit.append(call)
+        elif (receiver == writer and name in ("Write", "WriteString")) or any(
+            a.type == "identifier" and a.text == writer for a in args
+        ):
+            writes.append(call)
+    if writer and (
+        # A path that sets no status, or a body written before any status is.
+        not any(enclosing_block(c) == body for c in explicit)
+        or any(not _after_status(w, explicit) for w in writes)
+    ):
+        codes.add(200)
+    return sorted(codes)
+
+
+def enclosing_block(node: NodeWrapper) -> Optional[NodeWrapper]:
+    p = node.parent
+    while p is not None and p.type != "block":
+        p = p.parent
+    return p
+
+
+def status_value_arg(node: NodeWrapper) -> Optional[int]:
+    # Only named constants count anywhere; bare integers are too common.
+    return status_value(node) if node.type == "selector_expression" else None
+
+
+def _after_status(write: NodeWrapper, explicit: List[NodeWrapper]) -> bool:
+    """Whether a status set earlier in a block enclosing `write` always runs before it."""
+    for call in explicit:
+        block = enclosing_block(call)
+        if call.line > write.line or block is None:
+            continue
+        p = write.parent
+        while p is not None and p != block:
+            p = p.parent
+        if p is not None:
+            return True
+    return False
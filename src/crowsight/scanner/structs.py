@@ -0,0 +1,98 @@
+# src/crowsight/scanner/structs.py
+
+import re
+from typing import Dict, Optional
+
+from ..report.models import Struct, StructField
+from .goast import unquote
+from .package import PackageInfo
+
+_TAG_RE = re.compile(r'(\w+):"((?:[^"\\]|\\.)*)"')
+
+
+def parse_tag(raw: str) -> Dict[str, str]:
+    """`json:"id,omitempty" db:"id"` → {"json": "id,omitempty", "db": "id"}."""
+    return dict(_TAG_RE.findall(unquote(raw)))
+
+
+def base_type(type_text: str) -> str:
+    """Strip pointers and slices: `[]*pkg.T` → `pkg.T`."""
+    t = type_text.strip()
+    while True:
+        if t.startswith("*"):
+            t = t[1:]
+        elif t.startswith("[]"):
+            t = t[2:]
+        else:
+            return t
+
+
+class StructCollector:
+    """Collects package-level struct declarations with their json tags."""
+
+    def collect(self, pkg: PackageInfo) -> Dict[str, Struct]:
+        structs: Dict[str, Struct] = {}
+        for f in pkg.files:
+            for decl in f.root.named_children:
+                if decl.type != "type_declaration":
+                    continue
+                for spec in decl.named_children:
+                    if spec.type not in ("type_spec", "type_alias"):
+                        continue
+                    name = spec.field("name")
+                    typ = spec.field("type")
+                    if name is None or typ is None or typ.type != "struct_type":
+                        continue
+                    structs[name.text] = Struct(
+                        name=name.text,
+                        package=pkg.name,
+                        file=f.rel,
+                        line=spec.line,
+                        fields=self._fields(typ),
+                    )
+        return structs
+
+    def _fields(self, struct_type):
+        fields = []
+        body = next(
+            (c for c in struct_type.named_children if c.type == "field_declaration_list"),
+            None,
+        )
+        if body is None:
+            return fields
+        for decl in body.named_children:
+            if decl.type != "field_declaration":
+                continue
+            typ = decl.field("type")
+            tag = decl.field("tag")
+            tags = parse_tag(tag.text) if tag else {}
+            json_tag: Optional[str] = tags.get("json")
+            json_name, _, opts = (json_tag or "").partition(",")
+            names = decl.fields("name")
+            type_text = typ.text if typ else ""
+            pointer = any(c.type == "*" for c in decl.children)
+            if not names:
+                # Embedded field: named after its type.
+                fields.append(
+                    StructField(
+                        name=base_type(type_text).split(".")[-1],
+                        type=("*" if pointer else "") + type_text,
+                        json=json_name if json_tag is not None else None,
+                        omitempty="omitempty" in opts.split(","),
+                        embedded=True,
+                        line=decl.line,
+                    )
+                )
+                continue
+            for n in names:
+                fields.append(
+                    StructField(
+                        name=n.text,
+                        type=type_text,
+                        json=json_name if json_tag is not None else None,
+                        omitempty="omitempty" in opts.split(","),
+                        line=decl.line,
+                    )
+                )
+        return fields
+
@@ -0,0 +1,33 @@
+# src/crowsight/scanner/todos.py
+
+import re
+from typing import List, Sequence
+
+from ..report.models import Todo
+from .package import GoFile
+
+DEFAULT_MARKERS = ("TODO", "FIXME", "HACK", "XXX")
+
+
+class TodoDetector:
+    """Collects marker comments such as `// TODO: ...` from parsed files."""
+
+    def __init__(self, markers: Sequence[str] = DEFAULT_MARKERS):
+        alts = "|".join(re.escape(m) for m in markers)
+        # Matches `TODO: text`, `TODO(owner): text` and bare `TODO text`.
+        self._re = re.compile(rf"\b({alts})\b(?:\([^)]*\))?:?\s*(.*)")
+
+    def detect(self, f: GoFile) -> List[Todo]:
+        todos: List[Todo] = []
+        for node in f.root.descendants():
+            if node.type != "comment":
+                continue
+            for offset, raw in enumerate(node.text.splitlines()):
+                m = self._re.search(raw)
+                if not m:
+                    continue
+                text = m.group(2).rstrip().removesuffix("*/").rstrip()
+                todos.append(
+                    Todo(file=f.rel, line=node.line + offset, marker=m.group(1), text=text)
+                )
+        return todos
@@ -0,0 +1,176 @@
+# src/crowsight/scanner/types.py
+
+from typing import Callable, Dict, List, Optional, Set, Tuple
+
+from ..core.node import NodeWrapper
+from ..report.models import Struct
+from .goast import unquote
+from .module import ModuleSet
+from .package import GoFile, PackageInfo
+from .structs import StructCollector, base_type
+
+# Loads the package in a directory (relative to the scan root), or None.
+PackageLoad = Callable[[str], Optional[PackageInfo]]
+
+# How many `type A = B` / `type A B` hops to follow.
+MAX_ALIAS_DEPTH = 8
+
+
+def named_parts(type_text: str) -> Tuple[str, str]:
+    """Split `[]*dto.User` into its `[]*` prefix and the named type `dto.User`."""
+    name = base_type(type_text)
+    return type_text.strip()[: len(type_text.strip()) - len(name)], name
+
+
+def element_type(type_text: str) -> str:
+    """Named type behind pointers, slices, arrays and map values."""
+    t = base_type(type_text)
+    while True:
+        if t.startswith("map[") and "]" in t:
+            t = base_type(t[t.index("]") + 1 :])
+        elif t.startswith("[") and "]" in t:
+            t = base_type(t[t.index("]") + 1 :])
+        else:
+            return t
+
+
+class TypeResolver:
+    """Resolves DTO type names across the packages of the scanned modules.
+
+    Without a type checker this follows imports syntactically: `dto.User` is
+    looked up in the package that `dto` imports, in whichever module under
+    the scan root provides it, and `type A = B` or `type A B` declarations
+    are followed to the struct they name. Anything that cannot be followed
+    (standard library, modules outside the root, generics) keeps its name
+    as written.
+    """
+
+    def __init__(self, pkg: PackageInfo, modules: ModuleSet, load: PackageLoad):
+        self.pkg = pkg
+        self.modules = modules
+        self.load = load
+        # Structs reached so far, keyed by (package dir, name).
+        self.used: Dict[Tuple[str, str], Tuple[PackageInfo, Struct]] = {}
+        # Directories other than pkg's that resolution (or an import lookup) depended on.
+        self.deps: Set[str] = set()
+        self._decls: Dict[str, Dict[str, Tuple[GoFile, NodeWrapper]]] = {}
+        self._structs: Dict[str, Dict[str, Struct]] = {}
+        self._imports: Dict[str, Dict[str, PackageInfo]] = {}
+
+    def canonical(self, type_text: Optional[str], f: GoFile) -> Optional[str]:
+        """`type_text` with its named type replaced by the struct it resolves to.
+
+        Local structs stay unqualified; structs from other packages are
+        qualified by package name, which is how Report.find_struct looks them up.
+        """
+        if not type_text:
+            return type_text
+        prefix, name = named_parts(type_text)
+        found = self.resolve(name, self.pkg, f)
+        if found is None:
+            return type_text
+        pkg, st = found
+        self._use(pkg, st)
+        if pkg.dir == self.pkg.dir:
+            return prefix + st.name
+        return f"{prefix}{pkg.name}.{st.name}"
+
+    def resolve(
+        self, name: str, pkg: PackageInfo, f: GoFile, depth: int = 0
+    ) -> Optional[Tuple[PackageInfo, Struct]]:
+        """The struct a named type in file `f` of `pkg` denotes, if it is one."""
+        qualifier, _, base = name.rpartition(".")
+        if qualifier:
+            target = self.imported(qualifier, f)
+            if target is None:
+                return None
+        else:
+            target = pkg
+        decl = self._declarations(target).get(base)
+        if decl is None:
+            return None
+        gf, spec = decl
+        typ = spec.field("type")
+        if typ is None:
+            return None
+        if typ.type == "struct_type":
+            st = self._struct_map(target).get(base)
+            return (target, st) if st is not None else None
+        if typ.type in ("type_identifier", "qualified_type") and depth < MAX_ALIAS_DEPTH:
+            return self.resolve(typ.text, target, gf, depth + 1)
+        return None
+
+    def reachable(self) -> List[Struct]:
+        """Every struct used so far plus those reachable through their fields."""
+        stack = list(self.used.values())
+        while stack:
+            pkg, st = stack.pop()
+            gf = next((g for g in pkg.files if g.rel == st.file), None)
+            if gf is None:
+                continue
+            for fld in st.fields:
+                found = self.resolve(element_type(fld.type), pkg, gf)
+                if found is not None and self._use(*found):
+                    stack.append(found)
+        return [st for _, st in self.used.values()]
+
+    def _use(self, pkg: PackageInfo, st: Struct) -> bool:
+        key = (pkg.dir, st.name)
+        if key in self.used:
+            return False
+        self.used[key] = (pkg, st)
+        if pkg.dir != self.pkg.dir:
+            self.deps.add(pkg.dir)
+        return True
+
+    def _declarations(self, pkg: PackageInfo) -> Dict[str, Tuple[GoFile, NodeWrapper]]:
+        decls = self._decls.get(pkg.dir)
+        if decls is None:
+            decls = self._decls[pkg.dir] = {}
+            for f in pkg.files:
+                for decl in f.root.named_children:
+                    if decl.type != "type_declaration":
+                        continue
+                    for spec in decl.named_children:
+                        name = spec.field("name")
+                        if spec.type in ("type_spec", "type_alias") and name is not None:
+                            decls[name.text] = (f, spec)
+        return decls
+
+    def _struct_map(self, pkg: PackageInfo) -> Dict[str, Struct]:
+        if pkg.dir not in self._structs:
+            self._structs[pkg.dir] = StructCollector().collect(pkg)
+        return self._structs[pkg.dir]
+
+    def imported(self, qualifier: str, f: GoFile) -> Optional[PackageInfo]:
+        """The module package that `qualifier` names in file `f`, if it is one."""
+        imports = self._imports.get(f.rel)
+        if imports is None:
+            imports = self._imports[f.rel] = self._file_imports(f)
+        pkg = imports.get(qualifier)
+        if pkg is not None and pkg.dir != self.pkg.dir:
+            self.deps.add(pkg.dir)
+        return pkg
+
+    def package_at(self, rel_dir: str) -> Optional[PackageInfo]:
+        """The module package in a directory (relative to the scan root), as a dependency."""
+        pkg = self.load(rel_dir)
+        if pkg is not None and pkg.dir != self.pkg.dir:
+            self.deps.add(pkg.dir)
+        return pkg
+
+    def _file_imports(self, f: GoFile) -> Dict[str, PackageInfo]:
+        """Name -> package for the file's imports that live in the scanned modules."""
+        found: Dict[str, PackageInfo] = {}
+        for spec in f.root.descendants():
+            if spec.type != "import_spec":
+                continue
+            alias, path = spec.field("name"), spec.field("path")
+            rel_dir = self.modules.import_dir(unquote(path.text)) if path is not None else None
+            if rel_dir is None or (alias is not None and alias.text in ("_", ".")):
+                continue
+            pkg = self.load(rel_dir)
+            if pkg is not None:
+                # Without an alias the package clause, not the path, names the import.
+                found[alias.text if alias is not None else pkg.name] = pkg
+        return found
@@ -0,0 +1,277 @@
+# src/crowsight/scanner/watch.py
+
+import ctypes
+import ctypes.util
+import os
+from pathlib import Path
+import select
+import struct
+import sys
+import threading
+from typing import Callable, Dict, Optional, Set, Tuple
+
+from loguru import logger
+
+from ..config import CONFIG_FILES
+from ..report.diff import ReportDiff, diff
+from ..report.models import Report
+from .ignore import IGNORE_FILE
+from .scanner import GoScanner
+
+DEFAULT_DEBOUNCE = 0.3
+# How often the polling monitor stats the watched files.
+DEFAULT_INTERVAL = 1.0
+# Files besides .go sources whose edits change a scan.
+WATCHED_NAMES = ("go.mod", "go.work", IGNORE_FILE, *CONFIG_FILES)
+
+Snapshot = Dict[Path, Tuple[int, int]]
+OnReport = Callable[[Report, Optional[ReportDiff]], None]
+# Builds a scanner with the options of a just-edited config file; None keeps the old one.
+Reload = Callable[[], Optional[GoScanner]]
+
+
+def watch_set(scanner: GoScanner) -> Tuple[Set[Path], Set[Path]]:
+    """The directories to watch, and the files in them whose edits change a scan.
+
+    Build constraints are not read: editing a file's header can bring it
+    into the build. The root and module roots are watched for ignore,
+    go.mod and config files that do not exist yet.
+    """
+    dirs: Set[Path] = set()
+    files: Set[Path] = set()
+    for base, names, matched in scanner.walk():
+        dirs.add(base)
+        files.update(base / n for n in names if n in WATCHED_NAMES)
+        if matched:
+            files.update(base / n for n in names if n.endswith(".go"))
+    roots = {scanner.root, *(m.root for m in scanner.modules.modules)}
+    if scanner.module is not None:
+        roots.add(scanner.module.root)
+    for d in roots:
+        dirs.add(Path(d))
+        files.update(Path(d) / n for n in WATCHED_NAMES)
+    return dirs, files
+
+
+class PollingMonitor:
+    """Notices changes by comparing file metadata; nothing is read.
+
+    Directories are stat'ed too, and only re-listed when one of them
+    changed, so files that come and go are noticed without walking the
+    tree on every poll.
+    """
+
+    def __init__(self, scanner: GoScanner):
+        self.refresh(scanner)
+
+    def refresh(self, scanner: GoScanner):
+        self.scanner = scanner
+        self.dirs, self.files = watch_set(scanner)
+        self.seen = self._stat()
+
+    def changes(self, timeout: float, stop: threading.Event) -> Optional[Set[Path]]:
+        """Files changed within `timeout`; None once `stop` is set."""
+        if stop.wait(timeout):
+            return None
+        current = self._stat()
+        if any(current.get(d) != self.seen.get(d) for d in self.dirs):
+            self.dirs, self.files = watch_set(self.scanner)
+            current = self._stat()
+        changed = {
+            p
+            for p in current.keys() | self.seen.keys()
+            if p not in self.dirs and current.get(p) != self.seen.get(p)
+        }
+        self.seen = current
+        return changed
+
+    def close(self):
+        pass
+
+    def _stat(self) -> Snapshot:
+        snap: Snapshot = {}
+        for p in self.dirs | self.files:
+            try:
+                st = p.stat()
+            except OSError:
+                continue
+            # A directory's size is meaningless; its mtime moves when entries are added.
+            snap[p] = (st.st_mtime_ns, 0 if p in self.dirs else st.st_size)
+        return snap
+
+
+# From <sys/inotify.h>.
+IN_MODIFY = 0x002
+IN_ATTRIB = 0x004
+IN_CLOSE_WRITE = 0x008
+IN_MOVED_FROM = 0x040
+IN_MOVED_TO = 0x080
+IN_CREATE = 0x100
+IN_DELETE = 0x200
+IN_Q_OVERFLOW = 0x4000
+IN_IGNORED = 0x8000
+IN_ISDIR = 0x40000000
+IN_CLOEXEC = 0o2000000
+IN_NONBLOCK = 0o4000
+IN_MASK = (
+    IN_MODIFY | IN_ATTRIB | IN_CLOSE_WRITE | IN_MOVED_FROM | IN_MOVED_TO | IN_CREATE | IN_DELETE
+)
+EVENT = struct.Struct("iIII")
+
+
+class InotifyMonitor:
+    """Linux file events: one inotify watch per directory of the watch set.
+
+    A new or removed subdirectory counts as a change; the rescan finds its
+    files, and the refresh after it watches it.
+    """
+
+    # How often a wait checks whether the watcher was stopped.
+    STOP_CHECK = 0.1
+
+    def __init__(self, scanner: GoScanner):
+        self._libc = ctypes.CDLL(ctypes.util.find_library("c") or "libc.so.6", use_errno=True)
+        self.fd = self._libc.inotify_init1(IN_NONBLOCK | IN_CLOEXEC)
+        if self.fd < 0:
+            errno = ctypes.get_errno()
+            raise OSError(errno, os.strerror(errno))
+        self.watches: Dict[int, Path] = {}
+        self.refresh(scanner)
+
+    def refresh(self, scanner: GoScanner):
+        dirs, self.files = watch_set(scanner)
+        for wd, d in list(self.watches.items()):
+            if d not in dirs:
+                self._libc.inotify_rm_watch(self.fd, wd)
+                del self.watches[wd]
+        watched = set(self.watches.values())
+        for d in sorted(dirs - watched):
+            wd = self._libc.inotify_add_watch(self.fd, os.fsencode(d), IN_MASK)
+            if wd >= 0:
+                self.watches[wd] = d
+
+    def changes(self, timeout: float, stop: threading.Event) -> Optional[Set[Path]]:
+        """Files changed within `timeout`, returned on the first relevant event."""
+        remaining = timeout
+        while not stop.is_set():
+            if remaining <= 0:
+                return set()
+            wait = min(self.STOP_CHECK, remaining)
+            remaining -= wait
+            ready, _, _ = select.select([self.fd], [], [], wait)
+            if ready:
+                changed = self._read()
+                if changed:
+                    return changed
+        return None
+
+    def close(self):
+        os.close(self.fd)
+
+    def _read(self) -> Set[Path]:
+        try:
+            data = os.read(self.fd, 65536)
+        except BlockingIOError:
+            return set()
+        changed: Set[Path] = set()
+        offset = 0
+        while offset < len(data):
+            wd, mask, _, size = EVENT.unpack_from(data, offset)
+            start = offset + EVENT.size
+            name = os.fsdecode(data[start : start + size].rstrip(b"\0"))
+            offset = start + size
+            if mask & IN_Q_OVERFLOW:
+                # Events were dropped; rescan to be safe.
+                changed.update(self.watches.values())
+                continue
+            d = self.watches.get(wd)
+            if mask & IN_IGNORED:
+                self.watches.pop(wd, None)
+                continue
+            if d is None or not name:
+                continue
+            path = d / name
+            relevant = name.endswith(".go") or name in WATCHED_NAMES or path in self.files
+            if relevant or mask & IN_ISDIR:
+                changed.add(path)
+        return changed
+
+
+def open_monitor(scanner: GoScanner):
+    """inotify on Linux, metadata polling anywhere else (or when inotify fails)."""
+    if sys.platform.startswith("linux"):
+        try:
+            return InotifyMonitor(scanner)
+        except (OSError, AttributeError) as e:
+            logger.debug(f"inotify unavailable ({e}); polling for changes")
+    return PollingMonitor(scanner)
+
+
+class Watcher:
+    """Re-scans whenever the scanned Go files, go.mod files or config change.
+
+    Changes come from inotify on Linux and from polling file metadata
+    elsewhere. A burst of edits is folded into one rescan once nothing has
+    changed for `debounce` seconds. The scanner keeps parsed trees between
+    runs, so only files whose content changed are parsed again. When a
+    config file changes, `reload` (if given) supplies a scanner with its
+    new options.
+    """
+
+    def __init__(
+        self,
+        scanner: GoScanner,
+        debounce: float = DEFAULT_DEBOUNCE,
+        interval: float = DEFAULT_INTERVAL,
+        reload: Optional[Reload] = None,
+    ):
+        self.scanner = scanner
+        self.scanner.reuse_trees = True
+        self.debounce = debounce
+        self.interval = interval
+        self.reload = reload
+
+    def run(self, on_report: OnReport, stop: Optional[threading.Event] = None):
+        """Scan once, then call `on_report(report, diff)` after every settled change."""
+        stop = stop or threading.Event()
+        # Watching starts first, so edits made during a scan trigger the next one.
+        monitor = open_monitor(self.scanner)
+        try:
+            report = self.scanner.scan()
+            on_report(report, None)
+            while True:
+                changed = monitor.changes(self.interval, stop)
+                if changed:
+                    changed = self._settle(monitor, changed, stop)
+                if changed is None:
+                    return
+                if not changed:
+                    continue
+                if self.reload is not None and any(p.name in CONFIG_FILES for p in changed):
+                    self._reload()
+                logger.info(f"Change detected in {len(changed)} files; rescanning")
+                monitor.refresh(self.scanner)
+                new = self.scanner.scan()
+                on_report(new, diff(report, new))
+                report = new
+        finally:
+            monitor.close()
+
+    def _reload(self):
+        scanner = self.reload()
+        if scanner is None:
+            logger.warning("Config file changed but could not be loaded; keeping its old options")
+            return
+        logger.info("Config file changed; rescanning with its new options")
+        scanner.reuse_trees = True
+        self.scanner = scanner
+
+    def _settle(self, monitor, changed: Set[Path], stop: threading.Event) -> Optional[Set[Path]]:
+        """Wait until the tree has been quiet for `debounce` seconds."""
+        while True:
+            more = monitor.changes(self.debounce, stop)
+            if more is None:
+                return None
+            if not more:
+                return changed
+            changed |= more
@@ -0,0 +1,208 @@
+# src/crowsight/server.py
+
+from contextlib import contextmanager
+import html
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+import threading
+import time
+from typing import Callable, Dict, Iterator, Optional, Tuple
+from urllib.parse import parse_qs
+
+from loguru import logger
+
+from .render.dot import render_dot
+from .render.mermaid import render_mermaid
+from .report.diff import ReportDiff
+from .report.models import Report
+from .scanner.scanner import GoScanner
+from .scanner.watch import DEFAULT_DEBOUNCE, Reload, Watcher
+
+DEFAULT_ADDR = ":9000"
+
+
+def parse_addr(addr: str) -> Tuple[str, int]:
+    """Go-style `host:port` (`:9000` binds every interface)."""
+    host, sep, port = addr.rpartition(":")
+    if not sep or not port.isdigit():
+        raise ValueError(f"invalid address {addr!r}; expected host:port")
+    return host.strip("[]"), int(port)
+
+
+class ReadWriteLock:
+    """Many concurrent readers or one writer."""
+
+    def __init__(self):
+        self._cond = threading.Condition()
+        self._readers = 0
+        self._writing = False
+
+    @contextmanager
+    def read(self) -> Iterator[None]:
+        with self._cond:
+            while self._writing:
+                self._cond.wait()
+            self._readers += 1
+        try:
+            yield
+        finally:
+            with self._cond:
+                self._readers -= 1
+                if not self._readers:
+                    self._cond.notify_all()
+
+    @contextmanager
+    def write(self) -> Iterator[None]:
+        with self._cond:
+            while self._writing or self._readers:
+                self._cond.wait()
+            self._writing = True
+        try:
+            yield
+        finally:
+            with self._cond:
+                self._writing = False
+                self._cond.notify_all()
+
+
+class LiveReport:
+    """The latest Report, kept fresh by a background Watcher."""
+
+    def __init__(
+        self,
+        scanner: GoScanner,
+        debounce: float = DEFAULT_DEBOUNCE,
+        reload: Optional[Reload] = None,
+    ):
+        self.watcher = Watcher(scanner, debounce=debounce, reload=reload)
+        self.lock = ReadWriteLock()
+        self.report: Optional[Report] = None
+        self.updated = 0.0
+        self._ready = threading.Event()
+        self._stop = threading.Event()
+        self._thread = threading.Thread(target=self._run, name="crowsight-watch", daemon=True)
+
+    def start(self):
+        self._thread.start()
+        self._ready.wait()
+
+    def stop(self):
+        self._stop.set()
+        self._thread.join()
+
+    @contextmanager
+    def current(self) -> Iterator[Report]:
+        with self.lock.read():
+            yield self.report
+
+    def _run(self):
+        try:
+            self.watcher.run(self._publish, self._stop)
+        except Exception as e:
+            logger.exception(f"Background scan failed: {e}")
+        finally:
+            self._ready.set()
+
+    def _publish(self, report: Report, changes: Optional[ReportDiff]):
+        # The new report is built without the lock; readers only wait for the swap.
+        with self.lock.write():
+            self.report = report
+            self.updated = time.time()
+        if changes is not None and not changes.empty:
+            logger.info(f"Report refreshed:\n{changes.to_text().rstrip()}")
+        self._ready.set()
+
+
+def render_index(report: Report, updated: float) -> str:
+    esc = html.escape
+    rows = []
+    for svc in report.services:
+        for ep in svc.endpoints:
+            rows.append(
+                f"<tr><td>{esc(svc.name)}</td><td>{esc(ep.method)}</td>"
+                f"<td><code>{esc(ep.path)}</code></td><td>{esc(ep.handler or '')}</td>"
+                f"<td>{esc(ep.file)}:{ep.line}</td></tr>"
+            )
+    stamp = time.strftime("%Y-%m-%d %H:%M:%S", time.localtime(updated))
+    return f"""<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>crowsight: {esc(report.root)}</title></head>
+<body>
+<h1>{esc(report.root)}</h1>
+<p>{len(report.services)} services, {len(report.endpoints)} endpoints,
+{len(report.todos)} TODOs, {len(report.findings)} findings &middot; scanned {stamp}</p>
+<p><a href="/report.json">report.json</a> &middot; <a href="/graph">graph</a>
+(<a href="/graph?format=dot">dot</a>)</p>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Service</th><th>Method</th><th>Path</th><th>Handler</th><th>Registered at</th></tr>
+{chr(10).join(rows)}
+</table>
+</body>
+</html>
+"""
+
+
+# path -> (content type, renderer)
+ROUTES: Dict[str, Tuple[str, Callable[[Report, float], str]]] = {
+    "/": ("text/html; charset=utf-8", render_index),
+    "/index.html": ("text/html; charset=utf-8", render_index),
+    "/report.json": ("application/json", lambda r, _: r.to_json() + "\n"),
+    "/graph": ("text/plain; charset=utf-8", lambda r, _: render_mermaid(r)),
+}
+
+# `/graph?format=...` alternatives to the default Mermaid source.
+GRAPH_FORMATS: Dict[str, Tuple[str, Callable[[Report, float], str]]] = {
+    "mermaid": ROUTES["/graph"],
+    "dot": ("text/vnd.graphviz; charset=utf-8", lambda r, _: render_dot(r)),
+}
+
+
+def make_handler(live: LiveReport):
+    class Handler(BaseHTTPRequestHandler):
+        def do_GET(self):
+            path, _, query = self.path.partition("?")
+            route = ROUTES.get(path)
+            fmt = parse_qs(query).get("format")
+            if path == "/graph" and fmt:
+                route = GRAPH_FORMATS.get(fmt[0])
+            if route is None:
+                self._send(404, "text/plain; charset=utf-8", "not found\n")
+                return
+            content_type, render = route
+            with live.current() as report:
+                if report is None:
+                    self._send(503, "text/plain; charset=utf-8", "scan failed; see logs\n")
+                    return
+                body = render(report, live.updated)
+            self._send(200, content_type, body)
+
+        def _send(self, status: int, content_type: str, body: str):
+            data = body.encode("utf-8")
+            self.send_response(status)
+            self.send_header("Content-Type", content_type)
+            self.send_header("Content-Length", str(len(data)))
+            self.end_headers()
+            self.wfile.write(data)
+
+        def log_message(self, format, *args):
+            logger.debug(f"{self.address_string()} {format % args}")
+
+    return Handler
+
+
+def serve(
+    scanner: GoScanner,
+    addr: str = DEFAULT_ADDR,
+    debounce: float = DEFAULT_DEBOUNCE,
+    reload: Optional[Reload] = None,
+):
+    """Serve the live report until interrupted."""
+    host, port = parse_addr(addr)
+    live = LiveReport(scanner, debounce, reload)
+    live.start()
+    httpd = ThreadingHTTPServer((host, port), make_handler(live))
+    logger.info(f"Serving {scanner.root} on http://{host or 'localhost'}:{httpd.server_port}/")
+    try:
+        httpd.serve_forever()
+    finally:
+        httpd.server_close()
+        live.stop()
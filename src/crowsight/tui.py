@@ -0,0 +1,285 @@
+# src/crowsight/tui.py
+
+import curses
+from dataclasses import dataclass, field
+import os
+from pathlib import Path
+import posixpath
+import shlex
+import subprocess
+from typing import Dict, List, Optional, Set, Tuple
+
+from .report.models import Endpoint, Finding, Report, Service, Struct
+
+# Tried in order; the first one set opens files on `enter`.
+EDITOR_ENV = ("VISUAL", "EDITOR")
+DEFAULT_EDITOR = "vi"
+HELP = "up/down move  left/right fold  / filter by path  enter open in $EDITOR  q quit"
+
+
+@dataclass
+class Row:
+    """One line of the tree: a service, one of its endpoints, or a finding."""
+
+    depth: int
+    label: str
+    service: Service
+    endpoint: Optional[Endpoint] = None
+    finding: Optional[Finding] = None
+    # An endpoint's findings, listed under it.
+    findings: List[Finding] = field(default_factory=list)
+
+    @property
+    def location(self) -> Tuple[str, int]:
+        if self.finding is not None:
+            return self.finding.file, self.finding.line
+        if self.endpoint is not None:
+            ep = self.endpoint
+            return (ep.handler_file, ep.handler_line) if ep.handler_file else (ep.file, ep.line)
+        return (self.service.files[0], 1) if self.service.files else ("", 0)
+
+
+def endpoint_findings(
+    report: Report, svc: Service
+) -> Tuple[Dict[int, List[Finding]], List[Finding]]:
+    """The service's findings by endpoint index, and those in none of its endpoints.
+
+    A finding belongs to an endpoint when it is on the route's registration,
+    or in its handler: from the handler's first line up to the next handler,
+    struct or route registration in the same file, since the report does not
+    record where functions end.
+    """
+    starts: Dict[str, List[int]] = {}
+    for item in [*svc.handlers, *svc.structs, *svc.endpoints]:
+        starts.setdefault(item.file, []).append(item.line)
+    for ep in svc.endpoints:
+        if ep.handler_file:
+            starts.setdefault(ep.handler_file, []).append(ep.handler_line)
+    by_endpoint: Dict[int, List[Finding]] = {}
+    rest: List[Finding] = []
+    ranges = []
+    for i, ep in enumerate(svc.endpoints):
+        if ep.handler_file:
+            later = [n for n in starts[ep.handler_file] if n > ep.handler_line]
+            end = min(later) if later else None
+            ranges.append((i, ep.handler_file, ep.handler_line, end))
+    for fd in report.findings:
+        if posixpath.dirname(fd.file) != svc.dir:
+            continue
+        at = (fd.file, fd.line)
+        owners = [i for i, ep in enumerate(svc.endpoints) if (ep.file, ep.line) == at]
+        if not owners:
+            owners = [
+                i
+                for i, rel, start, end in ranges
+                if rel == fd.file and start <= fd.line and (end is None or fd.line < end)
+            ]
+        for i in owners:
+            by_endpoint.setdefault(i, []).append(fd)
+        if not owners:
+            rest.append(fd)
+    return by_endpoint, rest
+
+
+def build_rows(report: Report, query: str = "", folded: Optional[Set[str]] = None) -> List[Row]:
+    """The visible tree; a query keeps only endpoints whose path contains it."""
+    folded = folded or set()
+    needle = query.lower()
+    rows: List[Row] = []
+    for svc in report.services:
+        by_endpoint, rest = endpoint_findings(report, svc)
+        keep = [
+            i for i, ep in enumerate(svc.endpoints) if not needle or needle in ep.path.lower()
+        ]
+        if needle and not keep:
+            continue
+        # A finding in a handler that serves several routes is listed under each one.
+        shown = {id(fd) for i in keep for fd in by_endpoint.get(i, [])}
+        count = len(shown) + (0 if needle else len(rest))
+        label = f"{svc.name} ({len(keep)} endpoints, {count} findings)"
+        rows.append(Row(0, label, svc))
+        if svc.dir in folded:
+            continue
+        for i in keep:
+            ep = svc.endpoints[i]
+            findings = by_endpoint.get(i, [])
+            mark = f" [{len(findings)}]" if findings else ""
+            rows.append(Row(1, f"{ep.method} {ep.path}{mark}", svc, ep, findings=findings))
+            for fd in findings:
+                rows.append(Row(2, f"{fd.severity.value}: {fd.code}", svc, ep, fd))
+        if not needle:
+            for fd in rest:
+                rows.append(Row(1, f"{fd.severity.value}: {fd.code}", svc, finding=fd))
+    return rows
+
+
+def struct_lines(report: Report, label: str, type_name: str, svc: Service) -> List[str]:
+    st: Optional[Struct] = report.find_struct(type_name, svc.package)
+    if st is None:
+        return [f"{label}: {type_name}"]
+    lines = [f"{label}: {type_name} ({st.file}:{st.line})"]
+    for fl in st.fields:
+        tag = f' json:"{fl.json}{",omitempty" if fl.omitempty else ""}"' if fl.json else ""
+        lines.append(f"    {fl.name} {fl.type}{tag}")
+    return lines
+
+
+def detail_lines(report: Report, row: Row) -> List[str]:
+    svc, ep, fd = row.service, row.endpoint, row.finding
+    if fd is not None:
+        lines = [f"{fd.severity.value}: {fd.code}", f"at {fd.file}:{fd.line}", ""]
+        lines.append(fd.message)
+        if fd.expression:
+            lines += ["", fd.expression]
+        return lines
+    if ep is not None:
+        lines = [f"{ep.method} {ep.path}", f"registered at {ep.file}:{ep.line}"]
+        if ep.handler:
+            where = f" ({ep.handler_file}:{ep.handler_line})" if ep.handler_file else ""
+            lines.append(f"handler: {ep.handler}{where}")
+        if ep.path_params:
+            lines.append("path params: " + ", ".join(p.name for p in ep.path_params))
+        lines.append("middleware: " + (" -> ".join(ep.middleware) or "none"))
+        if ep.status_codes:
+            lines.append("statuses: " + ", ".join(str(c) for c in ep.status_codes))
+        if ep.request:
+            lines += ["", *struct_lines(report, "request", ep.request, svc)]
+        if ep.response:
+            lines += ["", *struct_lines(report, "response", ep.response, svc)]
+        if row.findings:
+            lines += ["", "findings:"]
+            lines += [f"    {f.severity.value} {f.code} {f.file}:{f.line}" for f in row.findings]
+        return lines
+    lines = [svc.name, f"package {svc.package} in {svc.dir or '.'}"]
+    if svc.module:
+        lines.append(f"module {svc.module}")
+    if svc.listen_addr:
+        lines.append(f"listens on {svc.listen_addr}")
+    lines.append(f"{len(svc.endpoints)} endpoints, {len(svc.outbound_calls)} outbound calls")
+    yes_no = {True: "yes", False: "no"}
+    lines.append(f"metrics: {yes_no[svc.has_metrics]}, health: {yes_no[svc.has_health]}")
+    return lines
+
+
+def editor_command(path: str, line: int) -> List[str]:
+    """`$EDITOR +LINE PATH`, which vi, vim, nano, emacs and most others accept."""
+    editor = next((os.environ[k] for k in EDITOR_ENV if os.environ.get(k)), DEFAULT_EDITOR)
+    return [*shlex.split(editor), f"+{max(line, 1)}", path]
+
+
+class Browser:
+    """Arrow-key navigation over one Report; nothing is rescanned."""
+
+    def __init__(self, report: Report):
+        self.report = report
+        self.query = ""
+        self.folded: Set[str] = set()
+        self.cursor = 0
+        self.top = 0
+        self.message = ""
+        self.rows = build_rows(report)
+
+    def refresh_rows(self):
+        self.rows = build_rows(self.report, self.query, self.folded)
+        self.cursor = min(self.cursor, max(len(self.rows) - 1, 0))
+
+    def run(self, screen):
+        curses.curs_set(0)
+        screen.keypad(True)
+        while True:
+            self.draw(screen)
+            key = screen.getch()
+            if key == ord("q"):
+                return
+            if key in (curses.KEY_UP, ord("k")):
+                self.cursor = max(self.cursor - 1, 0)
+            elif key in (curses.KEY_DOWN, ord("j")):
+                self.cursor = min(self.cursor + 1, max(len(self.rows) - 1, 0))
+            elif key in (curses.KEY_LEFT, curses.KEY_RIGHT) and self.rows:
+                svc = self.rows[self.cursor].service
+                if key == curses.KEY_LEFT:
+                    self.folded.add(svc.dir)
+                else:
+                    self.folded.discard(svc.dir)
+                self.refresh_rows()
+                self.cursor = next(i for i, r in enumerate(self.rows) if r.service is svc)
+            elif key == ord("/"):
+                self.query = self.prompt(screen, "/")
+                self.cursor = 0
+                self.refresh_rows()
+            elif key in (curses.KEY_ENTER, 10, 13) and self.rows:
+                self.open(screen, self.rows[self.cursor])
+
+    def open(self, screen, row: Row):
+        rel, line = row.location
+        if not rel:
+            self.message = "nothing to open"
+            return
+        cmd = editor_command(str(Path(self.report.root) / rel), line)
+        curses.endwin()
+        try:
+            subprocess.call(cmd)
+            self.message = ""
+        except OSError as e:
+            self.message = f"cannot run {cmd[0]}: {e}"
+        screen.refresh()
+
+    def prompt(self, screen, label: str) -> str:
+        height, width = screen.getmaxyx()
+        text = self.query
+        curses.curs_set(1)
+        while True:
+            screen.move(height - 1, 0)
+            screen.clrtoeol()
+            screen.addnstr(height - 1, 0, label + text, width - 1)
+            key = screen.get_wch()
+            if key in ("\n", "\r", curses.KEY_ENTER):
+                break
+            if key == "\x1b":
+                text = self.query
+                break
+            if key in ("\b", "\x7f", curses.KEY_BACKSPACE):
+                text = text[:-1]
+            elif isinstance(key, str) and key.isprintable():
+                text += key
+        curses.curs_set(0)
+        return text
+
+    def draw(self, screen):
+        screen.erase()
+        try:
+            self._paint(screen)
+        except curses.error:
+            # Too small a terminal for some of it; whatever fit is still shown.
+            pass
+        screen.refresh()
+
+    def _paint(self, screen):
+        height, width = screen.getmaxyx()
+        left = min(max(width * 2 // 5, 20), width)
+        body = max(height - 1, 1)
+        if self.cursor < self.top:
+            self.top = self.cursor
+        elif self.cursor >= self.top + body:
+            self.top = self.cursor - body + 1
+        for y, row in enumerate(self.rows[self.top : self.top + body]):
+            attr = curses.A_REVERSE if self.top + y == self.cursor else curses.A_NORMAL
+            if row.depth == 0:
+                attr |= curses.A_BOLD
+            prefix = ("+ " if row.service.dir in self.folded else "- ") if row.depth == 0 else ""
+            text = "  " * row.depth + prefix + row.label
+            screen.addnstr(y, 0, text.ljust(left - 1), left - 1, attr)
+        if self.rows:
+            for y, text in enumerate(detail_lines(self.report, self.rows[self.cursor])[:body]):
+                screen.addnstr(y, left + 1, text, max(width - left - 2, 0))
+        else:
+            screen.addnstr(0, 0, f"no endpoints match {self.query!r}", width - 1)
+        for y in range(body):
+            screen.addch(y, left - 1, curses.ACS_VLINE)
+        status = self.message or (f"/{self.query}  " if self.query else "") + HELP
+        screen.addnstr(height - 1, 0, status, width - 1, curses.A_DIM)
+
+
+def browse(report: Report):
+    """Browse the report until the user quits."""
+    curses.wrapper(Browser(report).run)
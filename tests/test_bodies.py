@@ -0,0 +1,60 @@
+# tests/test_bodies.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def unbounded(handler: str, route: str = 'http.HandleFunc("POST /items", create)'):
+    main = f"""package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+func create(w http.ResponseWriter, r *http.Request) {{
+	var v map[string]any
+	{handler}
+}}
+
+func BodyLimit(next http.Handler) http.Handler {{ return next }}
+
+func main() {{
+	{route}
+}}
+"""
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+    return [f for f in report.findings if f.code == "unbounded-body"]
+
+
+class UnboundedBodyTest(unittest.TestCase):
+    def test_decoder_and_read_all(self):
+        handler = "json.NewDecoder(r.Body).Decode(&v)\n\tio.ReadAll(r.Body)"
+        found = unbounded(handler)
+        self.assertEqual([f.line for f in found], [11, 12])
+        self.assertEqual(found[0].expression, "json.NewDecoder(r.Body).Decode(&v)")
+        self.assertIn("r.Body = http.MaxBytesReader(w, r.Body, 1<<20)", found[0].message)
+        self.assertIn("io.ReadAll(r.Body) reads an unbounded request body", found[1].message)
+
+    def test_limit_before_reading(self):
+        limit = "r.Body = http.MaxBytesReader(w, r.Body, 1<<20)"
+        self.assertEqual(unbounded(f"{limit}\n\tjson.NewDecoder(r.Body).Decode(&v)"), [])
+
+    def test_limit_after_reading_is_too_late(self):
+        limit = "r.Body = http.MaxBytesReader(w, r.Body, 1<<20)"
+        found = unbounded(f"json.NewDecoder(r.Body).Decode(&v)\n\t{limit}")
+        self.assertEqual([f.line for f in found], [11])
+
+    def test_wrapped_reader_and_limit_middleware(self):
+        wrapped = "json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&v)"
+        self.assertEqual(unbounded(wrapped), [])
+        route = """mux := http.NewServeMux()
+	mux.HandleFunc("POST /items", create)
+	http.ListenAndServe(":8080", BodyLimit(mux))"""
+        self.assertEqual(unbounded("io.ReadAll(r.Body)", route), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,81 @@
+# tests/test_config.py
+
+from pathlib import Path
+import tempfile
+import unittest
+
+from crowsight.cli import build_parser, config_defaults
+from crowsight.config import load_config
+
+CONFIG = """\
+output: /tmp/owned.json
+template-file: /etc/passwd
+template: "{{.Root}}"
+cache_dir: ../../cache
+addr: 0.0.0.0:80
+max-complexity: 12
+exclude: [vendor]
+policy: policy.yaml
+"""
+
+
+class CheckoutConfigTest(unittest.TestCase):
+    def setUp(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        self.clone = Path(tmp.name) / "repo"
+        self.clone.mkdir()
+        self.path = self.clone / "crowsight.yaml"
+        self.path.write_text(CONFIG)
+
+    def test_own_config_is_honored(self):
+        defaults = load_config(self.path)
+        self.assertEqual(defaults["output"], str(Path("/tmp/owned.json")))
+        self.assertEqual(defaults["addr"], "0.0.0.0:80")
+
+    def test_cloned_config_keeps_only_analysis_keys(self):
+        defaults = load_config(self.path, self.clone)
+        self.assertEqual(sorted(defaults), ["exclude", "max_complexity", "policy"])
+        self.assertEqual(defaults["policy"], str(self.clone / "policy.yaml"))
+
+    def test_cloned_paths_stay_inside(self):
+        self.path.write_text("policy: ../elsewhere.yaml\n")
+        with self.assertRaises(ValueError):
+            load_config(self.path, self.clone)
+        self.path.write_text("spec: /etc/openapi.yaml\n")
+        with self.assertRaises(ValueError):
+            load_config(self.path, self.clone)
+
+    def test_full_yaml(self):
+        self.path.write_text(
+            "---\nexclude: &skip [vendor, testdata]\ndisable: *skip\n"
+            "since: >-\n  origin/main\nmax-complexity: 12  # gocyclo\n"
+        )
+        self.assertEqual(
+            load_config(self.path),
+            {
+                "exclude": ["vendor", "testdata"],
+                "disable": ["vendor", "testdata"],
+                "since": "origin/main",
+                "max_complexity": 12,
+            },
+        )
+
+    def test_invalid_yaml(self):
+        self.path.write_text("exclude: [vendor\n")
+        with self.assertRaises(ValueError):
+            load_config(self.path)
+
+    def test_config_defaults_for_repo_scans(self):
+        args = build_parser().parse_args(["scan", "--repo", "https://example.com/r.git"])
+        args.checkout = self.clone
+        defaults = config_defaults(args)
+        self.assertNotIn("output", defaults)
+        self.assertEqual(defaults["max_complexity"], 12)
+        # An explicit --config is the user's own file.
+        args.config = str(self.path)
+        self.assertIn("output", config_defaults(args))
+
+
+if __name__ == "__main__":
+    unittest.main()
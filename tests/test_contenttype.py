@@ -0,0 +1,67 @@
+# tests/test_contenttype.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def missing(handler: str):
+    main = f"""package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func jsonHeader(w http.ResponseWriter) {{
+	w.Header().Set("Content-Type", "application/json")
+}}
+
+func get(w http.ResponseWriter, r *http.Request) {{
+	v := map[string]int{{"n": 1}}
+	{handler}
+}}
+
+func main() {{
+	http.HandleFunc("/items", get)
+}}
+"""
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+    return [(f.line, f.message) for f in report.findings if f.code == "missing-content-type"]
+
+
+class MissingContentTypeTest(unittest.TestCase):
+    def test_no_content_type(self):
+        ((line, message),) = missing("json.NewEncoder(w).Encode(v)")
+        self.assertEqual(line, 14)
+        self.assertIn("no Content-Type is set", message)
+        self.assertIn('w.Header().Set("Content-Type", "application/json")', message)
+
+    def test_wrong_type_and_too_late(self):
+        handler = """w.Header().Set("Content-Type", "text/plain")
+	json.NewEncoder(w).Encode(v)"""
+        ((_, message),) = missing(handler)
+        self.assertIn("the Content-Type set is not a JSON type", message)
+        handler = """b, _ := json.Marshal(v)
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)"""
+        ((line, message),) = missing(handler)
+        self.assertEqual(line, 17)
+        self.assertIn("only set after the header was written", message)
+
+    def test_content_type_set_first(self):
+        handler = """w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)"""
+        self.assertEqual(missing(handler), [])
+        self.assertEqual(missing("jsonHeader(w)\n\tjson.NewEncoder(w).Encode(v)"), [])
+        header_map = 'w.Header()["Content-Type"] = []string{"application/json"}'
+        self.assertEqual(missing(f"{header_map}\n\tjson.NewEncoder(w).Encode(v)"), [])
+
+    def test_not_json_or_suppressed(self):
+        self.assertEqual(missing('w.Write([]byte("ok"))'), [])
+        self.assertEqual(missing("json.NewEncoder(w).Encode(v) //nolint:crowsight"), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
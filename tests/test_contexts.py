@@ -0,0 +1,63 @@
+# tests/test_contexts.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def detached(handler: str):
+    main = f"""package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+func fetch(ctx context.Context, id string) error {{ return nil }}
+
+func get(w http.ResponseWriter, r *http.Request) {{
+	{handler}
+}}
+
+func main() {{
+	http.HandleFunc("/items", get)
+	_ = time.Second
+}}
+"""
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+    return [f for f in report.findings if f.code == "detached-context"]
+
+
+class DetachedContextTest(unittest.TestCase):
+    def test_background_passed_directly(self):
+        (found,) = detached('fetch(context.Background(), "a")')
+        self.assertEqual(found.line, 12)
+        self.assertIn("fetch is called with context.Background()", found.message)
+        self.assertIn("pass r.Context()", found.message)
+
+    def test_traced_through_variables_and_wrappers(self):
+        handler = """ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	fetch(ctx, "a")"""
+        (found,) = detached(handler)
+        self.assertEqual(found.line, 14)
+        self.assertIn("ctx (from context.TODO())", found.message)
+
+    def test_request_context_is_fine(self):
+        handler = """ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+	defer cancel()
+	fetch(ctx, "a")
+	fetch(r.Context(), "b")"""
+        self.assertEqual(detached(handler), [])
+
+    def test_goroutines_and_nolint_are_not_flagged(self):
+        handler = """go func() {
+		fetch(context.Background(), "a")
+	}()
+	fetch(context.Background(), "b") //nolint:crowsight"""
+        self.assertEqual(detached(handler), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,131 @@
+# tests/test_contract.py
+
+import io
+import os
+from contextlib import redirect_stdout
+from pathlib import Path
+import tempfile
+import unittest
+
+from crowsight import scan_fs
+from crowsight.cli import main
+from crowsight.report.contract import load_spec, validate
+
+MAIN = """package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func h(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	r := chi.NewRouter()
+	r.Route("/v1/payments", func(r chi.Router) {
+		r.Post("/", h)
+		r.Get("/{paymentID}", h)
+	})
+	r.Get("/healthz", h)
+	http.ListenAndServe(":8080", r)
+}
+"""
+
+SPEC = """\
+openapi: 3.0.3
+info:
+  title: Payments
+  version: "1.0"
+  description: >
+    Takes payments and
+    reports on them.
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /payments:
+    post:
+      summary: Create a payment
+      description: >-
+        Charges the card on file; the amount is in minor units.
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Payment'}
+      responses:
+        "201": &created
+          description: |
+            Created.
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Payment'}
+  /payments/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      responses:
+        "200": *created
+    delete:
+      responses: {"204": {description: Deleted}}
+  /refunds:
+    get:
+      responses: {"200": {description: OK}}
+components:
+  schemas:
+    Payment:
+      type: object
+      properties: {amount: {type: integer}, currency: {type: string}}
+"""
+
+
+class ValidateTest(unittest.TestCase):
+    def setUp(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        self.root = Path(tmp.name)
+        (self.root / "go.mod").write_text("module example.com/pay\n\ngo 1.22\n")
+        (self.root / "main.go").write_text(MAIN)
+        self.spec = self.root / "openapi.yaml"
+        self.spec.write_text(SPEC)
+
+    def test_load_spec(self):
+        spec = load_spec(str(self.spec))
+        self.assertEqual(spec["info"]["description"], "Takes payments and reports on them.\n")
+        body = spec["paths"]["/payments"]["post"]["requestBody"]
+        self.assertEqual(
+            body["content"]["application/json"]["schema"],
+            {"$ref": "#/components/schemas/Payment"},
+        )
+        self.assertEqual(spec["paths"]["/payments/{id}"]["parameters"][0]["in"], "path")
+
+    def test_drift(self):
+        report = scan_fs({"go.mod": "module example.com/pay\n\ngo 1.22\n", "main.go": MAIN})
+        drift = validate(report, load_spec(str(self.spec)))
+        self.assertEqual([(u.method, u.path) for u in drift.undocumented], [("GET", "/healthz")])
+        self.assertEqual(
+            [(op.method, op.path) for op in drift.unimplemented], [("GET", "/v1/refunds")]
+        )
+        self.assertEqual(
+            [(m.path, m.code, m.spec) for m in drift.mismatched],
+            [("/v1/payments/{id}", ["GET"], ["GET", "DELETE"])],
+        )
+
+    def test_invalid_yaml(self):
+        self.spec.write_text("paths:\n  /x: [unclosed\n")
+        with self.assertRaises(ValueError):
+            load_spec(str(self.spec))
+
+    def test_command(self):
+        out = io.StringIO()
+        cwd = os.getcwd()
+        os.chdir(self.root)
+        self.addCleanup(os.chdir, cwd)
+        with redirect_stdout(out):
+            code = main(["validate", "--spec", "openapi.yaml"])
+        self.assertEqual(code, 1)
+        self.assertIn("+ GET /healthz", out.getvalue())
+        self.assertIn("- GET /v1/refunds: not implemented", out.getvalue())
+
+
+if __name__ == "__main__":
+    unittest.main()
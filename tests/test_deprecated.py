@@ -0,0 +1,83 @@
+# tests/test_deprecated.py
+
+import unittest
+
+from crowsight import scan_fs
+
+CLIENT = """package client
+
+// Client talks to the billing API.
+type Client struct{}
+
+// Charge bills the card.
+//
+// Deprecated: use ChargeContext, which can be cancelled.
+func (c *Client) Charge(amount int) {}
+
+// ChargeContext bills the card.
+func (c *Client) ChargeContext(amount int) {}
+
+// Deprecated: use New.
+func Dial() *Client { return &Client{} }
+
+func New() *Client { return &Client{} }
+"""
+
+
+def deprecated(body: str, decls: str = "", imports: str = ""):
+    main = f"""package main
+
+import (
+	"example.com/api/client"
+	{imports}
+)
+
+{decls}
+
+func main() {{
+	{body}
+}}
+"""
+    report = scan_fs(
+        {
+            "go.mod": "module example.com/api\n\ngo 1.22\n",
+            "client/client.go": CLIENT,
+            "main.go": main,
+        }
+    )
+    return [(f.file, f.line, f.message) for f in report.findings if f.code == "deprecated-call"]
+
+
+class DeprecatedUsageTest(unittest.TestCase):
+    def test_imported_function_and_method(self):
+        found = deprecated("client.Dial()\n\tc := &client.Client{}\n\tc.Charge(10)")
+        self.assertEqual(
+            found,
+            [
+                ("main.go", 11, "client.Dial is deprecated: use New."),
+                (
+                    "main.go",
+                    13,
+                    "client.Client.Charge is deprecated: use ChargeContext, which can be "
+                    "cancelled.",
+                ),
+            ],
+        )
+
+    def test_local_declaration_and_stdlib(self):
+        decls = "// Deprecated: use run.\nfunc start() {}"
+        found = deprecated('start()\n\tstrings.Title("a")', decls, '"strings"')
+        self.assertEqual(
+            [(line, msg.split(":")[0]) for _, line, msg in found],
+            [(12, "start is deprecated"), (13, "strings.Title is deprecated")],
+        )
+
+    def test_current_apis_are_fine(self):
+        self.assertEqual(deprecated("c := &client.Client{}\n\tc.ChargeContext(10)"), [])
+        decls = "// Deprecated: use run.\nfunc start() { client.Dial() }"
+        self.assertEqual(deprecated("_ = client.New()", decls), [])
+        self.assertEqual(deprecated("client.Dial() //nolint:crowsight"), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
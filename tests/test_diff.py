@@ -0,0 +1,92 @@
+# tests/test_diff.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.report.diff import diff
+
+GO_MOD = "module example.com/m\n\ngo 1.22\n"
+
+OLD = """package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// TODO: paginate
+func users(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(User{})
+}
+
+func h(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("GET /users", users)
+	http.HandleFunc("GET /items", h)
+	http.HandleFunc("GET /old", h)
+}
+"""
+
+NEW = """package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type User struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+
+// TODO: paginate
+func users(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(User{})
+}
+
+// FIXME: validate
+func h(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("GET /users", users)
+	http.HandleFunc("POST /items", h)
+	http.HandleFunc("GET /new", h)
+}
+"""
+
+
+def scan(source: str):
+    return scan_fs({"go.mod": GO_MOD, "main.go": source})
+
+
+class DiffTest(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        cls.result = diff(scan(OLD), scan(NEW))
+
+    def test_same_report_is_empty(self):
+        self.assertTrue(diff(scan(OLD), scan(OLD)).empty)
+        self.assertEqual(diff(scan(OLD), scan(OLD)).to_text(), "No changes\n")
+
+    def test_added_and_removed(self):
+        self.assertEqual([(r.method, r.path) for r in self.result.added], [("GET", "/new")])
+        self.assertEqual([(r.method, r.path) for r in self.result.removed], [("GET", "/old")])
+
+    def test_changes(self):
+        changes = {(c.method, c.path): c.changes for c in self.result.changed}
+        self.assertEqual(changes[("POST", "/items")], ["method GET -> POST"])
+        self.assertEqual(changes[("GET", "/users")], ["response schema of User changed"])
+
+    def test_todos_ignore_moved_lines(self):
+        self.assertEqual([t.text for t in self.result.todos_added], ["validate"])
+        self.assertEqual(self.result.todos_removed, [])
+
+
+if __name__ == "__main__":
+    unittest.main()
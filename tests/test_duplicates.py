@@ -0,0 +1,54 @@
+# tests/test_duplicates.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def duplicates(body: str):
+    main = f"""package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func list(c *gin.Context) {{}}
+
+func main() {{
+	{body}
+	http.ListenAndServe(":8080", r)
+}}
+"""
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+    return [f for f in report.findings if f.code == "duplicate-route"]
+
+
+class DuplicateRouteTest(unittest.TestCase):
+    def test_same_path_in_different_groups(self):
+        body = """r := gin.New()
+	v1 := r.Group("/v1")
+	v1.GET("/users", list)
+	v2 := r.Group("/v2")
+	v2.GET("/users", list)"""
+        self.assertEqual(duplicates(body), [])
+
+    def test_same_path_twice(self):
+        body = """r := gin.New()
+	r.GET("/users/:id", list)
+	v1 := r.Group("/users")
+	v1.GET("/:key", list)"""
+        (found,) = duplicates(body)
+        self.assertEqual(found.line, 13)
+        self.assertIn("GET route registered 2 times: /users/:id at main.go:13", found.message)
+
+    def test_different_methods(self):
+        body = """r := gin.New()
+	r.GET("/users", list)
+	r.POST("/users", list)"""
+        self.assertEqual(duplicates(body), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
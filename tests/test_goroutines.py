@@ -0,0 +1,59 @@
+# tests/test_goroutines.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def leaks(handler: str):
+    main = f"""package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+func notify(ctx context.Context) {{}}
+
+func send(w http.ResponseWriter, r *http.Request) {{
+	var wg sync.WaitGroup
+	{handler}
+}}
+
+func main() {{
+	http.HandleFunc("POST /send", send)
+}}
+"""
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+    return [f for f in report.findings if f.code == "fire-and-forget-goroutine"]
+
+
+class GoroutineLeakTest(unittest.TestCase):
+    def test_fire_and_forget(self):
+        (found,) = leaks("go notify(context.Background())")
+        self.assertEqual(found.line, 13)
+        self.assertIn("goroutine started in send is not tied to the request", found.message)
+
+    def test_detached_context_variable(self):
+        handler = "ctx := context.Background()\n\tgo func() { notify(ctx) }()"
+        self.assertEqual([f.line for f in leaks(handler)], [14])
+
+    def test_request_context_is_fine(self):
+        self.assertEqual(leaks("go notify(r.Context())"), [])
+        self.assertEqual(leaks("ctx := r.Context()\n\tgo func() { notify(ctx) }()"), [])
+
+    def test_waited_for_is_fine(self):
+        handler = """wg.Add(1)
+	go func() {
+		defer wg.Done()
+		notify(context.Background())
+	}()
+	wg.Wait()"""
+        self.assertEqual(leaks(handler), [])
+        done = "done := make(chan struct{})\n\tgo func() { close(done) }()\n\t<-done"
+        self.assertEqual(leaks(done), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
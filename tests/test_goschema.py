@@ -0,0 +1,140 @@
+# tests/test_goschema.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.render.goschema import SchemaBuilder
+from crowsight.report.models import Report, Service, Struct, StructField
+
+
+def report_with(*structs: Struct) -> Report:
+    svc = Service(name="api", package="api", dir="api", structs=list(structs))
+    return Report(root=".", services=[svc])
+
+
+def field(name: str, typ: str, **kw) -> StructField:
+    return StructField(name=name, type=typ, **kw)
+
+
+class SchemaBuilderTest(unittest.TestCase):
+    def schema(self, report: Report, name: str, strict: bool = False):
+        builder = SchemaBuilder(report, "#/defs/", strict=strict)
+        ref = builder.for_type(name, "api")["$ref"]
+        return builder.defs[ref.rsplit("/", 1)[-1]], builder
+
+    def test_primitives_pointers_and_collections(self):
+        st = Struct(
+            name="User",
+            package="api",
+            fields=[
+                field("ID", "int64", json="id"),
+                field("Tags", "[]string", json="tags", omitempty=True),
+                field("Meta", "map[string]int", json="meta"),
+                field("Avatar", "*string", json="avatar"),
+                field("Raw", "[]byte", json="raw"),
+            ],
+        )
+        schema, _ = self.schema(report_with(st), "User", strict=True)
+        props = schema["properties"]
+        self.assertEqual(props["id"], {"type": "integer", "format": "int64"})
+        self.assertEqual(props["tags"], {"type": "array", "items": {"type": "string"}})
+        self.assertEqual(props["meta"]["additionalProperties"], {"type": "integer"})
+        self.assertEqual(props["avatar"], {"type": ["string", "null"]})
+        self.assertEqual(props["raw"], {"type": "string", "format": "byte"})
+        self.assertEqual(schema["required"], ["id", "meta", "avatar", "raw"])
+
+    def test_skips_unexported_and_dash_fields(self):
+        st = Struct(
+            name="User",
+            package="api",
+            fields=[
+                field("Name", "string"),
+                field("secret", "string"),
+                field("Hidden", "string", json="-"),
+            ],
+        )
+        schema, _ = self.schema(report_with(st), "User")
+        self.assertEqual(list(schema["properties"]), ["Name"])
+
+    def test_promotes_untagged_embedded_structs(self):
+        exported = Struct(name="Base", package="api", fields=[field("ID", "int", json="id")])
+        unexported = Struct(
+            name="audit", package="api", fields=[field("Created", "string", json="created")]
+        )
+        st = Struct(
+            name="User",
+            package="api",
+            fields=[
+                field("Base", "Base", embedded=True),
+                field("audit", "*audit", embedded=True),
+                field("Name", "string", json="name"),
+            ],
+        )
+        schema, _ = self.schema(report_with(st, exported, unexported), "User")
+        self.assertEqual(list(schema["properties"]), ["id", "created", "name"])
+
+    def test_tagged_embedded_struct_is_a_field(self):
+        inner = Struct(name="audit", package="api", fields=[field("By", "string", json="by")])
+        st = Struct(
+            name="User",
+            package="api",
+            fields=[
+                field("audit", "audit", json="audit", embedded=True),
+                field("mutex", "sync.Mutex", embedded=True),
+            ],
+        )
+        schema, builder = self.schema(report_with(st, inner), "User")
+        self.assertEqual(list(schema["properties"]), ["audit"])
+        self.assertEqual(builder.defs["audit"]["properties"], {"by": {"type": "string"}})
+
+    def test_recursive_structs_are_referenced(self):
+        st = Struct(name="Node", package="api", fields=[field("Next", "*Node", json="next")])
+        schema, _ = self.schema(report_with(st), "Node")
+        self.assertEqual(schema["properties"]["next"], {"$ref": "#/defs/Node"})
+
+    def test_same_name_in_another_package_is_qualified(self):
+        a = Struct(name="Item", package="api", fields=[field("A", "string")])
+        b = Struct(name="Item", package="store", fields=[field("B", "string")])
+        builder = SchemaBuilder(report_with(a, b), "#/")
+        self.assertEqual(builder.for_type("Item", "api"), {"$ref": "#/Item"})
+        self.assertEqual(builder.for_type("store.Item", "api"), {"$ref": "#/store.Item"})
+
+
+class ScannedEmbeddedStructTest(unittest.TestCase):
+    def test_unexported_embedded_fields_reach_the_schema(self):
+        report = scan_fs(
+            {
+                "go.mod": "module example.com/api\n\ngo 1.22\n",
+                "main.go": """package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type timestamps struct {
+	Created string `json:"created"`
+}
+
+type User struct {
+	timestamps
+	Name string `json:"name"`
+}
+
+func get(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(User{})
+}
+
+func main() {
+	http.HandleFunc("/user", get)
+}
+""",
+            }
+        )
+        builder = SchemaBuilder(report, "#/")
+        builder.for_type("User", report.services[0].package)
+        self.assertEqual(list(builder.defs["User"]["properties"]), ["created", "name"])
+
+
+if __name__ == "__main__":
+    unittest.main()
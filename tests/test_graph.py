@@ -0,0 +1,75 @@
+# tests/test_graph.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.render.dot import render_dot
+from crowsight.render.mermaid import render_mermaid
+from crowsight.report.models import Report
+
+# Two services named `api`, one calling the other.
+FILES = {
+    "go.mod": "module example.com/m\n\ngo 1.22\n",
+    "x/api/main.go": """package main
+
+import "net/http"
+
+func h(w http.ResponseWriter, r *http.Request) {
+	resp, _ := http.Get("http://orders/orders")
+	_ = resp
+}
+
+func main() {
+	http.HandleFunc("/users", h)
+}
+""",
+    "y/api/main.go": """package main
+
+import "net/http"
+
+func h(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("/orders", h)
+}
+""",
+}
+
+
+class SameNameServicesTest(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        cls.report = scan_fs(FILES)
+
+    def test_link_records_the_directory(self):
+        x = next(s for s in self.report.services if s.dir == "x/api")
+        call = x.outbound_calls[0]
+        self.assertEqual(call.service, "api")
+        self.assertEqual((call.service_dir, call.endpoint), ("y/api", "/orders"))
+
+    def test_mermaid_edge_between_distinct_subgraphs(self):
+        text = render_mermaid(self.report)
+        self.assertIn('subgraph svc_0["api"]', text)
+        self.assertIn('subgraph svc_1["api"]', text)
+        self.assertIn('svc_0 -->|"/orders"| svc_1', text)
+        self.assertNotIn("svc_1 -->", text)
+
+    def test_dot_edge_to_the_called_endpoint(self):
+        text = render_dot(self.report)
+        self.assertIn('"svc0" -> "svc1_ep_0"', text)
+        self.assertEqual(text.count(" -> "), 1)
+
+    def test_unique_names_migrate_to_directories(self):
+        data = self.report.to_dict()
+        data["schema_version"] = 9
+        for svc in data["services"]:
+            svc["name"] = svc["dir"].replace("/", "-")
+            for call in svc["outbound_calls"]:
+                call["service"] = "y-api"
+                del call["service_dir"]
+        loaded = Report.from_data(data)
+        self.assertEqual(loaded.services[0].outbound_calls[0].service_dir, "y/api")
+
+
+if __name__ == "__main__":
+    unittest.main()
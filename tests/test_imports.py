@@ -0,0 +1,90 @@
+# tests/test_imports.py
+
+import unittest
+
+from crowsight import ScanOptions, scan_fs
+
+FILES = {
+    "go.mod": "module example.com/shop\n\ngo 1.22\n",
+    "internal/db/db.go": "package db\n\nfunc Open() {}\n",
+    "internal/handlers/users.go": """package handlers
+
+import (
+	"net/http"
+
+	"example.com/shop/internal/db"
+	"example.com/shop/internal/service"
+)
+
+func Users(w http.ResponseWriter, r *http.Request) {
+	db.Open()
+	service.List()
+}
+""",
+    "internal/service/service.go": "package service\n\nfunc List() {}\n",
+}
+
+
+def findings(code: str, files, rules=()):
+    report = scan_fs(files, options=ScanOptions(forbid_imports=list(rules)))
+    return [(f.file, f.line, f.message) for f in report.findings if f.code == code]
+
+
+class ForbiddenImportTest(unittest.TestCase):
+    def test_rule_by_directory_and_import_path(self):
+        expected = [
+            (
+                "internal/handlers/users.go",
+                6,
+                "example.com/shop/internal/handlers imports example.com/shop/internal/db, "
+                "which the rule internal/handlers*:internal/db* forbids",
+            )
+        ]
+        rule = "internal/handlers*:internal/db*"
+        self.assertEqual(findings("forbidden-import", FILES, [rule]), expected)
+        by_path = "example.com/shop/internal/handlers:example.com/shop/internal/db"
+        self.assertEqual(len(findings("forbidden-import", FILES, [by_path])), 1)
+
+    def test_allowed_imports(self):
+        self.assertEqual(findings("forbidden-import", FILES), [])
+        rule = "internal/service*:internal/db*"
+        self.assertEqual(findings("forbidden-import", FILES, [rule]), [])
+
+    def test_invalid_rule(self):
+        with self.assertRaises(ValueError):
+            findings("forbidden-import", FILES, ["internal/handlers"])
+
+
+class ImportCycleTest(unittest.TestCase):
+    def test_cycle_reported_once(self):
+        files = dict(FILES)
+        files["internal/db/db.go"] = """package db
+
+import "example.com/shop/internal/service"
+
+func Open() { service.List() }
+"""
+        files["internal/service/service.go"] = """package service
+
+import "example.com/shop/internal/db"
+
+func List() { db.Open() }
+"""
+        self.assertEqual(
+            findings("import-cycle", files),
+            [
+                (
+                    "internal/db/db.go",
+                    3,
+                    "import cycle: example.com/shop/internal/db -> "
+                    "example.com/shop/internal/service -> example.com/shop/internal/db",
+                )
+            ],
+        )
+
+    def test_no_cycle(self):
+        self.assertEqual(findings("import-cycle", FILES), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,83 @@
+# tests/test_jsontags.py
+
+import unittest
+
+from crowsight import scan_fs
+
+MODELS = """package models
+
+type Address struct {
+	City string
+	Zip  string `json:"zip"`
+}
+
+type Meta struct {
+	Version int
+}
+
+type User struct {
+	Meta
+	Name    string `json:"name"`
+	Email   string
+	Skip    string `json:"-"`
+	Home    Address `json:"home"`
+	created int
+}
+
+// Internal is never sent or received.
+type Internal struct {
+	Secret string
+}
+"""
+
+MAIN = """package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"example.com/api/models"
+)
+
+func create(w http.ResponseWriter, r *http.Request) {
+	var u models.User
+	json.NewDecoder(r.Body).Decode(&u)
+}
+
+func main() {
+	http.HandleFunc("POST /users", create)
+}
+"""
+
+
+def missing(models: str = MODELS):
+    report = scan_fs(
+        {
+            "go.mod": "module example.com/api\n\ngo 1.22\n",
+            "models/models.go": models,
+            "main.go": MAIN,
+        }
+    )
+    return [(f.file, f.line, f.message) for f in report.findings if f.code == "missing-json-tag"]
+
+
+class MissingJSONTagTest(unittest.TestCase):
+    def test_untagged_fields_of_dtos_and_nested_structs(self):
+        found = missing()
+        # Embedded Meta's fields are promoted into User's JSON.
+        self.assertEqual([line for _, line, _ in found], [4, 9, 15])
+        self.assertIn("field City of DTO models.Address has no json tag", found[0][2])
+        self.assertIn("field Version of DTO models.Meta", found[1][2])
+        self.assertIn("field Email of DTO models.User", found[2][2])
+
+    def test_tagged_dto_is_fine(self):
+        tagged = (
+            MODELS.replace("\tCity string\n", '\tCity string `json:"city"`\n')
+            .replace("\tVersion int\n", '\tVersion int `json:"version"`\n')
+            .replace("\tEmail   string\n", '\tEmail   string `json:"email"`\n')
+        )
+        self.assertEqual(missing(tagged), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,72 @@
+# tests/test_listen.py
+
+import unittest
+
+from crowsight import scan_fs
+
+GO_MOD = "module example.com/svc\n\ngo 1.22\n"
+
+
+def main_go(body: str, extra: str = "") -> str:
+    return f"""package main
+
+import "net/http"
+
+{extra}
+
+func h(w http.ResponseWriter, r *http.Request) {{}}
+
+func main() {{
+	http.HandleFunc("/", h)
+	{body}
+}}
+"""
+
+
+class ListenAddressTest(unittest.TestCase):
+    def listen(self, body: str, extra: str = ""):
+        report = scan_fs({"go.mod": GO_MOD, "main.go": main_go(body, extra)})
+        self.assertEqual(len(report.services), 1)
+        return report.services[0]
+
+    def test_http_package_call(self):
+        svc = self.listen('http.ListenAndServe(":8080", nil)')
+        self.assertEqual(svc.listen_addr, '":8080"')
+        self.assertEqual((svc.listen_file, svc.listen_line), ("main.go", 11))
+
+    def test_server_literal(self):
+        svc = self.listen('srv := &http.Server{Addr: ":9090"}\n\tsrv.ListenAndServe()')
+        self.assertEqual(svc.listen_addr, '":9090"')
+
+    def test_empty_address_defaults(self):
+        self.assertEqual(self.listen('http.ListenAndServe("", nil)').listen_addr, '":http"')
+        tls = self.listen('srv := &http.Server{}\n\tsrv.ListenAndServeTLS("c.pem", "k.pem")')
+        self.assertEqual(tls.listen_addr, '":https"')
+        tls = self.listen('http.ListenAndServeTLS("", "c.pem", "k.pem", nil)')
+        self.assertEqual(tls.listen_addr, '":https"')
+
+    def test_unqualified_call_is_not_a_server(self):
+        # Used to crash the scan: a bare identifier callee has no operand.
+        svc = self.listen("ListenAndServe()", "func ListenAndServe() error { return nil }")
+        self.assertIsNone(svc.listen_addr)
+
+
+class PortConflictTest(unittest.TestCase):
+    def test_shared_port_is_a_finding(self):
+        report = scan_fs(
+            {
+                "go.mod": GO_MOD,
+                "a/main.go": main_go('http.ListenAndServe(":8080", nil)'),
+                "b/main.go": main_go('http.ListenAndServe("localhost:8080", nil)'),
+                "c/main.go": main_go('http.ListenAndServe(":8081", nil)'),
+            }
+        )
+        conflicts = [f for f in report.findings if f.code == "port-conflict"]
+        self.assertEqual(len(conflicts), 1)
+        self.assertEqual((conflicts[0].file, conflicts[0].line), ("a/main.go", 11))
+        self.assertIn("b at b/main.go:11", conflicts[0].message)
+        self.assertEqual(conflicts[0].analyzer, "ports")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,77 @@
+# tests/test_queries.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def scan(main: str):
+    return scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+
+
+def program(handler: str, decls: str = "") -> str:
+    return f"""package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+var db *sql.DB
+
+{decls}
+
+func orders(w http.ResponseWriter, r *http.Request) {{
+	{handler}
+}}
+
+func main() {{
+	http.HandleFunc("GET /orders", orders)
+}}
+"""
+
+
+def n_plus_one(main: str):
+    return [f for f in scan(main).findings if f.code == "n-plus-one"]
+
+
+class NPlusOneTest(unittest.TestCase):
+    def test_query_in_a_loop(self):
+        handler = """for _, id := range []int{1, 2} {
+		db.QueryRow("SELECT total FROM orders WHERE id = $1", id)
+	}"""
+        (found,) = n_plus_one(program(handler))
+        self.assertEqual(found.line, 14)
+        self.assertIn("GET /orders: QueryRow runs inside a loop", found.message)
+        (ep,) = scan(program(handler)).endpoints
+        (q,) = ep.db_queries
+        self.assertEqual(q.query, "SELECT total FROM orders WHERE id = $1")
+        self.assertTrue(q.in_loop)
+
+    def test_query_through_a_repository(self):
+        decls = """func total(id int) {
+	db.QueryRow("SELECT total FROM orders WHERE id = $1", id)
+}"""
+        handler = "for i := 0; i < 3; i++ {\n\t\ttotal(i)\n\t}"
+        (found,) = n_plus_one(program(handler, decls))
+        self.assertEqual(found.line, 11)
+        self.assertIn("(via total)", found.message)
+
+    def test_single_query_is_fine(self):
+        handler = 'rows, _ := db.Query("SELECT id FROM orders")\n\tdefer rows.Close()'
+        main = program(handler)
+        self.assertEqual(n_plus_one(main), [])
+        (ep,) = scan(main).endpoints
+        self.assertEqual([(q.call, q.in_loop) for q in ep.db_queries], [("Query", False)])
+
+    def test_loop_without_queries_and_nolint(self):
+        loop = "for i := 0; i < 3; i++ {\n\t\tw.Write(nil)\n\t}"
+        self.assertEqual(n_plus_one(program(loop)), [])
+        handler = """for _, id := range []int{1, 2} {
+		db.QueryRow("SELECT 1", id) //nolint:crowsight
+	}"""
+        self.assertEqual(n_plus_one(program(handler)), [])
+
+
+if __name__ == "__main__":
+    unittest.main()
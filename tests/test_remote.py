@@ -0,0 +1,59 @@
+# tests/test_remote.py
+
+from pathlib import Path
+import shutil
+import subprocess
+import tempfile
+import unittest
+
+from crowsight.scanner.changes import GitError
+from crowsight.scanner.remote import checkout, repo_name
+
+
+def git(cwd: Path, *args: str):
+    subprocess.run(
+        ["git", "-c", "user.name=t", "-c", "user.email=t@example.com", *args],
+        cwd=cwd,
+        check=True,
+        capture_output=True,
+    )
+
+
+@unittest.skipUnless(shutil.which("git"), "needs git")
+class CheckoutTest(unittest.TestCase):
+    def setUp(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        self.origin = Path(tmp.name) / "svc"
+        self.origin.mkdir()
+        git(self.origin, "init", "--quiet")
+        (self.origin / "go.mod").write_text("module example.com/svc\n")
+        git(self.origin, "add", "go.mod")
+        git(self.origin, "commit", "--quiet", "-m", "init")
+        git(self.origin, "tag", "v1")
+
+    def test_clone_at_ref(self):
+        with checkout(str(self.origin), "v1") as clone:
+            self.assertEqual(clone.name, "svc")
+            self.assertTrue((clone / "go.mod").is_file())
+        self.assertFalse(clone.exists())
+
+    def test_option_like_arguments_are_refused(self):
+        marker = self.origin / "ran"
+        for url, ref in (
+            (f"--upload-pack=touch {marker}", None),
+            (str(self.origin), f"--upload-pack=touch {marker}"),
+        ):
+            with self.subTest(url=url, ref=ref):
+                with self.assertRaises(GitError):
+                    with checkout(url, ref):
+                        pass
+        self.assertFalse(marker.exists())
+
+    def test_repo_name(self):
+        self.assertEqual(repo_name("https://github.com/org/name.git"), "name")
+        self.assertEqual(repo_name("git@github.com:org/name"), "name")
+
+
+if __name__ == "__main__":
+    unittest.main()
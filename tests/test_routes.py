@@ -0,0 +1,217 @@
+# tests/test_routes.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def scan(main: str):
+    return scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+
+
+def endpoints(main: str):
+    return sorted((ep.method, ep.path, ep.handler) for ep in scan(main).endpoints)
+
+
+def program(imports: str, body: str, decls: str = "") -> str:
+    return f"""package main
+
+import (
+	"net/http"
+	{imports}
+)
+
+func h(w http.ResponseWriter, r *http.Request) {{}}
+
+{decls}
+
+func main() {{
+	{body}
+}}
+"""
+
+
+GIN = '"github.com/gin-gonic/gin"'
+ECHO = '"github.com/labstack/echo/v4"'
+CHI = '"github.com/go-chi/chi/v5"'
+MUX = '"github.com/gorilla/mux"'
+
+
+class RegistrationTest(unittest.TestCase):
+    def test_net_http(self):
+        body = """mux := http.NewServeMux()
+	mux.HandleFunc("/plain", h)
+	mux.Handle("/static/", http.HandlerFunc(h))
+	mux.HandleFunc(itemsPath, func(w http.ResponseWriter, r *http.Request) {})
+	http.HandleFunc("/default", h)
+	http.Get("http://example.com/not-a-route")
+	req.Header.Get("/x")"""
+        decls = 'const itemsPath = "/items"\n\nvar req *http.Request'
+        self.assertEqual(
+            endpoints(program("", body, decls)),
+            [
+                ("ANY", "/default", "h"),
+                ("ANY", "/items", None),
+                ("ANY", "/plain", "h"),
+                ("ANY", "/static/", "h"),
+            ],
+        )
+
+    def test_go122_patterns(self):
+        body = """mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", h)
+	mux.HandleFunc("POST example.com/items", h)
+	mux.Handle("DELETE /files/{path...}", http.HandlerFunc(h))"""
+        report = scan(program("", body))
+        self.assertEqual(
+            sorted((ep.method, ep.path) for ep in report.endpoints),
+            [("DELETE", "/files/{path...}"), ("GET", "/items/{id}"), ("POST", "/items")],
+        )
+        by_path = {ep.path: ep for ep in report.endpoints}
+        self.assertEqual([p.name for p in by_path["/items/{id}"].path_params], ["id"])
+
+    def test_chi(self):
+        body = """r := chi.NewRouter()
+	r.Get("/a", h)
+	r.Post("/a", h)
+	r.Method("PATCH", "/b", http.HandlerFunc(h))
+	r.MethodFunc(http.MethodPut, "/c", h)
+	r.HandleFunc("/d", h)
+	r.With(mw).Delete("/e/{id}", h)"""
+        decls = "func mw(next http.Handler) http.Handler { return next }"
+        self.assertEqual(
+            endpoints(program(CHI, body, decls)),
+            [
+                ("ANY", "/d", "h"),
+                ("DELETE", "/e/{id}", "h"),
+                ("GET", "/a", "h"),
+                ("PATCH", "/b", "h"),
+                ("POST", "/a", "h"),
+                ("PUT", "/c", "h"),
+            ],
+        )
+
+    def test_gin(self):
+        body = """r := gin.Default()
+	r.GET("/a/:id", list)
+	r.POST("/a", auth, list)
+	r.Any("/any", list)
+	r.PUT("/p/*rest", func(c *gin.Context) {})"""
+        decls = "func list(c *gin.Context) {}\n\nfunc auth(c *gin.Context) {}"
+        self.assertEqual(
+            endpoints(program(GIN, body, decls)),
+            [
+                ("ANY", "/any", "list"),
+                ("GET", "/a/:id", "list"),
+                ("POST", "/a", "list"),
+                ("PUT", "/p/*rest", None),
+            ],
+        )
+
+    def test_echo(self):
+        body = """e := echo.New()
+	e.GET("/a/:id", get)
+	e.DELETE("/a/:id", get)"""
+        decls = "func get(c echo.Context) error { return nil }"
+        self.assertEqual(
+            endpoints(program(ECHO, body, decls)),
+            [("DELETE", "/a/:id", "get"), ("GET", "/a/:id", "get")],
+        )
+
+    def test_gorilla_methods(self):
+        body = """r := mux.NewRouter()
+	r.HandleFunc("/a/{id:[0-9]+}", h).Methods("GET", "post")
+	r.Handle("/b", http.HandlerFunc(h)).Methods(http.MethodPut)"""
+        report = scan(program(MUX, body))
+        self.assertEqual(
+            sorted((ep.method, ep.path, ep.handler) for ep in report.endpoints),
+            [("GET", "/a/{id:[0-9]+}", "h"), ("POST", "/a/{id:[0-9]+}", "h"), ("PUT", "/b", "h")],
+        )
+        ep = report.endpoints[0]
+        self.assertEqual([p.name for p in ep.path_params], ["id"])
+        self.assertEqual((ep.file, ep.line, ep.handler_line), ("main.go", 14, 8))
+
+
+class GroupPrefixTest(unittest.TestCase):
+    def test_gin_groups(self):
+        body = """r := gin.New()
+	v1 := r.Group("/v1")
+	{
+		v1.GET("/users", list)
+		admin := v1.Group("/admin", auth)
+		admin.POST("/ban/:id", list)
+		v1.GET("/", list)
+	}
+	r.GET("/health", list)"""
+        decls = "func list(c *gin.Context) {}\n\nfunc auth(c *gin.Context) {}"
+        self.assertEqual(
+            endpoints(program(GIN, body, decls)),
+            [
+                ("GET", "/health", "list"),
+                ("GET", "/v1/", "list"),
+                ("GET", "/v1/users", "list"),
+                ("POST", "/v1/admin/ban/:id", "list"),
+            ],
+        )
+
+    def test_gin_group_params_and_middleware(self):
+        body = """r := gin.New()
+	users := r.Group("/users/:id", auth)
+	users.GET("/posts", list)"""
+        decls = "func list(c *gin.Context) {}\n\nfunc auth(c *gin.Context) {}"
+        (ep,) = scan(program(GIN, body, decls)).endpoints
+        self.assertEqual(ep.path, "/users/:id/posts")
+        self.assertEqual([p.name for p in ep.path_params], ["id"])
+        self.assertEqual(ep.middleware, ["auth"])
+
+    def test_echo_nested_groups(self):
+        body = """e := echo.New()
+	api := e.Group("/api")
+	v2 := api.Group("/v2")
+	v2.GET("/items/:id", get)
+	e.GET(prefix+"/ignored", get)"""
+        decls = "const prefix = \"/x\"\n\nfunc get(c echo.Context) error { return nil }"
+        found = endpoints(program(ECHO, body, decls))
+        self.assertEqual(found, [("GET", "/api/v2/items/:id", "get")])
+
+    def test_chi_route_group_and_mount(self):
+        body = """r := chi.NewRouter()
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/x", h)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h)
+		})
+		r.Group(func(r chi.Router) {
+			r.Post("/y", h)
+		})
+	})
+	r.Mount("/admin", admin())
+	http.ListenAndServe(":8080", r)"""
+        decls = """func admin() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/stats", h)
+	return r
+}"""
+        self.assertEqual(
+            endpoints(program(CHI, body, decls)),
+            [
+                ("GET", "/admin/stats", "h"),
+                ("GET", "/api/x", "h"),
+                ("GET", "/api/{id}", "h"),
+                ("POST", "/api/y", "h"),
+            ],
+        )
+
+    def test_gorilla_subrouter(self):
+        body = """r := mux.NewRouter()
+	s := r.PathPrefix("/api").Subrouter()
+	s.HandleFunc("/users/{id}", h).Methods("GET")
+	r.HandleFunc("/health", h)"""
+        self.assertEqual(
+            endpoints(program(MUX, body)),
+            [("ANY", "/health", "h"), ("GET", "/api/users/{id}", "h")],
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
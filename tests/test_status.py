@@ -0,0 +1,88 @@
+# tests/test_status.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.render.openapi import build_openapi
+
+# (handler signature, route registration)
+NET_HTTP = (
+    "get(w http.ResponseWriter, r *http.Request)",
+    'http.HandleFunc("GET /items/{id}", get)',
+)
+GIN = ("get(c *gin.Context)", 'gin.Default().GET("/items/:id", get)')
+
+
+def scan(handler: str, framework=NET_HTTP):
+    header, route = framework
+    main = f"""package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func {header} {{
+	{handler}
+}}
+
+func main() {{
+	{route}
+}}
+"""
+    return scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main})
+
+
+def codes(handler: str):
+    (ep,) = scan(handler).endpoints
+    return ep.status_codes
+
+
+class StatusCodesTest(unittest.TestCase):
+    def test_implicit_ok(self):
+        self.assertEqual(codes('w.Write([]byte("ok"))'), [200])
+
+    def test_error_paths_and_explicit_status(self):
+        handler = """if r.URL.Query().Get("x") == "" {
+		http.Error(w, "missing x", http.StatusBadRequest)
+		return
+	}
+	if r.PathValue("id") == "0" {
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(nil)"""
+        self.assertEqual(codes(handler), [201, 400, 404])
+
+    def test_body_before_status_is_ok(self):
+        self.assertEqual(codes("w.Write(nil)\n\tw.WriteHeader(202)"), [200, 202])
+
+    def test_framework_calls(self):
+        handler = """if c.Query("x") == "" {
+		c.AbortWithStatus(401)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{})"""
+        (ep,) = scan(handler, GIN).endpoints
+        self.assertEqual(ep.status_codes, [200, 401])
+
+    def test_plain_numbers_outside_status_calls_are_ignored(self):
+        self.assertEqual(codes("n := 404\n\t_ = n\n\tw.Write(nil)"), [200])
+
+    def test_openapi_responses(self):
+        handler = """if r.PathValue("id") == "0" {
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)"""
+        doc = build_openapi(scan(handler))
+        responses = doc["paths"]["/items/{id}"]["get"]["responses"]
+        self.assertEqual(
+            responses, {"204": {"description": "No Content"}, "404": {"description": "Not Found"}}
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
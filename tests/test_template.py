@@ -0,0 +1,91 @@
+# tests/test_template.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.render.template import TemplateError, render_template
+
+FILES = {
+    "go.mod": "module example.com/m\n\ngo 1.22\n",
+    "api/main.go": """package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type User struct {
+	Name string `json:"name"`
+}
+
+func users(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(User{})
+}
+
+func health(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("/users", users)
+	http.HandleFunc("/healthz", health)
+	http.ListenAndServe(":8080", nil)
+}
+""",
+}
+
+
+class TemplateTest(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        cls.report = scan_fs(FILES)
+
+    def render(self, source: str) -> str:
+        return render_template(self.report, source)
+
+    def test_fields_and_json_names(self):
+        out = self.render("{{range .Services}}{{.Name}} {{.listen_addr}}{{end}}")
+        self.assertEqual(out, "api \":8080\"")
+
+    def test_range_pipelines_and_variables(self):
+        source = (
+            "{{range $i, $ep := .Endpoints}}{{if $i}}, {{end}}"
+            "{{$ep.Path | upper}}{{end}}"
+        )
+        self.assertEqual(self.render(source), "/HEALTHZ, /USERS")
+
+    def test_trim_markers_and_comments(self):
+        out = self.render("a {{- /* gone */ -}} b {{- len .Endpoints}}")
+        self.assertEqual(out, "ab2")
+
+    def test_functions(self):
+        out = self.render('{{range groupBy "Method" .Endpoints}}{{.Key}}={{len .Items}}{{end}}')
+        self.assertEqual(out, "ANY=2")
+        self.assertEqual(self.render('{{.Services | len | printf "%03d"}}'), "001")
+        self.assertEqual(self.render('{{"" | default "none"}}'), "none")
+
+    def test_helpers(self):
+        self.assertEqual(self.render('{{with .FindStruct "User"}}{{.Name}}{{end}}'), "User")
+        self.assertEqual(self.render('{{(.ServiceByName "api").Dir}}'), "api")
+        self.assertEqual(self.render('{{len (.EndpointsByMethod "any")}}'), "2")
+
+    def test_live_objects_are_not_reachable(self):
+        for source in (
+            '{{.Scanner.Fs.ReadText "/etc/hostname"}}',
+            "{{len .Scanner.Scan.Services}}",
+            "{{.Lock}}",
+            "{{.ToJson}}",
+            "{{.Summarize}}",
+            '{{.UpdateFiles "x" "y"}}',
+        ):
+            with self.subTest(source=source):
+                with self.assertRaises(TemplateError) as ctx:
+                    self.render(source)
+                self.assertIn("can't evaluate field", str(ctx.exception))
+
+    def test_errors_say_where(self):
+        with self.assertRaises(TemplateError) as ctx:
+            self.render("line\n{{.Nope}}")
+        self.assertIn("template: template:2:3:", str(ctx.exception))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,91 @@
+# tests/test_tls.py
+
+import unittest
+
+from crowsight import scan_fs
+
+
+def findings(code: str, main: str, path: str = "cmd/api/main.go"):
+    report = scan_fs({"go.mod": "module example.com/api\n\ngo 1.22\n", path: main})
+    return [f for f in report.findings if f.code == code]
+
+
+def program(body: str, decls: str = "", imports: str = '"net/http"') -> str:
+    return f"""package main
+
+import (
+	{imports}
+)
+
+{decls}
+
+func main() {{
+	{body}
+}}
+"""
+
+
+class PlaintextServerTest(unittest.TestCase):
+    def plaintext(self, body: str, decls: str = "", imports: str = '"net/http"'):
+        return findings("plaintext-server", program(body, decls, imports))
+
+    def test_package_function(self):
+        found = self.plaintext('http.ListenAndServe(":8080", nil)')
+        self.assertEqual([f.line for f in found], [10])
+        self.assertIn("http.ListenAndServe serves plain HTTP", found[0].message)
+
+    def test_aliased_import(self):
+        found = self.plaintext('nethttp.ListenAndServe(":8080", nil)', imports='nethttp "net/http"')
+        self.assertEqual(len(found), 1)
+
+    def test_server_literal(self):
+        found = self.plaintext('srv := &http.Server{Addr: ":8080"}\n\tsrv.ListenAndServe()')
+        self.assertEqual([f.line for f in found], [10])
+
+    def test_server_parameter_and_field(self):
+        decls = """type app struct{ srv *http.Server }
+
+func run(srv *http.Server) { srv.ListenAndServe() }
+
+func (a *app) start() { a.srv.ListenAndServe() }"""
+        self.assertEqual([f.line for f in self.plaintext("run(nil)", decls)], [9, 11])
+
+    def test_loopback_and_tls_config_are_fine(self):
+        self.assertEqual(self.plaintext('http.ListenAndServe("localhost:8080", nil)'), [])
+        body = 'srv := &http.Server{Addr: ":8080", TLSConfig: nil}\n\tsrv.ListenAndServe()'
+        self.assertEqual(self.plaintext(body), [])
+
+    def test_other_functions_of_that_name(self):
+        local = "func ListenAndServe() error { return nil }"
+        self.assertEqual(self.plaintext("ListenAndServe()", local), [])
+        custom = """type grpcServer struct{}
+
+func (grpcServer) ListenAndServe() error { return nil }"""
+        self.assertEqual(self.plaintext("s := grpcServer{}\n\ts.ListenAndServe()", custom), [])
+        unknown = "func newServer() interface{ ListenAndServe() error } { return nil }"
+        self.assertEqual(self.plaintext("newServer().ListenAndServe()", unknown), [])
+
+    def test_tls_anywhere_in_the_package(self):
+        body = 'go http.ListenAndServe(":80", nil)\n\thttp.ListenAndServeTLS(":443", "c", "k", nil)'
+        self.assertEqual(self.plaintext(body), [])
+
+    def test_non_production_directories(self):
+        main = program('http.ListenAndServe(":8080", nil)')
+        self.assertEqual(findings("plaintext-server", main, "examples/demo/main.go"), [])
+
+
+class TLSConfigTest(unittest.TestCase):
+    def test_skip_verify_and_weak_versions(self):
+        main = program(
+            "_ = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10}\n"
+            "\tcfg := &tls.Config{}\n\tcfg.MinVersion = tls.VersionTLS13\n\t_ = cfg",
+            imports='"crypto/tls"',
+        )
+        self.assertEqual(len(findings("insecure-skip-verify", main)), 1)
+        weak = findings("weak-tls-version", main)
+        self.assertEqual(len(weak), 1)
+        self.assertIn("tls.VersionTLS10", weak[0].message)
+
+
+if __name__ == "__main__":
+    unittest.main()
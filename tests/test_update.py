@@ -0,0 +1,154 @@
+# tests/test_update.py
+
+import os
+from pathlib import Path
+import shutil
+import tempfile
+import unittest
+
+from crowsight.scanner.scanner import scan
+
+FILES = {
+    "go.mod": "module example.com/shop\n\ngo 1.22\n",
+    "models/user.go": """package models
+
+type User struct {
+	Name string `json:"name"`
+}
+""",
+    "api/main.go": """package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"example.com/shop/models"
+)
+
+func users(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(models.User{})
+}
+
+func main() {
+	http.HandleFunc("GET /users", users)
+	registerExtra()
+	http.ListenAndServe(":8080", nil)
+}
+""",
+    "api/extra.go": """package main
+
+import (
+	"io/ioutil"
+	"net/http"
+)
+
+// TODO: move to its own service
+func extra(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Write(body)
+}
+
+func registerExtra() {
+	http.HandleFunc("POST /extra", extra)
+}
+""",
+    "worker/main.go": """package main
+
+import "net/http"
+
+func main() {
+	http.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {})
+	http.ListenAndServe(":9090", nil)
+}
+""",
+}
+
+
+def data(report):
+    d = report.to_dict()
+    d.pop("metrics", None)
+    return d
+
+
+class UpdateFilesTest(unittest.TestCase):
+    """`update_files` must leave the report exactly as a full rescan would."""
+
+    def setUp(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        self.root = Path(tmp.name) / "shop"
+        for rel, text in FILES.items():
+            path = self.root / rel
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(text)
+        self.report = scan(str(self.root))
+
+    def update(self, changed=(), deleted=()):
+        before = data(self.report)
+        self.report.update_files(str(self.root), list(changed), list(deleted))
+        full = scan(str(self.root))
+        self.assertNotEqual(data(self.report), before)
+        self.assertEqual(data(self.report), data(full))
+        self.assertEqual(self.report.content_hash(), full.content_hash())
+
+    def test_edit_a_dependency(self):
+        user = self.root / "models/user.go"
+        user.write_text(user.read_text().replace("}", '\tEmail string `json:"email"`\n}'))
+        self.update(changed=["models/user.go"])
+        (st,) = [s for s in self.report.services[0].structs if s.name == "User"]
+        self.assertEqual([f.name for f in st.fields], ["Name", "Email"])
+
+    def test_delete_a_file_with_endpoints_and_findings(self):
+        codes = {f.code for f in self.report.findings if f.file == "api/extra.go"}
+        self.assertIn("deprecated-call", codes)
+        main = self.root / "api/main.go"
+        main.write_text(main.read_text().replace("\tregisterExtra()\n", ""))
+        (self.root / "api/extra.go").unlink()
+        self.update(changed=["api/main.go"], deleted=["api/extra.go"])
+        self.assertNotIn("/extra", [ep.path for ep in self.report.endpoints])
+        self.assertFalse(any(f.file == "api/extra.go" for f in self.report.findings))
+
+    def test_add_a_file(self):
+        (self.root / "worker/health.go").write_text(
+            """package main
+
+import "net/http"
+
+func init() {
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+}
+"""
+        )
+        self.update(changed=["worker/health.go"])
+        self.assertIn("/healthz", [ep.path for ep in self.report.endpoints])
+
+    def test_several_updates(self):
+        self.test_edit_a_dependency()
+        self.test_add_a_file()
+        (self.root / "api/extra.go").unlink()
+        main = self.root / "api/main.go"
+        main.write_text(main.read_text().replace("\tregisterExtra()\n", ""))
+        self.update(changed=["api/main.go"], deleted=[str(self.root / "api/extra.go")])
+
+
+class ContentHashTest(unittest.TestCase):
+    def test_same_tree_from_two_roots(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        first = Path(tmp.name) / "a" / "shop"
+        for rel, text in FILES.items():
+            path = first / rel
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(text)
+        # A dangling symlink fails to read, and OSError quotes the absolute path.
+        os.symlink("missing.go", first / "api/broken.go")
+        second = Path(tmp.name) / "b" / "elsewhere"
+        shutil.copytree(first, second, symlinks=True)
+        one, two = scan(str(first)), scan(str(second))
+        self.assertEqual([e.file for e in one.errors], ["api/broken.go"])
+        self.assertNotIn(str(first), one.errors[0].message)
+        self.assertEqual(one.content_hash(), two.content_hash())
+
+
+if __name__ == "__main__":
+    unittest.main()
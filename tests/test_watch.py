@@ -0,0 +1,118 @@
+# tests/test_watch.py
+
+from pathlib import Path
+import queue
+import shutil
+import sys
+import tempfile
+import threading
+import time
+import unittest
+
+from crowsight.scanner.scanner import GoScanner
+from crowsight.scanner.watch import InotifyMonitor, PollingMonitor, Watcher
+
+MAIN = """package main
+
+import "net/http"
+
+func h(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("/a", h)
+}
+"""
+
+
+class MonitorTest(unittest.TestCase):
+    monitor_class = PollingMonitor
+
+    def setUp(self):
+        self.root = Path(tempfile.mkdtemp(prefix="crowsight-test-"))
+        self.addCleanup(shutil.rmtree, self.root, True)
+        (self.root / "go.mod").write_text("module example.com/w\n\ngo 1.22\n")
+        (self.root / "api").mkdir()
+        (self.root / "api" / "main.go").write_text(MAIN)
+        (self.root / "README.md").write_text("hello\n")
+        self.stop = threading.Event()
+        self.monitor = self.monitor_class(GoScanner(str(self.root)))
+        self.addCleanup(self.monitor.close)
+
+    def changes(self):
+        # Bumps the mtime for filesystems with coarse timestamps.
+        time.sleep(0.02)
+        return self.monitor.changes(0.3, self.stop)
+
+    def touch(self, path: Path, text: str):
+        path.write_text(text)
+
+    def test_quiet_tree(self):
+        self.assertEqual(self.monitor.changes(0.1, self.stop), set())
+
+    def test_edited_go_file(self):
+        self.touch(self.root / "api" / "main.go", MAIN + "\n// edited\n")
+        self.assertIn(self.root / "api" / "main.go", self.changes())
+
+    def test_new_package(self):
+        (self.root / "users").mkdir()
+        self.touch(self.root / "users" / "main.go", MAIN)
+        changed = self.changes()
+        self.assertTrue(changed & {self.root / "users", self.root / "users" / "main.go"})
+
+    def test_go_mod_and_config(self):
+        self.touch(self.root / "go.mod", "module example.com/other\n")
+        self.assertIn(self.root / "go.mod", self.changes())
+        self.touch(self.root / "crowsight.yaml", "max_complexity: 3\n")
+        self.assertIn(self.root / "crowsight.yaml", self.changes())
+
+    def test_unrelated_files_are_ignored(self):
+        self.touch(self.root / "README.md", "changed\n")
+        self.assertEqual(self.changes(), set())
+
+    def test_stop(self):
+        self.stop.set()
+        self.assertIsNone(self.monitor.changes(0.1, self.stop))
+
+
+@unittest.skipUnless(sys.platform.startswith("linux"), "inotify is Linux-only")
+class InotifyMonitorTest(MonitorTest):
+    monitor_class = InotifyMonitor
+
+
+class WatcherTest(unittest.TestCase):
+    def test_rescans_and_reloads_config(self):
+        root = Path(tempfile.mkdtemp(prefix="crowsight-test-"))
+        self.addCleanup(shutil.rmtree, root, True)
+        (root / "go.mod").write_text("module example.com/w\n\ngo 1.22\n")
+        (root / "main.go").write_text(MAIN)
+        reloads = []
+
+        def reload():
+            reloads.append(True)
+            return GoScanner(str(root))
+
+        reports: "queue.Queue" = queue.Queue()
+        stop = threading.Event()
+        watcher = Watcher(GoScanner(str(root)), debounce=0.05, interval=0.05, reload=reload)
+        thread = threading.Thread(target=watcher.run, args=(lambda r, d: reports.put((r, d)), stop))
+        thread.start()
+        self.addCleanup(thread.join)
+        self.addCleanup(stop.set)
+
+        report, changes = reports.get(timeout=10)
+        self.assertIsNone(changes)
+        self.assertEqual([ep.path for ep in report.endpoints], ["/a"])
+
+        (root / "main.go").write_text(MAIN.replace('"/a"', '"/b"'))
+        report, changes = reports.get(timeout=10)
+        self.assertEqual([ep.path for ep in report.endpoints], ["/b"])
+        self.assertFalse(changes.empty)
+        self.assertEqual(reloads, [])
+
+        (root / "crowsight.yaml").write_text("max_complexity: 3\n")
+        reports.get(timeout=10)
+        self.assertEqual(reloads, [True])
+
+
+if __name__ == "__main__":
+    unittest.main()
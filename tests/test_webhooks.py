@@ -0,0 +1,53 @@
+# tests/test_webhooks.py
+
+import unittest
+
+from crowsight import ScanOptions, scan_fs
+
+
+def webhooks(handler: str, route: str = "/webhooks/stripe", decls: str = "", options=None):
+    main = f"""package main
+
+import "net/http"
+
+{decls}
+
+func stripe(w http.ResponseWriter, r *http.Request) {{
+	{handler}
+}}
+
+func main() {{
+	http.HandleFunc("POST {route}", stripe)
+}}
+"""
+    files = {"go.mod": "module example.com/api\n\ngo 1.22\n", "main.go": main}
+    report = scan_fs(files, options=options)
+    return [(f.line, f.message) for f in report.findings if f.code == "webhook-idempotency"]
+
+
+class WebhookIdempotencyTest(unittest.TestCase):
+    def test_no_idempotency_handling(self):
+        ((line, message),) = webhooks("w.WriteHeader(http.StatusOK)")
+        self.assertEqual(line, 7)
+        self.assertIn("webhook handler stripe (POST /webhooks/stripe)", message)
+
+    def test_delivery_id_or_dedup_helper(self):
+        self.assertEqual(webhooks('_ = r.Header.Get("Idempotency-Key")'), [])
+        decls = "func alreadyProcessed(id string) bool { return false }"
+        self.assertEqual(webhooks('if alreadyProcessed("x") { return }', decls=decls), [])
+        helper = 'func record(r *http.Request) { _ = r.Header.Get("X-GitHub-Delivery") }'
+        self.assertEqual(webhooks("record(r)", decls=helper), [])
+
+    def test_comments_do_not_count(self):
+        found = webhooks("// TODO: dedup by event id\n\tw.WriteHeader(http.StatusOK)")
+        self.assertEqual(len(found), 1)
+
+    def test_other_endpoints_and_patterns(self):
+        self.assertEqual(webhooks("w.WriteHeader(http.StatusOK)", route="/payments"), [])
+        options = ScanOptions(webhook_patterns=["/hooks/*"])
+        found = webhooks("w.WriteHeader(http.StatusOK)", route="/hooks/github", options=options)
+        self.assertEqual([line for line, _ in found], [7])
+
+
+if __name__ == "__main__":
+    unittest.main()
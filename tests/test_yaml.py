@@ -0,0 +1,83 @@
+# tests/test_yaml.py
+
+import unittest
+
+from crowsight import scan_fs
+from crowsight.report import yaml
+from crowsight.report.models import Report
+
+
+class YAMLTest(unittest.TestCase):
+    def test_round_trip(self):
+        data = {
+            "plain": "x",
+            "null": None,
+            "flag": True,
+            "yes": "yes",
+            "int": 3,
+            "float": 1.5,
+            "digits": "3",
+            "text": "line1\nline2\n",
+            "empty": [],
+            "none": {},
+            "nested": [{"k": 1, "v": [1, 2]}, "s"],
+            "colon": "a: b",
+            "hash": "# x",
+            "space": " lead",
+        }
+        text = yaml.dumps(data)
+        self.assertIn('"yes": "yes"', text)
+        self.assertIn("text: |\n  line1\n  line2\n", text)
+        self.assertEqual(yaml.loads(text), data)
+
+    def test_hand_written(self):
+        text = "# top\na: 'it''s'  # note\nb:\n- 1\n- two\nc: ~\n"
+        self.assertEqual(yaml.loads(text), {"a": "it's", "b": [1, "two"], "c": None})
+
+    def test_flow_lists(self):
+        text = 'exclude: [vendor, "a, b", 3]\nenable: []\n'
+        self.assertEqual(yaml.loads(text), {"exclude": ["vendor", "a, b", 3], "enable": []})
+
+    def test_unsupported(self):
+        cases = {
+            'a: "x': "unsupported double-quoted string",
+            "a: &anchor 1\nb: *anchor": "unsupported YAML syntax '&anchor 1'",
+            "a: [[1]]": "nested flow collections are not supported",
+            "a: [1 2": "unsupported YAML syntax '[1 2'",
+            "description: >\n  folded\n  text\n": "unsupported YAML syntax '>'",
+            "description: >-\n  folded\n": "unsupported YAML syntax '>-'",
+            "schema: {$ref: '#/x'}": "unsupported YAML syntax",
+            "- {name: id, in: path}": "unsupported YAML syntax",
+            "a: !!str 1": "unsupported YAML syntax",
+            "---\na: 1\n": "line 1: document markers are not supported",
+            "a: 1\n---\nb: 2\n": "line 2: document markers are not supported",
+            "? a\n: b\n": "complex keys are not supported",
+            "a:\n\tb: 1\n": "line 2: tabs cannot indent YAML",
+            "a: b: c": "a plain value cannot contain ': '",
+            "a: [k: v]": "a plain value cannot contain ': '",
+            'a: "x" extra': "unexpected 'extra' after a string",
+        }
+        for text, message in cases.items():
+            with self.subTest(text=text):
+                with self.assertRaises(yaml.YAMLError) as ctx:
+                    yaml.loads(text)
+                self.assertIn(message, str(ctx.exception))
+
+    def test_block_scalars_keep_their_content(self):
+        text = "a: |\n  ---\n  \tb: c\n"
+        self.assertEqual(yaml.loads(text), {"a": "---\n\tb: c\n"})
+
+    def test_report_round_trip(self):
+        report = scan_fs(
+            {
+                "go.mod": "module example.com/m\n\ngo 1.22\n",
+                "main.go": 'package main\n\nimport "net/http"\n\nfunc main() {\n'
+                '\thttp.HandleFunc("/x", nil)\n}\n',
+            }
+        )
+        loaded = Report.from_yaml(report.to_yaml())
+        self.assertEqual(loaded.to_dict(), report.to_dict())
+
+
+if __name__ == "__main__":
+    unittest.main()